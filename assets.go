@@ -0,0 +1,626 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default maximum size for a single asset upload (25 MB)
+const defaultMaxUploadSize = 25 << 20
+
+// Lifetime of signed asset URLs when none is requested, and the longest allowed
+const (
+	defaultSignedURLExpiry = time.Hour
+	maxSignedURLExpiry     = 7 * 24 * time.Hour
+)
+
+// allowedAssetTypes lists the MIME types accepted for upload. The type is
+// sniffed from the file contents rather than trusted from the client.
+var allowedAssetTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"image/bmp":       true,
+	"application/pdf": true,
+	"text/plain":      true,
+	"text/csv":        true,
+	"audio/mpeg":      true,
+	"audio/wave":      true,
+	"audio/ogg":       true,
+	"video/mp4":       true,
+	"video/webm":      true,
+	"application/zip": true,
+}
+
+type Asset struct {
+	ID        int
+	Hash      string
+	Filename  string
+	MimeType  string
+	Size      int64
+	Private   bool
+	CreatedBy sql.NullInt64
+	CreatedAt time.Time
+}
+
+// Asset Management
+func (d *Database) CreateAsset(hash, filename, mimeType string, size int64, private bool, createdBy int) (*Asset, error) {
+	query := `INSERT INTO assets (hash, filename, mime_type, size, private, created_by) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := d.db.Exec(query, hash, filename, mimeType, size, private, createdBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create asset: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset ID: %w", err)
+	}
+
+	return d.GetAsset(int(id))
+}
+
+func (d *Database) GetAsset(id int) (*Asset, error) {
+	query := `
+		SELECT id, hash, filename, mime_type, size, private, created_by, created_at
+		FROM assets WHERE id = ?
+	`
+
+	var asset Asset
+	err := d.db.QueryRow(query, id).Scan(
+		&asset.ID,
+		&asset.Hash,
+		&asset.Filename,
+		&asset.MimeType,
+		&asset.Size,
+		&asset.Private,
+		&asset.CreatedBy,
+		&asset.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset: %w", err)
+	}
+
+	return &asset, nil
+}
+
+func (d *Database) GetAssets() ([]Asset, error) {
+	query := `
+		SELECT id, hash, filename, mime_type, size, private, created_by, created_at
+		FROM assets
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []Asset
+	for rows.Next() {
+		var asset Asset
+		err := rows.Scan(
+			&asset.ID,
+			&asset.Hash,
+			&asset.Filename,
+			&asset.MimeType,
+			&asset.Size,
+			&asset.Private,
+			&asset.CreatedBy,
+			&asset.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assets: %w", err)
+	}
+
+	if assets == nil {
+		assets = []Asset{}
+	}
+
+	return assets, nil
+}
+
+// DeleteAsset removes the asset record and reports whether any other asset
+// still references the same content hash.
+func (d *Database) DeleteAsset(id int) (*Asset, bool, error) {
+	asset, err := d.GetAsset(id)
+	if err != nil {
+		return nil, false, err
+	}
+	if asset == nil {
+		return nil, false, fmt.Errorf("asset not found")
+	}
+
+	if _, err := d.db.Exec(`DELETE FROM assets WHERE id = ?`, id); err != nil {
+		return nil, false, fmt.Errorf("failed to delete asset: %w", err)
+	}
+
+	var remaining int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM assets WHERE hash = ?`, asset.Hash).Scan(&remaining); err != nil {
+		return nil, false, fmt.Errorf("failed to count asset references: %w", err)
+	}
+
+	return asset, remaining > 0, nil
+}
+
+// AssetResponse represents an asset for JSON API responses
+type AssetResponse struct {
+	ID        int    `json:"id"`
+	Filename  string `json:"filename"`
+	MimeType  string `json:"mimeType"`
+	Size      int64  `json:"size"`
+	Private   bool   `json:"private"`
+	URL       string `json:"url"`
+	CreatedAt string `json:"createdAt"`
+}
+
+func convertAssetToResponse(asset *Asset) AssetResponse {
+	return AssetResponse{
+		ID:        asset.ID,
+		Filename:  asset.Filename,
+		MimeType:  asset.MimeType,
+		Size:      asset.Size,
+		Private:   asset.Private,
+		URL:       fmt.Sprintf("/assets/%d", asset.ID),
+		CreatedAt: asset.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// assetKey returns the storage key for a content hash. Files are sharded by
+// the first two hex characters to keep directories small.
+func assetKey(hash string) string {
+	return "assets/" + hash[:2] + "/" + hash
+}
+
+// storeAssetFile spools the upload to a temporary file while hashing it, then
+// writes it to storage under its content-addressed key.
+func (s *Server) storeAssetFile(src io.Reader, mimeType string) (string, int64, error) {
+	dir := filepath.Join(s.dataDir, "tmp")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", 0, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "upload-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), src)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to write upload: %w", err)
+	}
+
+	hash := hex.EncodeToString(hasher.Sum(nil))
+	key := assetKey(hash)
+
+	// Identical content is already stored; nothing to upload
+	exists, err := s.storage.Exists(key)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to check for existing asset: %w", err)
+	}
+	if exists {
+		return hash, size, nil
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	if err := s.storage.Put(key, tmp, size, mimeType); err != nil {
+		return "", 0, fmt.Errorf("failed to store asset: %w", err)
+	}
+
+	return hash, size, nil
+}
+
+// signAssetURL returns a time-limited link to /assets/{id} that works for
+// private assets.
+func (s *Server) signAssetURL(assetID int, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return fmt.Sprintf("/assets/%d?expires=%s&signature=%s", assetID, expires, s.assetSignature(assetID, expires))
+}
+
+// AssetSigningKey returns the key signed asset links are made with. A random
+// key is generated and saved in the settings the first time, so links stay
+// valid across restarts.
+func (d *Database) AssetSigningKey() ([]byte, error) {
+	return d.signingKey("asset_signing_key")
+}
+
+// signingKey returns the random key stored in setting, generating and saving
+// it first if there isn't one yet.
+func (d *Database) signingKey(setting string) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate %s: %w", setting, err)
+	}
+	_, err := d.db.Exec(`
+		INSERT INTO settings (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (key) DO NOTHING
+	`, setting, hex.EncodeToString(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save %s: %w", setting, err)
+	}
+
+	value, err := d.GetSetting(setting)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(value)
+}
+
+func (s *Server) assetSignature(assetID int, expires string) string {
+	mac := hmac.New(sha256.New, s.assetKey)
+	fmt.Fprintf(mac, "asset:%d:%s", assetID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyAssetSignature checks the expires and signature query parameters and
+// returns when the link stops being valid.
+func (s *Server) verifyAssetSignature(assetID int, query url.Values) (time.Time, bool) {
+	expires := query.Get("expires")
+	signature := query.Get("signature")
+	if expires == "" || signature == "" {
+		return time.Time{}, false
+	}
+
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	expiresAt := time.Unix(expiresUnix, 0)
+	if time.Now().After(expiresAt) {
+		return time.Time{}, false
+	}
+
+	expected := s.assetSignature(assetID, expires)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return time.Time{}, false
+	}
+	return expiresAt, true
+}
+
+// sniffContentType detects the MIME type from the first 512 bytes of the file
+func sniffContentType(r io.ReadSeeker) (string, error) {
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	mimeType := http.DetectContentType(buf[:n])
+	// Strip parameters such as "; charset=utf-8"
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:i])
+	}
+	return mimeType, nil
+}
+
+func (s *Server) handleAdminAssets(w http.ResponseWriter, r *http.Request) {
+	// Validate JWT token
+	username, err := s.validateJWTToken(r)
+	if err != nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := s.db.GetUserByUsername(username)
+	if err != nil || user == nil {
+		s.sendJSONError(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	// Parse URL: /admin-api/assets or /admin-api/assets/{id}
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin-api/assets"), "/")
+
+	if path == "" {
+		switch r.Method {
+		case http.MethodGet:
+			assets, err := s.db.GetAssets()
+			if err != nil {
+				log.Printf("Error getting assets: %v", err)
+				s.sendJSONError(w, "Failed to fetch assets", http.StatusInternalServerError)
+				return
+			}
+
+			response := []AssetResponse{}
+			for i := range assets {
+				response = append(response, convertAssetToResponse(&assets[i]))
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+
+		case http.MethodPost:
+			// Limit the whole request body; multipart overhead is small
+			r.Body = http.MaxBytesReader(w, r.Body, s.maxUploadSize+(1<<20))
+			if err := r.ParseMultipartForm(10 << 20); err != nil {
+				s.sendJSONError(w, fmt.Sprintf("Upload exceeds maximum size of %d bytes", s.maxUploadSize), http.StatusRequestEntityTooLarge)
+				return
+			}
+			defer r.MultipartForm.RemoveAll()
+
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				s.sendJSONError(w, "Failed to get file from form", http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+
+			if header.Size > s.maxUploadSize {
+				s.sendJSONError(w, fmt.Sprintf("Upload exceeds maximum size of %d bytes", s.maxUploadSize), http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			mimeType, err := sniffContentType(file)
+			if err != nil {
+				s.sendJSONError(w, "Failed to read uploaded file", http.StatusBadRequest)
+				return
+			}
+			if !allowedAssetTypes[mimeType] {
+				s.sendJSONError(w, fmt.Sprintf("File type %s is not allowed", mimeType), http.StatusUnsupportedMediaType)
+				return
+			}
+
+			hash, size, err := s.storeAssetFile(file, mimeType)
+			if err != nil {
+				log.Printf("Error storing asset: %v", err)
+				s.sendJSONError(w, "Failed to store file", http.StatusInternalServerError)
+				return
+			}
+
+			filename := filepath.Base(header.Filename)
+			if filename == "." || filename == "/" {
+				filename = hash
+			}
+
+			private := r.FormValue("private") == "true"
+
+			asset, err := s.db.CreateAsset(hash, filename, mimeType, size, private, user.ID)
+			if err != nil {
+				log.Printf("Error creating asset: %v", err)
+				s.sendJSONError(w, "Failed to create asset", http.StatusInternalServerError)
+				return
+			}
+
+			s.audit(r, user, "asset.create", "asset", asset.ID, nil, convertAssetToResponse(asset))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(convertAssetToResponse(asset))
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	assetID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		s.sendJSONError(w, "Invalid asset ID", http.StatusBadRequest)
+		return
+	}
+
+	if len(parts) == 2 && parts[1] == "url" {
+		s.handleAdminAssetURL(w, r, assetID)
+		return
+	} else if len(parts) > 1 {
+		s.sendJSONError(w, "Invalid assets endpoint", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		asset, err := s.db.GetAsset(assetID)
+		if err != nil {
+			log.Printf("Error getting asset %d: %v", assetID, err)
+			s.sendJSONError(w, "Failed to get asset", http.StatusInternalServerError)
+			return
+		}
+		if asset == nil {
+			s.sendJSONError(w, "Asset not found", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(convertAssetToResponse(asset))
+
+	case http.MethodDelete:
+		asset, stillReferenced, err := s.db.DeleteAsset(assetID)
+		if err != nil {
+			if err.Error() == "asset not found" {
+				s.sendJSONError(w, "Asset not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error deleting asset %d: %v", assetID, err)
+			s.sendJSONError(w, "Failed to delete asset", http.StatusInternalServerError)
+			return
+		}
+
+		s.audit(r, user, "asset.delete", "asset", assetID, convertAssetToResponse(asset), nil)
+
+		// Only remove the file once no asset record points at its content
+		if !stillReferenced {
+			if err := s.storage.Delete(assetKey(asset.Hash)); err != nil {
+				log.Printf("Warning: Failed to remove asset file %s: %v", asset.Hash, err)
+			}
+			if err := os.RemoveAll(s.derivedImageDir(asset.Hash)); err != nil {
+				log.Printf("Warning: Failed to remove cached images for %s: %v", asset.Hash, err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Asset deleted successfully"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAdminAssetURL returns a signed download link, valid for the number of
+// seconds given by the expires query parameter.
+// GET /admin-api/assets/{id}/url?expires=3600
+func (s *Server) handleAdminAssetURL(w http.ResponseWriter, r *http.Request, assetID int) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	expiry := defaultSignedURLExpiry
+	if expiresStr := r.URL.Query().Get("expires"); expiresStr != "" {
+		seconds, err := strconv.Atoi(expiresStr)
+		if err != nil || seconds <= 0 || time.Duration(seconds)*time.Second > maxSignedURLExpiry {
+			s.sendJSONError(w, fmt.Sprintf("expires must be between 1 and %d seconds", int(maxSignedURLExpiry.Seconds())), http.StatusBadRequest)
+			return
+		}
+		expiry = time.Duration(seconds) * time.Second
+	}
+
+	asset, err := s.db.GetAsset(assetID)
+	if err != nil {
+		log.Printf("Error getting asset %d: %v", assetID, err)
+		s.sendJSONError(w, "Failed to get asset", http.StatusInternalServerError)
+		return
+	}
+	if asset == nil {
+		s.sendJSONError(w, "Asset not found", http.StatusNotFound)
+		return
+	}
+
+	expiresAt := time.Now().Add(expiry)
+
+	// Prefer a direct link to the storage backend when it supports one
+	var signedURL string
+	if signer, ok := s.storage.(URLSigner); ok {
+		signedURL, err = signer.SignedURL(assetKey(asset.Hash), expiry)
+		if err != nil {
+			log.Printf("Error signing URL for asset %d: %v", assetID, err)
+			s.sendJSONError(w, "Failed to sign URL", http.StatusInternalServerError)
+			return
+		}
+	} else {
+		signedURL = s.signAssetURL(asset.ID, expiresAt)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"url":       signedURL,
+		"expiresAt": expiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// handleAssets serves uploaded files at /assets/{id}. Private assets require
+// a signed link from handleAdminAssetURL.
+func (s *Server) handleAssets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	assetID, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/assets/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	asset, err := s.db.GetAsset(assetID)
+	if err != nil {
+		log.Printf("Error getting asset %d: %v", assetID, err)
+		http.Error(w, "Failed to get asset", http.StatusInternalServerError)
+		return
+	}
+	if asset == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Asset contents never change for a given ID, so public assets can be
+	// cached forever. Private ones may only be cached until the link expires.
+	cacheControl := "public, max-age=31536000, immutable"
+	if asset.Private {
+		expiresAt, ok := s.verifyAssetSignature(asset.ID, r.URL.Query())
+		if !ok {
+			http.Error(w, "Invalid or expired signature", http.StatusForbidden)
+			return
+		}
+		cacheControl = fmt.Sprintf("private, max-age=%d", int(time.Until(expiresAt).Seconds()))
+	}
+
+	if hasImageTransformParams(r.URL.Query()) {
+		s.serveTransformedAsset(w, r, asset, cacheControl)
+		return
+	}
+
+	etag := `"` + asset.Hash + `"`
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	file, err := s.storage.Get(assetKey(asset.Hash))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Asset file missing for asset %d", assetID)
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("Error opening asset file for asset %d: %v", assetID, err)
+		http.Error(w, "Failed to read asset", http.StatusBadGateway)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", asset.MimeType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", asset.Filename))
+
+	// Local files support range requests; remote streams are copied as-is
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, asset.Filename, asset.CreatedAt, seeker)
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.FormatInt(asset.Size, 10))
+	w.Header().Set("Last-Modified", asset.CreatedAt.UTC().Format(http.TimeFormat))
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := io.Copy(w, file); err != nil {
+		log.Printf("Error streaming asset %d: %v", assetID, err)
+	}
+}
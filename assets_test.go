@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestDatabase opens a fresh database in a temporary directory
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	db, err := NewDatabase(filepath.Join(t.TempDir(), "lodge.db"))
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestAssetSigningKeyIsPersisted(t *testing.T) {
+	db := newTestDatabase(t)
+
+	first, err := db.AssetSigningKey()
+	if err != nil {
+		t.Fatalf("AssetSigningKey: %v", err)
+	}
+	if len(first) != 32 {
+		t.Fatalf("key is %d bytes, want 32", len(first))
+	}
+	second, err := db.AssetSigningKey()
+	if err != nil {
+		t.Fatalf("AssetSigningKey: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("AssetSigningKey returned a different key the second time")
+	}
+
+	other, err := newTestDatabase(t).AssetSigningKey()
+	if err != nil {
+		t.Fatalf("AssetSigningKey: %v", err)
+	}
+	if bytes.Equal(first, other) {
+		t.Error("two databases were given the same key")
+	}
+}
+
+func TestVerifyAssetSignature(t *testing.T) {
+	s := &Server{assetKey: []byte("test key")}
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	link, err := url.Parse(s.signAssetURL(42, expiresAt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := link.Query()
+
+	tamper := func(name, value string) url.Values {
+		query := url.Values{}
+		for k, v := range valid {
+			query[k] = v
+		}
+		query.Set(name, value)
+		return query
+	}
+
+	tests := []struct {
+		name    string
+		assetID int
+		query   url.Values
+		want    bool
+	}{
+		{"valid link", 42, valid, true},
+		{"other asset", 43, valid, false},
+		{"extended expiry", 42, tamper("expires", strconv.FormatInt(expiresAt.Add(time.Hour).Unix(), 10)), false},
+		{"expired", 42, tamper("expires", "1"), false},
+		{"bad signature", 42, tamper("signature", strings.Repeat("0", 64)), false},
+		{"no signature", 42, url.Values{"expires": valid["expires"]}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := s.verifyAssetSignature(tt.assetID, tt.query)
+			if ok != tt.want {
+				t.Fatalf("verifyAssetSignature = %t, want %t", ok, tt.want)
+			}
+			if ok && !got.Equal(expiresAt) {
+				t.Errorf("expires at %v, want %v", got, expiresAt)
+			}
+		})
+	}
+
+	// A different key doesn't accept the link
+	if _, ok := (&Server{assetKey: []byte("other key")}).verifyAssetSignature(42, valid); ok {
+		t.Error("link signed with one key was accepted with another")
+	}
+}
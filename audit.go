@@ -0,0 +1,370 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultAuditRetention is how long audit log entries are kept before
+	// they are purged automatically
+	defaultAuditRetention = 365 * 24 * time.Hour
+
+	// auditPurgeInterval is how often expired audit log entries are purged
+	auditPurgeInterval = time.Hour
+
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+// AuditEntry records one administrative action: who did it, to what, and the
+// target's state before and after
+type AuditEntry struct {
+	ID         int
+	UserID     sql.NullInt64
+	Username   sql.NullString // Kept so entries outlive the user
+	Action     string
+	TargetType string
+	TargetID   sql.NullString
+	Before     sql.NullString // JSON snapshot
+	After      sql.NullString // JSON snapshot
+	IP         sql.NullString
+	CreatedAt  time.Time
+}
+
+// AuditFilter narrows down a search of the audit log. Zero values match
+// everything.
+type AuditFilter struct {
+	Username   string
+	Action     string // An exact action, or a prefix ending in "." such as "item."
+	TargetType string
+	TargetID   string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	Offset     int
+}
+
+// RecordAudit appends an entry to the audit log
+func (d *Database) RecordAudit(entry *AuditEntry) error {
+	_, err := d.db.Exec(`
+		INSERT INTO audit_log (user_id, username, action, target_type, target_id, before, after, ip)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.UserID, entry.Username, entry.Action, entry.TargetType, entry.TargetID, entry.Before, entry.After, entry.IP)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// GetAuditLog returns the entries matching filter, newest first, along with
+// how many match in total
+func (d *Database) GetAuditLog(filter AuditFilter) ([]AuditEntry, int, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Username != "" {
+		conditions = append(conditions, "username = ?")
+		args = append(args, filter.Username)
+	}
+	if strings.HasSuffix(filter.Action, ".") {
+		conditions = append(conditions, "substr(action, 1, ?) = ?")
+		args = append(args, len(filter.Action), filter.Action)
+	} else if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.TargetType != "" {
+		conditions = append(conditions, "target_type = ?")
+		args = append(args, filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, filter.TargetID)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since.UTC().Format(scheduleTimeLayout))
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.Until.UTC().Format(scheduleTimeLayout))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM audit_log `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	query := `
+		SELECT id, user_id, username, action, target_type, target_id, before, after, ip, created_at
+		FROM audit_log ` + where + `
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := d.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var e AuditEntry
+		err := rows.Scan(&e.ID, &e.UserID, &e.Username, &e.Action, &e.TargetType, &e.TargetID, &e.Before, &e.After, &e.IP, &e.CreatedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating audit entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// PurgeAuditLog deletes audit entries recorded before the given time and
+// returns how many were deleted
+func (d *Database) PurgeAuditLog(before time.Time) (int, error) {
+	result, err := d.db.Exec(`DELETE FROM audit_log WHERE created_at < ?`, before.UTC().Format(scheduleTimeLayout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge audit log: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(purged), nil
+}
+
+// requestIP returns the address a request came from
+func requestIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// auditSnapshot encodes the state of a target for the audit log. A nil value
+// means there is no such state, e.g. before something was created.
+func auditSnapshot(value interface{}) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	snapshot, err := json.Marshal(value)
+	if err != nil || string(snapshot) == "null" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(snapshot), Valid: true}
+}
+
+// auditUser is the audit snapshot of a user, leaving out their password
+func auditUser(u *User) interface{} {
+	if u == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"id":       u.ID,
+		"username": u.Username,
+		"email":    u.Email.String,
+		"role":     u.Role,
+	}
+}
+
+// auditItem is the audit snapshot of an item
+func auditItem(item *Item) interface{} {
+	if item == nil {
+		return nil
+	}
+	return convertItemToResponse(item)
+}
+
+// auditAPIKey is the audit snapshot of an API key. Only the key's prefix is
+// kept, never the key itself.
+func auditAPIKey(key *APIKey) interface{} {
+	if key == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"id":        key.ID,
+		"name":      key.Name,
+		"keyPrefix": key.KeyPrefix,
+		"isActive":  key.IsActive,
+	}
+}
+
+// audit records an action done by user through request r. Failures are
+// logged rather than failing the request, since the action has already
+// happened by the time it is recorded.
+func (s *Server) audit(r *http.Request, user *User, action, targetType string, targetID interface{}, before, after interface{}) {
+	entry := &AuditEntry{
+		Action:     action,
+		TargetType: targetType,
+		Before:     auditSnapshot(before),
+		After:      auditSnapshot(after),
+	}
+	if user != nil {
+		entry.UserID = sql.NullInt64{Int64: int64(user.ID), Valid: true}
+		entry.Username = sql.NullString{String: user.Username, Valid: true}
+	}
+	if targetID != nil {
+		entry.TargetID = sql.NullString{String: fmt.Sprint(targetID), Valid: true}
+	}
+	if r != nil {
+		entry.IP = sql.NullString{String: requestIP(r), Valid: true}
+	}
+
+	if err := s.db.RecordAudit(entry); err != nil {
+		log.Printf("Error recording %s of %s %v: %v", action, targetType, targetID, err)
+	}
+}
+
+// runAuditPurge removes audit entries older than the server's retention
+// period at startup and then every interval
+func (s *Server) runAuditPurge(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.db.PurgeAuditLog(time.Now().Add(-s.auditRetention))
+		if err != nil {
+			log.Printf("Error purging audit log: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d entries from the audit log", purged)
+		}
+
+		<-ticker.C
+	}
+}
+
+func auditEntryResponse(e *AuditEntry) map[string]interface{} {
+	response := map[string]interface{}{
+		"id":         e.ID,
+		"userId":     nil,
+		"username":   nil,
+		"action":     e.Action,
+		"targetType": e.TargetType,
+		"targetId":   nil,
+		"before":     nil,
+		"after":      nil,
+		"ip":         nil,
+		"createdAt":  e.CreatedAt.Format(time.RFC3339),
+	}
+	if e.UserID.Valid {
+		response["userId"] = e.UserID.Int64
+	}
+	if e.Username.Valid {
+		response["username"] = e.Username.String
+	}
+	if e.TargetID.Valid {
+		response["targetId"] = e.TargetID.String
+	}
+	if e.Before.Valid {
+		response["before"] = json.RawMessage(e.Before.String)
+	}
+	if e.After.Valid {
+		response["after"] = json.RawMessage(e.After.String)
+	}
+	if e.IP.Valid {
+		response["ip"] = e.IP.String
+	}
+	return response
+}
+
+// handleAdminAudit serves the audit log to admins:
+//
+//	GET /admin-api/audit?user=alice&action=item.&targetType=item&targetId=12&since=...&until=...&limit=50&offset=0
+//
+// since and until are RFC 3339 timestamps. An action ending in "." matches
+// every action starting with it.
+func (s *Server) handleAdminAudit(w http.ResponseWriter, r *http.Request) {
+	username, err := s.validateJWTToken(r)
+	if err != nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := s.db.GetUserByUsername(username)
+	if err != nil || user == nil {
+		s.sendJSONError(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if user.Role != "admin" {
+		s.sendJSONError(w, "Only admins can view the audit log", http.StatusForbidden)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := AuditFilter{
+		Username:   query.Get("user"),
+		Action:     query.Get("action"),
+		TargetType: query.Get("targetType"),
+		TargetID:   query.Get("targetId"),
+		Limit:      defaultAuditPageSize,
+	}
+
+	for name, t := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				s.sendJSONError(w, fmt.Sprintf("%s must be an RFC 3339 timestamp", name), http.StatusBadRequest)
+				return
+			}
+			*t = parsed
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxAuditPageSize {
+			s.sendJSONError(w, fmt.Sprintf("limit must be between 1 and %d", maxAuditPageSize), http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			s.sendJSONError(w, "offset must be a non-negative number", http.StatusBadRequest)
+			return
+		}
+		filter.Offset = offset
+	}
+
+	entries, total, err := s.db.GetAuditLog(filter)
+	if err != nil {
+		log.Printf("Error getting audit log: %v", err)
+		s.sendJSONError(w, "Failed to get audit log", http.StatusInternalServerError)
+		return
+	}
+
+	response := []map[string]interface{}{}
+	for i := range entries {
+		response = append(response, auditEntryResponse(&entries[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"entries": response,
+		"total":   total,
+	})
+}
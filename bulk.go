@@ -0,0 +1,534 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// maxBulkItems is the most items a single bulk operation may touch
+const maxBulkItems = 1000
+
+var (
+	// errBulkItemsFailed is returned when some items of a bulk operation
+	// can't be changed and the operation was rolled back
+	errBulkItemsFailed = errors.New("some items cannot be changed")
+
+	// errTooManyBulkItems is returned when a filter matches more than
+	// maxBulkItems items
+	errTooManyBulkItems = errors.New("too many items")
+)
+
+// BulkOperation is a change applied to many items at once. Items are chosen
+// by ID or by a filter; Action is one of "status", "delete", "move" or "set".
+type BulkOperation struct {
+	IDs    []int       `json:"ids"`
+	Filter *BulkFilter `json:"filter"`
+	Action string      `json:"action"`
+
+	Status       string      `json:"status"`       // status: the new status
+	Comment      string      `json:"comment"`      // status: recorded with workflow transitions
+	CollectionID int         `json:"collectionId"` // move: the target collection
+	Field        string      `json:"field"`        // set: the field to change
+	Value        interface{} `json:"value"`        // set: the new value, or null to clear it
+}
+
+// BulkFilter selects the items of a collection, optionally with a status
+type BulkFilter struct {
+	CollectionID int    `json:"collectionId"`
+	Status       string `json:"status"`
+}
+
+// BulkOptions controls how a bulk operation handles failures
+type BulkOptions struct {
+	DryRun     bool // Report what would happen without writing anything
+	SkipFailed bool // Apply the items that can be changed instead of failing
+}
+
+// BulkItemResult is the outcome of a bulk operation for one item
+type BulkItemResult struct {
+	ID    int    `json:"id"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// BulkReport summarises a bulk operation
+type BulkReport struct {
+	Action    string           `json:"action"`
+	Matched   int              `json:"matched"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+	DryRun    bool             `json:"dryRun"`
+	Applied   bool             `json:"applied"`
+}
+
+// validate checks a bulk operation before any item is looked at
+func (op *BulkOperation) validate() error {
+	if len(op.IDs) > 0 && op.Filter != nil {
+		return fmt.Errorf("Give either ids or filter, not both")
+	}
+	if len(op.IDs) == 0 && op.Filter == nil {
+		return fmt.Errorf("ids or filter is required")
+	}
+	if op.Filter != nil && op.Filter.CollectionID == 0 {
+		return fmt.Errorf("filter.collectionId is required")
+	}
+	if len(op.IDs) > maxBulkItems {
+		return fmt.Errorf("A bulk operation can change at most %d items", maxBulkItems)
+	}
+
+	switch op.Action {
+	case "status":
+		if op.Status == "" {
+			return fmt.Errorf("status is required")
+		}
+	case "delete":
+	case "move":
+		if op.CollectionID == 0 {
+			return fmt.Errorf("collectionId is required")
+		}
+	case "set":
+		if op.Field == "" {
+			return fmt.Errorf("field is required")
+		}
+	case "":
+		return fmt.Errorf("action is required")
+	default:
+		return fmt.Errorf("Unknown action %q; use status, delete, move or set", op.Action)
+	}
+	return nil
+}
+
+// bulkItem is the part of an item a bulk operation works with
+type bulkItem struct {
+	id, collectionID int
+	data             map[string]interface{}
+	status           string
+}
+
+// bulkField is the part of a field a bulk operation works with
+type bulkField struct {
+	fieldType   string
+	required    bool
+	localizable bool
+}
+
+// bulkState caches per-collection lookups while a bulk operation runs
+type bulkState struct {
+	tx        *sql.Tx
+	workflows map[int]*Workflow
+	fields    map[int]map[string]bulkField
+}
+
+func (b *bulkState) workflow(collectionID int) (*Workflow, error) {
+	wf, ok := b.workflows[collectionID]
+	if !ok {
+		var err error
+		if wf, err = getCollectionWorkflow(b.tx, collectionID); err != nil {
+			return nil, err
+		}
+		b.workflows[collectionID] = wf
+	}
+	return wf, nil
+}
+
+// collectionFields returns every field of a collection by name
+func (b *bulkState) collectionFields(collectionID int) (map[string]bulkField, error) {
+	if fields, ok := b.fields[collectionID]; ok {
+		return fields, nil
+	}
+
+	rows, err := b.tx.Query(`SELECT name, type, required, localizable FROM collection_fields WHERE collection_id = ?`, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := make(map[string]bulkField)
+	for rows.Next() {
+		var name string
+		var field bulkField
+		if err := rows.Scan(&name, &field.fieldType, &field.required, &field.localizable); err != nil {
+			return nil, fmt.Errorf("failed to scan collection field: %w", err)
+		}
+		fields[name] = field
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating collection fields: %w", err)
+	}
+
+	b.fields[collectionID] = fields
+	return fields, nil
+}
+
+// bulkItemIDs returns the items an operation applies to
+func bulkItemIDs(tx *sql.Tx, op *BulkOperation) ([]int, error) {
+	if op.Filter == nil {
+		return op.IDs, nil
+	}
+
+	query := `SELECT id FROM items WHERE collection_id = ? AND deleted_at IS NULL AND (? = '' OR status = ?) ORDER BY id LIMIT ?`
+	rows, err := tx.Query(query, op.Filter.CollectionID, op.Filter.Status, op.Filter.Status, maxBulkItems+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find items: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan item: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating items: %w", err)
+	}
+	if len(ids) > maxBulkItems {
+		return nil, errTooManyBulkItems
+	}
+	return ids, nil
+}
+
+// BulkUpdateItems applies op to every selected item as user, in a single
+// transaction. Each item either succeeds or fails with a reason; if any fail
+// nothing is saved and errBulkItemsFailed is returned, unless
+// opts.SkipFailed is set.
+func (d *Database) BulkUpdateItems(op *BulkOperation, user *User, opts BulkOptions) (*BulkReport, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := bulkItemIDs(tx, op)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bulkState{tx: tx, workflows: make(map[int]*Workflow), fields: make(map[int]map[string]bulkField)}
+	report := &BulkReport{Action: op.Action, Results: []BulkItemResult{}, DryRun: opts.DryRun}
+	seen := make(map[int]bool)
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		problem, err := b.apply(op, id, user)
+		if err != nil {
+			return nil, err
+		}
+		result := BulkItemResult{ID: id, OK: problem == "", Error: problem}
+		if result.OK {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+	report.Matched = len(report.Results)
+
+	if opts.DryRun {
+		return report, nil
+	}
+	if report.Failed > 0 && !opts.SkipFailed {
+		return report, errBulkItemsFailed
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit bulk operation: %w", err)
+	}
+	report.Applied = true
+	return report, nil
+}
+
+// apply changes one item. It returns a reason if the item can't be changed,
+// or an error if the whole operation has to stop.
+func (b *bulkState) apply(op *BulkOperation, id int, user *User) (string, error) {
+	var item bulkItem
+	var data string
+	err := b.tx.QueryRow(`
+		SELECT id, collection_id, data, status FROM items
+		WHERE id = ? AND deleted_at IS NULL
+		AND collection_id IN (SELECT id FROM collections WHERE deleted_at IS NULL)
+	`, id).Scan(&item.id, &item.collectionID, &data, &item.status)
+	if err == sql.ErrNoRows {
+		return "Item not found", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get item %d: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(data), &item.data); err != nil || item.data == nil {
+		item.data = make(map[string]interface{})
+	}
+
+	var problem string
+	switch op.Action {
+	case "status":
+		problem, err = b.setStatus(&item, op.Status, op.Comment, user)
+	case "delete":
+		_, err = b.tx.Exec(`UPDATE items SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, id)
+		if err != nil {
+			err = fmt.Errorf("failed to delete item %d: %w", id, err)
+		}
+	case "move":
+		problem, err = b.move(&item, op.CollectionID, user)
+	case "set":
+		problem, err = b.setField(&item, op.Field, op.Value, user)
+	}
+	return problem, err
+}
+
+// setStatus changes an item's status, through a transition if its collection
+// has a workflow
+func (b *bulkState) setStatus(item *bulkItem, to, comment string, user *User) (string, error) {
+	if item.status == to {
+		return "", nil
+	}
+
+	wf, err := b.workflow(item.collectionID)
+	if err != nil {
+		return "", err
+	}
+	if wf != nil {
+		t := wf.transition(item.status, to)
+		if t == nil || !t.allows(user.Role) {
+			return fmt.Sprintf("The workflow does not allow moving from %q to %q", item.status, to), nil
+		}
+	}
+
+	_, err = b.tx.Exec(`UPDATE items SET status = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, to, item.id)
+	if err != nil {
+		return "", fmt.Errorf("failed to update item %d: %w", item.id, err)
+	}
+	if wf != nil {
+		if err := recordItemTransition(b.tx, item.id, item.status, to, comment, user.ID); err != nil {
+			return "", err
+		}
+	}
+	return "", recordItemRevision(b.tx, item.id, user.ID)
+}
+
+// move puts an item in another collection. Every value the item has must
+// belong to a field of the same name and type in the target collection, and
+// every required field of the target must have a value. Translated values of
+// fields that aren't localizable in the target are dropped.
+func (b *bulkState) move(item *bulkItem, targetID int, user *User) (string, error) {
+	if item.collectionID == targetID {
+		return "", nil
+	}
+
+	sourceFields, err := b.collectionFields(item.collectionID)
+	if err != nil {
+		return "", err
+	}
+	targetFields, err := b.collectionFields(targetID)
+	if err != nil {
+		return "", err
+	}
+
+	for name, value := range item.data {
+		if value == nil {
+			continue
+		}
+		target, ok := targetFields[name]
+		if !ok {
+			return fmt.Sprintf("The target collection has no field named '%s'", name), nil
+		}
+		if source, ok := sourceFields[name]; ok && source.fieldType != target.fieldType {
+			return fmt.Sprintf("Field '%s' is %s here but %s in the target collection", name, source.fieldType, target.fieldType), nil
+		}
+	}
+	for name, target := range targetFields {
+		if value := item.data[name]; target.required && (value == nil || value == "") {
+			return fmt.Sprintf("Field '%s' is required in the target collection but has no value", name), nil
+		}
+	}
+
+	wf, err := b.workflow(targetID)
+	if err != nil {
+		return "", err
+	}
+	if wf != nil && !wf.hasState(item.status) {
+		return fmt.Sprintf("Status %q is not a state of the target collection's workflow", item.status), nil
+	}
+
+	_, err = b.tx.Exec(`UPDATE items SET collection_id = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, targetID, item.id)
+	if err != nil {
+		return "", fmt.Errorf("failed to move item %d: %w", item.id, err)
+	}
+	if err := b.dropUnlocalizedTranslations(item.id, targetFields); err != nil {
+		return "", err
+	}
+	if err := recordItemRevision(b.tx, item.id, user.ID); err != nil {
+		return "", err
+	}
+	return "", indexItem(b.tx, item.id)
+}
+
+// dropUnlocalizedTranslations removes the translated values of an item that
+// don't belong to a localizable field of fields, and any translation left
+// empty
+func (b *bulkState) dropUnlocalizedTranslations(itemID int, fields map[string]bulkField) error {
+	rows, err := b.tx.Query(`SELECT locale, data FROM item_translations WHERE item_id = ?`, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to get translations of item %d: %w", itemID, err)
+	}
+	translations := make(map[string]map[string]interface{})
+	for rows.Next() {
+		var locale, data string
+		if err := rows.Scan(&locale, &data); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan translation: %w", err)
+		}
+		values := make(map[string]interface{})
+		json.Unmarshal([]byte(data), &values)
+		translations[locale] = values
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("error iterating translations: %w", err)
+	}
+	rows.Close()
+
+	for locale, values := range translations {
+		changed := false
+		for name := range values {
+			if !fields[name].localizable {
+				delete(values, name)
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		if len(values) == 0 {
+			_, err = b.tx.Exec(`DELETE FROM item_translations WHERE item_id = ? AND locale = ?`, itemID, locale)
+		} else {
+			data, err := json.Marshal(values)
+			if err != nil {
+				return fmt.Errorf("failed to encode translation of item %d: %w", itemID, err)
+			}
+			_, err = b.tx.Exec(`UPDATE item_translations SET data = ?, updated_at = CURRENT_TIMESTAMP WHERE item_id = ? AND locale = ?`, string(data), itemID, locale)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update translation of item %d: %w", itemID, err)
+		}
+	}
+	return nil
+}
+
+// setField sets one field of an item, converting the value to the field's type
+func (b *bulkState) setField(item *bulkItem, name string, value interface{}, user *User) (string, error) {
+	fields, err := b.collectionFields(item.collectionID)
+	if err != nil {
+		return "", err
+	}
+	field, ok := fields[name]
+	if !ok {
+		return fmt.Sprintf("The item's collection has no field named '%s'", name), nil
+	}
+	fieldType := field.fieldType
+
+	converted, err := convertFieldValue(value, fieldType, fieldType)
+	if err != nil {
+		return fmt.Sprintf("Invalid value for %s field '%s': %v", fieldType, name, err), nil
+	}
+	if converted == nil {
+		delete(item.data, name)
+	} else {
+		item.data[name] = converted
+	}
+
+	dataJSON, err := json.Marshal(item.data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode item %d: %w", item.id, err)
+	}
+	_, err = b.tx.Exec(`UPDATE items SET data = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, string(dataJSON), item.id)
+	if err != nil {
+		return "", fmt.Errorf("failed to update item %d: %w", item.id, err)
+	}
+	if err := recordItemRevision(b.tx, item.id, user.ID); err != nil {
+		return "", err
+	}
+	return "", indexItem(b.tx, item.id)
+}
+
+// handleAdminItemsBulk serves POST /admin-api/items/bulk. ?dryRun=true
+// reports the result without saving; ?skipFailed=true saves the items that
+// can be changed even if others can't.
+func (s *Server) handleAdminItemsBulk(w http.ResponseWriter, r *http.Request, user *User) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var op BulkOperation
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&op); err != nil {
+		s.sendJSONError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := op.validate(); err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if op.Action == "move" {
+		target, err := s.db.GetCollectionByID(op.CollectionID)
+		if err != nil {
+			log.Printf("Error getting collection %d: %v", op.CollectionID, err)
+			s.sendJSONError(w, "Failed to get collection", http.StatusInternalServerError)
+			return
+		}
+		if target == nil {
+			s.sendJSONError(w, "Target collection not found", http.StatusNotFound)
+			return
+		}
+		if target.Singleton {
+			s.sendJSONError(w, "Items can't be moved into a singleton collection", http.StatusBadRequest)
+			return
+		}
+	}
+
+	opts := BulkOptions{
+		DryRun:     r.URL.Query().Get("dryRun") == "true",
+		SkipFailed: r.URL.Query().Get("skipFailed") == "true",
+	}
+
+	report, err := s.db.BulkUpdateItems(&op, user, opts)
+	if err == errTooManyBulkItems {
+		s.sendJSONError(w, fmt.Sprintf("The filter matches more than %d items; narrow it down", maxBulkItems), http.StatusBadRequest)
+		return
+	}
+	if err == errBulkItemsFailed {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":  fmt.Sprintf("%d of %d item(s) cannot be changed, so nothing was saved; fix them or retry with skipFailed=true", report.Failed, report.Matched),
+			"report": report,
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("Error running bulk %s: %v", op.Action, err)
+		s.sendJSONError(w, "Failed to update items", http.StatusInternalServerError)
+		return
+	}
+
+	if report.Applied {
+		s.audit(r, user, "item.bulk_"+op.Action, "item", nil, nil, map[string]interface{}{"operation": op, "report": report})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
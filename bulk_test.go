@@ -0,0 +1,190 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// bulkFixture is a database with two posts, good and bad, and a pages
+// collection to move them to. Only good has the title pages requires, and
+// its French translation has a summary, which isn't localizable in pages.
+type bulkFixture struct {
+	db           *Database
+	user         *User
+	posts, pages *Collection
+	good, bad    *Item
+}
+
+func newBulkFixture(t *testing.T) *bulkFixture {
+	t.Helper()
+	f := &bulkFixture{db: newTestDatabase(t)}
+	var err error
+
+	if err := f.db.CreateUser("editor", "password", "editor@example.com", "admin"); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if f.user, err = f.db.GetUserByUsername("editor"); err != nil {
+		t.Fatalf("GetUserByUsername: %v", err)
+	}
+
+	if f.posts, err = f.db.CreateCollection("Posts", "posts", "", false, ""); err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+	if f.pages, err = f.db.CreateCollection("Pages", "pages", "", false, ""); err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+	fields := []struct {
+		collection            *Collection
+		name, fieldType       string
+		required, localizable bool
+	}{
+		{f.posts, "title", "text", false, true},
+		{f.posts, "summary", "text", false, true},
+		{f.posts, "views", "number", false, false},
+		{f.pages, "title", "text", true, true},
+		{f.pages, "summary", "text", false, false},
+		{f.pages, "views", "number", false, false},
+	}
+	for i, field := range fields {
+		if _, err := f.db.CreateCollectionField(field.collection.ID, field.name, field.name, field.fieldType, field.required, false, field.localizable, "", "", i); err != nil {
+			t.Fatalf("CreateCollectionField: %v", err)
+		}
+	}
+
+	if f.good, err = f.db.CreateItem(f.posts.ID, "good", `{"title":"Good","summary":"Short","views":3}`, "draft", f.user.ID, nil); err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	if f.bad, err = f.db.CreateItem(f.posts.ID, "bad", `{"views":"many"}`, "draft", f.user.ID, nil); err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	if err := f.db.SetItemTranslation(f.good.ID, "fr", map[string]interface{}{"title": "Bon", "summary": "Court"}, 0); err != nil {
+		t.Fatalf("SetItemTranslation: %v", err)
+	}
+	return f
+}
+
+func TestBulkUpdateItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      func(f *bulkFixture) BulkOperation
+		opts    BulkOptions
+		wantErr error
+		wantOK  []bool // By item, in the order given
+		applied bool
+		check   func(t *testing.T, f *bulkFixture)
+	}{
+		{
+			name: "all items succeed",
+			op: func(f *bulkFixture) BulkOperation {
+				return BulkOperation{Filter: &BulkFilter{CollectionID: f.posts.ID}, Action: "status", Status: "published"}
+			},
+			wantOK:  []bool{true, true},
+			applied: true,
+			check: func(t *testing.T, f *bulkFixture) {
+				expectItem(t, f.db, f.good.ID, f.posts.ID, "published", 3)
+				expectItem(t, f.db, f.bad.ID, f.posts.ID, "published", 2)
+			},
+		},
+		{
+			name: "dry run",
+			op: func(f *bulkFixture) BulkOperation {
+				return BulkOperation{IDs: []int{f.good.ID, f.bad.ID}, Action: "status", Status: "published"}
+			},
+			opts:   BulkOptions{DryRun: true},
+			wantOK: []bool{true, true},
+			check: func(t *testing.T, f *bulkFixture) {
+				expectItem(t, f.db, f.good.ID, f.posts.ID, "draft", 2)
+				expectItem(t, f.db, f.bad.ID, f.posts.ID, "draft", 1)
+			},
+		},
+		{
+			name: "a failure rolls everything back",
+			op: func(f *bulkFixture) BulkOperation {
+				return BulkOperation{IDs: []int{f.good.ID, 999}, Action: "set", Field: "views", Value: 10.0}
+			},
+			wantErr: errBulkItemsFailed,
+			wantOK:  []bool{true, false},
+			check: func(t *testing.T, f *bulkFixture) {
+				expectItem(t, f.db, f.good.ID, f.posts.ID, "draft", 2)
+			},
+		},
+		{
+			name: "required field missing in the target",
+			op: func(f *bulkFixture) BulkOperation {
+				return BulkOperation{IDs: []int{f.good.ID, f.bad.ID}, Action: "move", CollectionID: f.pages.ID}
+			},
+			wantErr: errBulkItemsFailed,
+			wantOK:  []bool{true, false},
+			check: func(t *testing.T, f *bulkFixture) {
+				expectItem(t, f.db, f.good.ID, f.posts.ID, "draft", 2)
+				expectItem(t, f.db, f.bad.ID, f.posts.ID, "draft", 1)
+			},
+		},
+		{
+			name: "skipFailed applies the items that can be moved",
+			op: func(f *bulkFixture) BulkOperation {
+				return BulkOperation{IDs: []int{f.good.ID, f.bad.ID}, Action: "move", CollectionID: f.pages.ID}
+			},
+			opts:    BulkOptions{SkipFailed: true},
+			wantOK:  []bool{true, false},
+			applied: true,
+			check: func(t *testing.T, f *bulkFixture) {
+				expectItem(t, f.db, f.good.ID, f.pages.ID, "draft", 3)
+				expectItem(t, f.db, f.bad.ID, f.posts.ID, "draft", 1)
+
+				translations, err := f.db.GetItemTranslations(f.good.ID)
+				if err != nil {
+					t.Fatalf("GetItemTranslations: %v", err)
+				}
+				want := map[string]map[string]interface{}{"fr": {"title": "Bon"}}
+				if !reflect.DeepEqual(translations, want) {
+					t.Errorf("translations = %v, want %v", translations, want)
+				}
+
+				revisions, err := f.db.GetItemRevisions(f.good.ID)
+				if err != nil {
+					t.Fatalf("GetItemRevisions: %v", err)
+				}
+				item, _ := f.db.GetItem(f.good.ID)
+				if len(revisions) == 0 || revisions[0].Data != item.Data {
+					t.Errorf("latest revision doesn't match the moved item")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBulkFixture(t)
+			op := tt.op(f)
+			report, err := f.db.BulkUpdateItems(&op, f.user, tt.opts)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			ok := []bool{}
+			for _, result := range report.Results {
+				ok = append(ok, result.OK)
+			}
+			if !reflect.DeepEqual(ok, tt.wantOK) {
+				t.Errorf("results = %+v, want ok %v", report.Results, tt.wantOK)
+			}
+			if report.Applied != tt.applied || report.DryRun != tt.opts.DryRun {
+				t.Errorf("applied = %t, dryRun = %t, want %t, %t", report.Applied, report.DryRun, tt.applied, tt.opts.DryRun)
+			}
+			tt.check(t, f)
+		})
+	}
+}
+
+func expectItem(t *testing.T, db *Database, id, collectionID int, status string, version int) {
+	t.Helper()
+	item, err := db.GetItem(id)
+	if err != nil || item == nil {
+		t.Fatalf("GetItem(%d): %v", id, err)
+	}
+	if item.CollectionID != collectionID || item.Status != status || item.Version != version {
+		t.Errorf("item %d is in collection %d, %s, version %d; want %d, %s, version %d",
+			id, item.CollectionID, item.Status, item.Version, collectionID, status, version)
+	}
+}
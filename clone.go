@@ -0,0 +1,320 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// uniqueItemSlug returns base, or base with a number appended if another item
+// of the collection already uses it
+func uniqueItemSlug(tx *sql.Tx, collectionID int, base string) (string, error) {
+	slug := base
+	for n := 2; ; n++ {
+		var taken bool
+		err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM items WHERE collection_id = ? AND slug = ? AND deleted_at IS NULL)`, collectionID, slug).Scan(&taken)
+		if err != nil {
+			return "", fmt.Errorf("failed to check item slug: %w", err)
+		}
+		if !taken {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// cloneStatus is the status copies start in: the workflow's initial state,
+// or draft
+func cloneStatus(tx *sql.Tx, collectionID int) (string, error) {
+	wf, err := getCollectionWorkflow(tx, collectionID)
+	if err != nil {
+		return "", err
+	}
+	if wf != nil {
+		return wf.initialState(), nil
+	}
+	return "draft", nil
+}
+
+// CloneItem copies an item within its collection as a new draft. The copy
+// gets slug, or the original's slug with "-copy" appended, and the values in
+// overrides replace the original's (a nil value removes the field).
+func (d *Database) CloneItem(item *Item, slug string, overrides map[string]interface{}, createdBy int) (*Item, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(item.Data), &data); err != nil || data == nil {
+		data = make(map[string]interface{})
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(data, name)
+		} else {
+			data[name] = value
+		}
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode item data: %w", err)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if slug == "" && item.Slug.Valid && item.Slug.String != "" {
+		if slug, err = uniqueItemSlug(tx, item.CollectionID, item.Slug.String+"-copy"); err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := cloneStatus(tx, item.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := createItem(tx, item.CollectionID, slug, string(dataJSON), status, createdBy, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := copyItemTranslations(tx, item.ID, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit item clone: %w", err)
+	}
+
+	return d.GetItem(id)
+}
+
+// CloneCollection creates a new collection with the fields, workflow and
+// preview URL of source. With includeItems its items are copied too, as
+// drafts. It returns the new collection and how many fields and items were
+// copied.
+func (d *Database) CloneCollection(source *Collection, name, slug, description string, includeItems bool, createdBy int) (*Collection, int, int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := createCollection(tx, name, slug, description, source.Singleton, source.PreviewURL.String)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO collection_workflows (collection_id, definition)
+		SELECT ?, definition FROM collection_workflows WHERE collection_id = ?
+	`, id, source.ID)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to copy workflow: %w", err)
+	}
+
+	fieldCount, err := cloneCollectionFields(tx, source.ID, id)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	itemCount := 0
+	if includeItems {
+		if itemCount, err = cloneCollectionItems(tx, source.ID, id, createdBy); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to commit collection clone: %w", err)
+	}
+
+	collection, err := d.GetCollectionByID(id)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return collection, fieldCount, itemCount, nil
+}
+
+// cloneCollectionFields copies the fields of one collection to another
+func cloneCollectionFields(tx *sql.Tx, sourceID, targetID int) (int, error) {
+	rows, err := tx.Query(`
+		SELECT name, label, type, required, indexed, localizable, placeholder, default_value, sort_order
+		FROM collection_fields
+		WHERE collection_id = ?
+		ORDER BY sort_order ASC, created_at ASC
+	`, sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get collection fields: %w", err)
+	}
+
+	var fields []CollectionField
+	for rows.Next() {
+		var f CollectionField
+		if err := rows.Scan(&f.Name, &f.Label, &f.Type, &f.Required, &f.Indexed, &f.Localizable, &f.Placeholder, &f.DefaultValue, &f.SortOrder); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan collection field: %w", err)
+		}
+		fields = append(fields, f)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating collection fields: %w", err)
+	}
+
+	for _, f := range fields {
+		if _, err := createCollectionField(tx, targetID, f.Name, f.Label, f.Type, f.Required, f.Indexed, f.Localizable, f.Placeholder.String, f.DefaultValue.String, f.SortOrder); err != nil {
+			return 0, err
+		}
+	}
+	if err := syncFieldIndexes(tx); err != nil {
+		return 0, err
+	}
+	return len(fields), nil
+}
+
+// cloneCollectionItems copies the items of one collection to another, oldest
+// first, starting each copy in the target's initial status
+func cloneCollectionItems(tx *sql.Tx, sourceID, targetID, createdBy int) (int, error) {
+	rows, err := tx.Query(`SELECT id, slug, data FROM items WHERE collection_id = ? AND deleted_at IS NULL ORDER BY id`, sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	type itemCopy struct {
+		id   int
+		slug sql.NullString
+		data string
+	}
+	var items []itemCopy
+	for rows.Next() {
+		var item itemCopy
+		if err := rows.Scan(&item.id, &item.slug, &item.data); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan item: %w", err)
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating items: %w", err)
+	}
+
+	status, err := cloneStatus(tx, targetID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, item := range items {
+		id, err := createItem(tx, targetID, item.slug.String, item.data, status, createdBy, nil)
+		if err != nil {
+			return 0, err
+		}
+		if err := copyItemTranslations(tx, item.id, id); err != nil {
+			return 0, err
+		}
+	}
+	return len(items), nil
+}
+
+// handleAdminItemClone serves POST /admin-api/items/{id}/clone
+func (s *Server) handleAdminItemClone(w http.ResponseWriter, r *http.Request, user *User, itemID int) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Slug string                 `json:"slug"`
+		Data map[string]interface{} `json:"data"`
+	}
+	// The body is optional
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		s.sendJSONError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	item, err := s.db.GetItem(itemID)
+	if err != nil {
+		log.Printf("Error getting item %d: %v", itemID, err)
+		s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+		return
+	}
+	if item == nil {
+		s.sendJSONError(w, "Item not found", http.StatusNotFound)
+		return
+	}
+
+	clone, err := s.db.CloneItem(item, request.Slug, request.Data, user.ID)
+	if err == errSingletonItemExists {
+		s.sendJSONError(w, "Items of a singleton collection can't be cloned", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("Error cloning item %d: %v", itemID, err)
+		s.sendJSONError(w, "Failed to clone item", http.StatusInternalServerError)
+		return
+	}
+
+	s.audit(r, user, "item.clone", "item", clone.ID, nil, map[string]interface{}{"source": itemID, "item": auditItem(clone)})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", itemETag(clone))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(convertItemToResponse(clone))
+}
+
+// handleAdminCollectionClone serves POST /admin-api/collections/{id}/clone.
+// The name and slug default to the original's with " (copy)" and "-copy"
+// appended.
+func (s *Server) handleAdminCollectionClone(w http.ResponseWriter, r *http.Request, user *User, collection *Collection) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Name         string  `json:"name"`
+		Slug         string  `json:"slug"`
+		Description  *string `json:"description"`
+		IncludeItems bool    `json:"includeItems"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		s.sendJSONError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if request.Name == "" {
+		request.Name = collection.Name + " (copy)"
+	}
+	if request.Slug == "" {
+		request.Slug = collection.Slug + "-copy"
+	}
+	description := collection.Description.String
+	if request.Description != nil {
+		description = *request.Description
+	}
+
+	clone, fieldCount, itemCount, err := s.db.CloneCollection(collection, request.Name, request.Slug, description, request.IncludeItems, user.ID)
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		s.sendJSONError(w, "A collection with this name or slug already exists (it may be in the trash)", http.StatusConflict)
+		return
+	}
+	if err != nil || clone == nil {
+		log.Printf("Error cloning collection %d: %v", collection.ID, err)
+		s.sendJSONError(w, "Failed to clone collection", http.StatusInternalServerError)
+		return
+	}
+
+	response := collectionResponse(clone)
+	response["clonedFields"] = fieldCount
+	response["clonedItems"] = itemCount
+	s.audit(r, user, "collection.clone", "collection", clone.ID, nil, map[string]interface{}{"source": collection.ID, "collection": response})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
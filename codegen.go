@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CollectionDefinition pairs a collection with its fields for code generation
+type CollectionDefinition struct {
+	Collection Collection
+	Fields     []CollectionField
+}
+
+// loadCollectionDefinitions returns every collection, or a single one when
+// slug is not empty. A missing slug returns an empty slice.
+func (d *Database) loadCollectionDefinitions(slug string) ([]CollectionDefinition, error) {
+	var collections []Collection
+	if slug != "" {
+		collection, err := d.GetCollectionBySlug(slug)
+		if err != nil {
+			return nil, err
+		}
+		if collection != nil {
+			collections = append(collections, *collection)
+		}
+	} else {
+		var err error
+		collections, err = d.GetCollections()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	definitions := []CollectionDefinition{}
+	for _, c := range collections {
+		fields, err := d.GetCollectionFields(c.ID)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, CollectionDefinition{Collection: c, Fields: fields})
+	}
+	return definitions, nil
+}
+
+// typeName turns a collection slug into a PascalCase identifier
+func typeName(slug string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range slug {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "Collection" + name
+	}
+	// Avoid clashing with the shared Item, Markdown, Error and Facets definitions
+	if name == "Item" || name == "Markdown" || name == "Error" || name == "Facets" {
+		name += "Collection"
+	}
+	return name
+}
+
+// collectionTypeNames gives every collection a distinct type name, keyed by
+// collection ID. Slugs like blog-post and blog_post turn into the same name,
+// so the collection created later gets a numeric suffix. The names generated
+// from a type name (BlogPostData, getBlogPostItem) must not clash either.
+func collectionTypeNames(defs []CollectionDefinition) map[int]string {
+	sorted := make([]CollectionDefinition, len(defs))
+	copy(sorted, defs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Collection.ID < sorted[j].Collection.ID })
+
+	used := make(map[string]bool)
+	taken := func(name string) bool {
+		return used[name] || used[name+"Data"] || used[name+"Item"]
+	}
+
+	names := make(map[int]string)
+	for _, def := range sorted {
+		base := typeName(def.Collection.Slug)
+		name := base
+		for n := 2; taken(name); n++ {
+			name = base + strconv.Itoa(n)
+		}
+		used[name] = true
+		used[name+"Data"] = true
+		used[name+"Item"] = true
+		names[def.Collection.ID] = name
+	}
+	return names
+}
+
+// fieldJSONSchema describes the stored value of a field type. refBase is where
+// shared definitions live, e.g. "#/$defs/".
+func fieldJSONSchema(field CollectionField, refBase string) map[string]interface{} {
+	var schema map[string]interface{}
+	switch field.Type {
+	case "number":
+		schema = map[string]interface{}{"type": "number"}
+	case "boolean":
+		schema = map[string]interface{}{"type": "boolean"}
+	case "asset":
+		schema = map[string]interface{}{"type": "integer", "minimum": 1, "description": "Asset ID, served from /assets/{id}"}
+	case "date":
+		schema = map[string]interface{}{"type": "string", "format": "date"}
+	case "email":
+		schema = map[string]interface{}{"type": "string", "format": "email"}
+	case "url":
+		schema = map[string]interface{}{"type": "string", "format": "uri"}
+	case "markdown":
+		schema = map[string]interface{}{"oneOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"$ref": refBase + "Markdown"},
+		}}
+	default:
+		schema = map[string]interface{}{"type": "string"}
+	}
+	schema["title"] = field.Label
+	return schema
+}
+
+// collectionJSONSchema describes the item shape returned by the API for a
+// collection. Shared definitions are expected under refBase.
+func collectionJSONSchema(def CollectionDefinition, refBase string) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	for _, field := range def.Fields {
+		properties[field.Name] = fieldJSONSchema(field, refBase)
+		if field.Required {
+			required = append(required, field.Name)
+		}
+	}
+
+	description := def.Collection.Description.String
+	if description == "" {
+		description = fmt.Sprintf("An item in the %s collection", def.Collection.Name)
+	}
+
+	return map[string]interface{}{
+		"title":       def.Collection.Name,
+		"description": description,
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"id":           map[string]interface{}{"type": "integer"},
+			"collectionId": map[string]interface{}{"type": "integer"},
+			"slug":         map[string]interface{}{"type": "string"},
+			"status":       map[string]interface{}{"type": "string"},
+			"version":      map[string]interface{}{"type": "integer", "minimum": 1},
+			"publishAt":    map[string]interface{}{"type": "string", "format": "date-time"},
+			"unpublishAt":  map[string]interface{}{"type": "string", "format": "date-time"},
+			"createdAt":    map[string]interface{}{"type": "string", "format": "date-time"},
+			"updatedAt":    map[string]interface{}{"type": "string", "format": "date-time"},
+			"data": map[string]interface{}{
+				"type":       "object",
+				"properties": properties,
+				"required":   required,
+			},
+		},
+		"required": []string{"id", "collectionId", "status", "version", "createdAt", "updatedAt", "data"},
+	}
+}
+
+// markdownJSONSchema is the {md, html} shape the admin editor saves markdown
+// fields in. Values written through the API or imported from CSV are stored
+// as given, usually a plain string.
+var markdownJSONSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"md":   map[string]interface{}{"type": "string", "description": "Markdown source"},
+		"html": map[string]interface{}{"type": "string", "description": "Rendered HTML"},
+	},
+	"required": []string{"md", "html"},
+}
+
+// generateJSONSchema builds a JSON Schema document. A single collection is
+// the root schema; several are listed under $defs by type name.
+func generateJSONSchema(defs []CollectionDefinition, single bool) map[string]interface{} {
+	if single && len(defs) == 1 {
+		schema := collectionJSONSchema(defs[0], "#/$defs/")
+		schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+		schema["$defs"] = map[string]interface{}{"Markdown": markdownJSONSchema}
+		return schema
+	}
+
+	definitions := map[string]interface{}{"Markdown": markdownJSONSchema}
+	names := collectionTypeNames(defs)
+	for _, def := range defs {
+		definitions[names[def.Collection.ID]] = collectionJSONSchema(def, "#/$defs/")
+	}
+	return map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "Lodge collections",
+		"$defs":   definitions,
+	}
+}
+
+// fieldTypeScript returns the TypeScript type of a field's stored value
+func fieldTypeScript(field CollectionField) string {
+	switch field.Type {
+	case "number", "asset":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "markdown":
+		return "string | Markdown"
+	default:
+		return "string"
+	}
+}
+
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// generateTypeScript builds type definitions for the API item shape of each
+// collection
+func generateTypeScript(defs []CollectionDefinition) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Lodge CMS. Do not edit by hand.\n\n")
+	b.WriteString("/** Markdown saved from the admin editor: the source text and its rendered HTML. Values written through the API or CSV import are plain strings. */\n")
+	b.WriteString("export interface Markdown {\n  md: string;\n  html: string;\n}\n\n")
+	b.WriteString("/** Fields common to every item returned by the API. */\n")
+	b.WriteString("export interface Item<T> {\n  id: number;\n  collectionId: number;\n  slug?: string;\n  data: T;\n  status: string;\n  version: number;\n  publishAt?: string;\n  unpublishAt?: string;\n  createdAt: string;\n  updatedAt: string;\n}\n")
+
+	names := collectionTypeNames(defs)
+	for _, def := range defs {
+		name := names[def.Collection.ID]
+
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "/** %s (`%s`) */\n", def.Collection.Name, def.Collection.Slug)
+		fmt.Fprintf(&b, "export interface %sData {\n", name)
+		for _, field := range def.Fields {
+			key := field.Name
+			if !plainIdentifier.MatchString(key) {
+				key = fmt.Sprintf("%q", key)
+			}
+			optional := "?"
+			if field.Required {
+				optional = ""
+			}
+			comment := field.Label
+			if field.Type == "asset" {
+				comment += " (asset ID)"
+			} else if field.Type == "date" {
+				comment += " (YYYY-MM-DD)"
+			}
+			fmt.Fprintf(&b, "  /** %s */\n", comment)
+			fmt.Fprintf(&b, "  %s%s: %s;\n", key, optional, fieldTypeScript(field))
+		}
+		b.WriteString("}\n\n")
+		fmt.Fprintf(&b, "export type %s = Item<%sData>;\n", name, name)
+	}
+
+	return b.String()
+}
+
+// handleAPISchema serves generated type definitions for API consumers:
+//
+//	GET /api/schema/json-schema[/{slug}]
+//	GET /api/schema/typescript[/{slug}]
+func (s *Server) handleAPISchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	_, err := s.validateAPIKey(r)
+	if err != nil {
+		s.sendJSONError(w, "Invalid or missing API key", http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/schema/")
+	format, slug, _ := strings.Cut(path, "/")
+
+	defs, err := s.db.loadCollectionDefinitions(slug)
+	if err != nil {
+		log.Printf("Error loading collection definitions: %v", err)
+		s.sendJSONError(w, "Failed to load collections", http.StatusInternalServerError)
+		return
+	}
+	if slug != "" && len(defs) == 0 {
+		s.sendJSONError(w, "Collection not found", http.StatusNotFound)
+		return
+	}
+
+	switch format {
+	case "json-schema":
+		w.Header().Set("Content-Type", "application/schema+json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(generateJSONSchema(defs, slug != ""))
+	case "typescript":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(generateTypeScript(defs)))
+	default:
+		s.sendJSONError(w, "Unknown schema format. Use json-schema or typescript", http.StatusNotFound)
+	}
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errCommentParentNotFound is returned when a reply names a comment that
+// isn't on the same item
+var errCommentParentNotFound = errors.New("parent comment not found")
+
+// ItemComment is an editorial comment on an item. Comments without a parent
+// start a thread; replies always belong to the thread's first comment, which
+// also holds whether the thread is resolved.
+type ItemComment struct {
+	ID             int
+	ItemID         int
+	ParentID       sql.NullInt64
+	Field          sql.NullString
+	Body           string
+	CreatedBy      sql.NullInt64
+	CreatedByName  sql.NullString
+	ResolvedBy     sql.NullInt64
+	ResolvedByName sql.NullString
+	ResolvedAt     sql.NullTime
+	CreatedAt      time.Time
+}
+
+// threadID is the ID of the first comment of the comment's thread
+func (c *ItemComment) threadID() int {
+	if c.ParentID.Valid {
+		return int(c.ParentID.Int64)
+	}
+	return c.ID
+}
+
+const itemCommentColumns = `
+	c.id, c.item_id, c.parent_id, c.field, c.body, c.created_by, cu.username,
+	c.resolved_by, ru.username, c.resolved_at, c.created_at
+	FROM item_comments c
+	LEFT JOIN users cu ON cu.id = c.created_by
+	LEFT JOIN users ru ON ru.id = c.resolved_by
+`
+
+func scanItemComment(row interface{ Scan(...interface{}) error }, c *ItemComment) error {
+	return row.Scan(&c.ID, &c.ItemID, &c.ParentID, &c.Field, &c.Body, &c.CreatedBy, &c.CreatedByName,
+		&c.ResolvedBy, &c.ResolvedByName, &c.ResolvedAt, &c.CreatedAt)
+}
+
+// GetItemComment returns a comment of an item, or nil if the item has no
+// such comment
+func (d *Database) GetItemComment(itemID, commentID int) (*ItemComment, error) {
+	var c ItemComment
+	err := scanItemComment(d.db.QueryRow(`SELECT `+itemCommentColumns+` WHERE c.item_id = ? AND c.id = ?`, itemID, commentID), &c)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	return &c, nil
+}
+
+// GetItemComments returns all comments on an item, oldest first
+func (d *Database) GetItemComments(itemID int) ([]ItemComment, error) {
+	rows, err := d.db.Query(`SELECT `+itemCommentColumns+` WHERE c.item_id = ? ORDER BY c.id ASC`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []ItemComment{}
+	for rows.Next() {
+		var c ItemComment
+		if err := scanItemComment(rows, &c); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// CreateItemComment adds a comment to an item. A reply (parentID set) joins
+// the thread of its parent and is anchored to the same field as the thread.
+func (d *Database) CreateItemComment(itemID int, parentID *int, field, body string, createdBy int) (*ItemComment, error) {
+	var parent sql.NullInt64
+	if parentID != nil {
+		thread, err := d.GetItemComment(itemID, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		if thread == nil {
+			return nil, errCommentParentNotFound
+		}
+		parent = sql.NullInt64{Int64: int64(thread.threadID()), Valid: true}
+		field = thread.Field.String
+	}
+
+	var fieldParam interface{}
+	if field != "" {
+		fieldParam = field
+	}
+
+	result, err := d.db.Exec(`
+		INSERT INTO item_comments (item_id, parent_id, field, body, created_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, itemID, parent, fieldParam, body, createdBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment ID: %w", err)
+	}
+
+	return d.GetItemComment(itemID, int(id))
+}
+
+// SetItemCommentThreadResolved resolves or reopens a comment thread
+func (d *Database) SetItemCommentThreadResolved(threadID int, resolved bool, userID int) error {
+	var err error
+	if resolved {
+		_, err = d.db.Exec(`
+			UPDATE item_comments SET resolved_by = ?, resolved_at = CURRENT_TIMESTAMP
+			WHERE id = ? AND resolved_at IS NULL
+		`, userID, threadID)
+	} else {
+		_, err = d.db.Exec(`UPDATE item_comments SET resolved_by = NULL, resolved_at = NULL WHERE id = ?`, threadID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update comment thread: %w", err)
+	}
+	return nil
+}
+
+// GetUnresolvedCommentCounts returns the number of unresolved comment threads
+// on each item of a collection. Items without any are left out.
+func (d *Database) GetUnresolvedCommentCounts(collectionID int) (map[int]int, error) {
+	rows, err := d.db.Query(`
+		SELECT c.item_id, COUNT(*)
+		FROM item_comments c
+		JOIN items i ON i.id = c.item_id
+		WHERE i.collection_id = ? AND c.parent_id IS NULL AND c.resolved_at IS NULL
+		GROUP BY c.item_id
+	`, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var itemID, count int
+		if err := rows.Scan(&itemID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan comment count: %w", err)
+		}
+		counts[itemID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comment counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// renameCommentField moves the comments anchored to a field of a collection's
+// items to the field's new name, inside the caller's transaction
+func renameCommentField(tx *sql.Tx, collectionID int, oldName, newName string) error {
+	_, err := tx.Exec(`
+		UPDATE item_comments SET field = ?
+		WHERE field = ? AND item_id IN (SELECT id FROM items WHERE collection_id = ?)
+	`, newName, oldName, collectionID)
+	if err != nil {
+		return fmt.Errorf("failed to rename comment field: %w", err)
+	}
+	return nil
+}
+
+func commentResponse(c *ItemComment) map[string]interface{} {
+	response := map[string]interface{}{
+		"id":        c.ID,
+		"field":     nil,
+		"body":      c.Body,
+		"createdBy": nil,
+		"author":    nil,
+		"createdAt": c.CreatedAt.Format(time.RFC3339),
+	}
+	if c.ParentID.Valid {
+		response["parentId"] = c.ParentID.Int64
+	}
+	if c.Field.Valid {
+		response["field"] = c.Field.String
+	}
+	if c.CreatedBy.Valid {
+		response["createdBy"] = c.CreatedBy.Int64
+	}
+	if c.CreatedByName.Valid {
+		response["author"] = c.CreatedByName.String
+	}
+	return response
+}
+
+// threadResponse renders the first comment of a thread with its resolution,
+// and its replies unless they are nil
+func threadResponse(c *ItemComment, replies []map[string]interface{}) map[string]interface{} {
+	response := commentResponse(c)
+	response["resolved"] = c.ResolvedAt.Valid
+	response["resolvedBy"] = nil
+	response["resolvedAt"] = nil
+	if c.ResolvedByName.Valid {
+		response["resolvedBy"] = c.ResolvedByName.String
+	}
+	if c.ResolvedAt.Valid {
+		response["resolvedAt"] = c.ResolvedAt.Time.UTC().Format(time.RFC3339)
+	}
+	if replies != nil {
+		response["replies"] = replies
+	}
+	return response
+}
+
+// handleAdminItemComments serves the comment threads of an item:
+//
+//	GET  /admin-api/items/{id}/comments?field=title&resolved=false
+//	POST /admin-api/items/{id}/comments  {"body": "...", "field": "title", "parentId": 3}
+//	POST /admin-api/items/{id}/comments/{commentId}/resolve
+//	POST /admin-api/items/{id}/comments/{commentId}/reopen
+func (s *Server) handleAdminItemComments(w http.ResponseWriter, r *http.Request, user *User, itemID int, parts []string) {
+	item, err := s.db.GetItem(itemID)
+	if err != nil {
+		log.Printf("Error getting item %d: %v", itemID, err)
+		s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+		return
+	}
+	if item == nil {
+		s.sendJSONError(w, "Item not found", http.StatusNotFound)
+		return
+	}
+
+	if len(parts) == 0 || parts[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			s.listItemComments(w, r, itemID)
+		case http.MethodPost:
+			s.createItemComment(w, r, user, item)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if len(parts) != 2 || (parts[1] != "resolve" && parts[1] != "reopen") {
+		s.sendJSONError(w, "Invalid comments endpoint", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	commentID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		s.sendJSONError(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+
+	comment, err := s.db.GetItemComment(itemID, commentID)
+	if err != nil {
+		log.Printf("Error getting comment %d: %v", commentID, err)
+		s.sendJSONError(w, "Failed to get comment", http.StatusInternalServerError)
+		return
+	}
+	if comment == nil {
+		s.sendJSONError(w, "Comment not found", http.StatusNotFound)
+		return
+	}
+
+	// Resolving a reply resolves its whole thread
+	threadID := comment.threadID()
+	if err := s.db.SetItemCommentThreadResolved(threadID, parts[1] == "resolve", user.ID); err != nil {
+		log.Printf("Error updating comment thread %d: %v", threadID, err)
+		s.sendJSONError(w, "Failed to update comment thread", http.StatusInternalServerError)
+		return
+	}
+
+	thread, err := s.db.GetItemComment(itemID, threadID)
+	if err != nil || thread == nil {
+		log.Printf("Error getting comment %d: %v", threadID, err)
+		s.sendJSONError(w, "Failed to get comment", http.StatusInternalServerError)
+		return
+	}
+
+	s.audit(r, user, "comment."+parts[1], "comment", threadID, nil, threadResponse(thread, nil))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(threadResponse(thread, nil))
+}
+
+// listItemComments writes an item's comment threads, oldest first, each with
+// its replies
+func (s *Server) listItemComments(w http.ResponseWriter, r *http.Request, itemID int) {
+	field := r.URL.Query().Get("field")
+	resolvedFilter := r.URL.Query().Get("resolved")
+	if resolvedFilter != "" && resolvedFilter != "true" && resolvedFilter != "false" {
+		s.sendJSONError(w, "resolved must be true or false", http.StatusBadRequest)
+		return
+	}
+
+	comments, err := s.db.GetItemComments(itemID)
+	if err != nil {
+		log.Printf("Error getting comments for item %d: %v", itemID, err)
+		s.sendJSONError(w, "Failed to get comments", http.StatusInternalServerError)
+		return
+	}
+
+	replies := make(map[int][]map[string]interface{})
+	for i := range comments {
+		if c := &comments[i]; c.ParentID.Valid {
+			replies[c.threadID()] = append(replies[c.threadID()], commentResponse(c))
+		} else {
+			replies[c.ID] = []map[string]interface{}{}
+		}
+	}
+
+	threads := []map[string]interface{}{}
+	for i := range comments {
+		c := &comments[i]
+		if c.ParentID.Valid {
+			continue
+		}
+		if field != "" && c.Field.String != field {
+			continue
+		}
+		if resolvedFilter != "" && c.ResolvedAt.Valid != (resolvedFilter == "true") {
+			continue
+		}
+		threads = append(threads, threadResponse(c, replies[c.ID]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(threads)
+}
+
+// createItemComment starts a comment thread on an item or replies to one
+func (s *Server) createItemComment(w http.ResponseWriter, r *http.Request, user *User, item *Item) {
+	var request struct {
+		Body     string `json:"body"`
+		Field    string `json:"field"`
+		ParentID *int   `json:"parentId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.sendJSONError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	request.Body = strings.TrimSpace(request.Body)
+	if request.Body == "" {
+		s.sendJSONError(w, "Comment body is required", http.StatusBadRequest)
+		return
+	}
+
+	// Replies take their thread's field, so only check it for new threads
+	if request.Field != "" && request.ParentID == nil {
+		fields, err := s.db.GetCollectionFields(item.CollectionID)
+		if err != nil {
+			log.Printf("Error getting fields for collection %d: %v", item.CollectionID, err)
+			s.sendJSONError(w, "Failed to create comment", http.StatusInternalServerError)
+			return
+		}
+		known := false
+		for _, f := range fields {
+			if f.Name == request.Field {
+				known = true
+				break
+			}
+		}
+		if !known {
+			s.sendJSONError(w, fmt.Sprintf("Collection has no field %q", request.Field), http.StatusBadRequest)
+			return
+		}
+	}
+
+	comment, err := s.db.CreateItemComment(item.ID, request.ParentID, request.Field, request.Body, user.ID)
+	if err == errCommentParentNotFound {
+		s.sendJSONError(w, "Parent comment not found", http.StatusBadRequest)
+		return
+	}
+	if err != nil || comment == nil {
+		log.Printf("Error creating comment on item %d: %v", item.ID, err)
+		s.sendJSONError(w, "Failed to create comment", http.StatusInternalServerError)
+		return
+	}
+
+	response := commentResponse(comment)
+	if !comment.ParentID.Valid {
+		response = threadResponse(comment, []map[string]interface{}{})
+	}
+	s.audit(r, user, "comment.create", "comment", comment.ID, nil, response)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
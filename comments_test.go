@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestFieldRenameMovesComments(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.CreateUser("editor", "password", "editor@example.com", "admin"); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	editor, err := db.GetUserByUsername("editor")
+	if err != nil {
+		t.Fatalf("GetUserByUsername: %v", err)
+	}
+
+	// Two collections with a title field, each with a commented item
+	var fieldIDs, itemIDs []int
+	for _, slug := range []string{"posts", "pages"} {
+		collection, err := db.CreateCollection(slug, slug, "", false, "")
+		if err != nil {
+			t.Fatalf("CreateCollection: %v", err)
+		}
+		field, err := db.CreateCollectionField(collection.ID, "title", "Title", "text", false, false, false, "", "", 0)
+		if err != nil {
+			t.Fatalf("CreateCollectionField: %v", err)
+		}
+		item, err := db.CreateItem(collection.ID, "first", `{"title":"First"}`, "draft", editor.ID, nil)
+		if err != nil {
+			t.Fatalf("CreateItem: %v", err)
+		}
+		for _, field := range []string{"title", ""} {
+			if _, err := db.CreateItemComment(item.ID, nil, field, "Check this", editor.ID); err != nil {
+				t.Fatalf("CreateItemComment: %v", err)
+			}
+		}
+		fieldIDs = append(fieldIDs, field.ID)
+		itemIDs = append(itemIDs, item.ID)
+	}
+
+	fields := func(itemID int) []string {
+		t.Helper()
+		comments, err := db.GetItemComments(itemID)
+		if err != nil {
+			t.Fatalf("GetItemComments: %v", err)
+		}
+		names := []string{}
+		for _, c := range comments {
+			names = append(names, c.Field.String)
+		}
+		return names
+	}
+	rename := func(opts FieldMigrationOptions) {
+		t.Helper()
+		if _, err := db.UpdateCollectionField(fieldIDs[0], "headline", "Headline", "text", false, false, false, "", "", 0, opts); err != nil {
+			t.Fatalf("UpdateCollectionField: %v", err)
+		}
+	}
+
+	rename(FieldMigrationOptions{DryRun: true})
+	if got := fields(itemIDs[0]); got[0] != "title" {
+		t.Errorf("after a dry run, comment fields = %q, want title kept", got)
+	}
+
+	rename(FieldMigrationOptions{})
+	if got := fields(itemIDs[0]); len(got) != 2 || got[0] != "headline" || got[1] != "" {
+		t.Errorf("renamed collection's comment fields = %q, want [headline \"\"]", got)
+	}
+	if got := fields(itemIDs[1]); len(got) != 2 || got[0] != "title" {
+		t.Errorf("other collection's comment fields = %q, want title kept", got)
+	}
+}
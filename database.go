@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -61,10 +62,21 @@ func (d *Database) migrate() error {
 		name TEXT UNIQUE NOT NULL,
 		slug TEXT UNIQUE NOT NULL,
 		description TEXT,
+		singleton BOOLEAN NOT NULL DEFAULT 0, -- Holds exactly one item (e.g. a homepage)
+		preview_url TEXT, -- Template for links to preview items on the site
+		deleted_at DATETIME, -- Set while the collection is in the trash
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
+	-- Previous collection slugs, so renamed collections keep resolving
+	CREATE TABLE IF NOT EXISTS collection_slug_history (
+		slug TEXT PRIMARY KEY,
+		collection_id INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE
+	);
+
 	-- Collection Fields table
 	CREATE TABLE IF NOT EXISTS collection_fields (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -76,6 +88,8 @@ func (d *Database) migrate() error {
 		placeholder TEXT,
 		default_value TEXT,
 		sort_order INTEGER DEFAULT 0,
+		indexed BOOLEAN NOT NULL DEFAULT 0,
+		localizable BOOLEAN NOT NULL DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
 		UNIQUE(collection_id, name)
@@ -88,6 +102,10 @@ func (d *Database) migrate() error {
 		slug TEXT,
 		data TEXT NOT NULL, -- JSON content
 		status TEXT NOT NULL DEFAULT 'draft',
+		version INTEGER NOT NULL DEFAULT 1, -- Incremented on every save, for optimistic concurrency
+		publish_at DATETIME, -- When the scheduler should publish the item
+		unpublish_at DATETIME, -- When the scheduler should archive the item
+		deleted_at DATETIME, -- Set while the item is in the trash
 		created_by INTEGER,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
@@ -95,6 +113,109 @@ func (d *Database) migrate() error {
 		FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
 	);
 
+	-- Item revisions table (a snapshot of an item after every change)
+	CREATE TABLE IF NOT EXISTS item_revisions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		item_id INTEGER NOT NULL,
+		revision INTEGER NOT NULL, -- Numbered from 1 for each item
+		slug TEXT,
+		data TEXT NOT NULL,
+		status TEXT NOT NULL,
+		created_by INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+		UNIQUE (item_id, revision)
+	);
+
+	-- Collection workflows (states and allowed transitions by role, as JSON)
+	CREATE TABLE IF NOT EXISTS collection_workflows (
+		collection_id INTEGER PRIMARY KEY,
+		definition TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE
+	);
+
+	-- Item workflow history (who moved an item between states, when and why)
+	CREATE TABLE IF NOT EXISTS item_transitions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		item_id INTEGER NOT NULL,
+		from_state TEXT NOT NULL,
+		to_state TEXT NOT NULL,
+		comment TEXT,
+		created_by INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+	);
+
+	-- Advisory edit locks (who has an item open in the editor, until when)
+	CREATE TABLE IF NOT EXISTS item_locks (
+		item_id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		acquired_at DATETIME NOT NULL,
+		expires_at DATETIME NOT NULL,
+		FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	-- Editorial comments on items. Replies point at the first comment of their
+	-- thread, which records whether the thread is resolved.
+	CREATE TABLE IF NOT EXISTS item_comments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		item_id INTEGER NOT NULL,
+		parent_id INTEGER,
+		field TEXT,
+		body TEXT NOT NULL,
+		created_by INTEGER,
+		resolved_by INTEGER,
+		resolved_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+		FOREIGN KEY (parent_id) REFERENCES item_comments(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+		FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL
+	);
+
+	-- Audit log of administrative actions. Rows are only ever added, and
+	-- removed once they are older than the retention period. Users aren't
+	-- foreign keys so entries outlive them.
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER,
+		username TEXT,
+		action TEXT NOT NULL,
+		target_type TEXT NOT NULL,
+		target_id TEXT,
+		before TEXT,
+		after TEXT,
+		ip TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TRIGGER IF NOT EXISTS audit_log_append_only BEFORE UPDATE ON audit_log
+	BEGIN
+		SELECT RAISE(ABORT, 'audit log entries cannot be changed');
+	END;
+
+	-- Full-text index of the text, textarea and markdown fields of items.
+	-- The rowid is the item ID.
+	CREATE VIRTUAL TABLE IF NOT EXISTS item_search USING fts5(
+		content,
+		tokenize = 'unicode61 remove_diacritics 2'
+	);
+
+	-- Values of localizable fields in locales other than the default, which
+	-- are kept in items.data. data is a JSON object keyed by field name.
+	CREATE TABLE IF NOT EXISTS item_translations (
+		item_id INTEGER NOT NULL,
+		locale TEXT NOT NULL,
+		data TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (item_id, locale),
+		FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE
+	);
+
 	-- Settings table
 	CREATE TABLE IF NOT EXISTS settings (
 		key TEXT PRIMARY KEY,
@@ -116,7 +237,21 @@ func (d *Database) migrate() error {
 		FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
 	);
 
+	-- Assets table (uploaded files, stored content-addressed on disk)
+	CREATE TABLE IF NOT EXISTS assets (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		hash TEXT NOT NULL, -- SHA256 of the file contents
+		filename TEXT NOT NULL,
+		mime_type TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		private BOOLEAN NOT NULL DEFAULT 0,
+		created_by INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+	);
+
 	-- Create indexes
+	CREATE INDEX IF NOT EXISTS idx_collection_slug_history_collection_id ON collection_slug_history(collection_id);
 	CREATE INDEX IF NOT EXISTS idx_collection_fields_collection_id ON collection_fields(collection_id);
 	CREATE INDEX IF NOT EXISTS idx_collection_fields_sort_order ON collection_fields(collection_id, sort_order);
 	CREATE INDEX IF NOT EXISTS idx_items_collection_id ON items(collection_id);
@@ -125,16 +260,90 @@ func (d *Database) migrate() error {
 	CREATE INDEX IF NOT EXISTS idx_items_slug ON items(slug);
 	CREATE INDEX IF NOT EXISTS idx_api_keys_hash ON api_keys(key_hash);
 	CREATE INDEX IF NOT EXISTS idx_api_keys_active ON api_keys(is_active);
+	CREATE INDEX IF NOT EXISTS idx_assets_hash ON assets(hash);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target_type, target_id);
 	`
 
 	if _, err := d.db.Exec(schema); err != nil {
 		return fmt.Errorf("failed to create schema: %w", err)
 	}
 
+	// Columns added after a table was first released. CREATE TABLE IF NOT EXISTS
+	// leaves existing tables alone, so older databases need them added here.
+	columns := []struct {
+		table, column, definition string
+	}{
+		{"assets", "private", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"collections", "singleton", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"collections", "preview_url", "TEXT"},
+		{"items", "version", "INTEGER NOT NULL DEFAULT 1"},
+		{"items", "publish_at", "DATETIME"},
+		{"items", "unpublish_at", "DATETIME"},
+		{"collections", "deleted_at", "DATETIME"},
+		{"items", "deleted_at", "DATETIME"},
+		{"collection_fields", "indexed", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"collection_fields", "localizable", "BOOLEAN NOT NULL DEFAULT 0"},
+	}
+	for _, c := range columns {
+		if err := d.addColumnIfMissing(c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+
+	// Items saved before revisions were recorded start with their current state as revision 1
+	backfill := `
+		INSERT INTO item_revisions (item_id, revision, slug, data, status, created_by, created_at)
+		SELECT id, 1, slug, data, status, created_by, updated_at FROM items
+		WHERE NOT EXISTS (SELECT 1 FROM item_revisions WHERE item_id = items.id)
+	`
+	if _, err := d.db.Exec(backfill); err != nil {
+		return fmt.Errorf("failed to backfill item revisions: %w", err)
+	}
+
+	if err := d.backfillSearchIndex(); err != nil {
+		return fmt.Errorf("failed to backfill search index: %w", err)
+	}
+
+	if err := syncFieldIndexes(d.db); err != nil {
+		return err
+	}
+
 	log.Println("Database schema initialized")
 	return nil
 }
 
+// addColumnIfMissing adds a column to an existing table unless it is already present
+func (d *Database) addColumnIfMissing(table, column, definition string) error {
+	rows, err := d.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return fmt.Errorf("failed to scan column info: %w", err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating columns: %w", err)
+	}
+	rows.Close()
+
+	query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
 func (d *Database) Close() error {
 	return d.db.Close()
 }
@@ -239,11 +448,14 @@ func (d *Database) DeleteUser(id int) error {
 }
 
 // API Key Management
-func (d *Database) CreateAPIKey(name string, createdBy int) (string, error) {
+
+// CreateAPIKey creates an API key and returns it along with the full key,
+// which is only stored hashed and can't be read back later
+func (d *Database) CreateAPIKey(name string, createdBy int) (*APIKey, string, error) {
 	// Generate a random API key
 	keyBytes := make([]byte, 32)
 	if _, err := rand.Read(keyBytes); err != nil {
-		return "", fmt.Errorf("failed to generate random key: %w", err)
+		return nil, "", fmt.Errorf("failed to generate random key: %w", err)
 	}
 
 	// Create the full API key with prefix
@@ -258,12 +470,51 @@ func (d *Database) CreateAPIKey(name string, createdBy int) (string, error) {
 	keyPrefix := fullKey[:12] + "..."
 
 	query := `INSERT INTO api_keys (name, key_hash, key_prefix, created_by) VALUES (?, ?, ?, ?)`
-	_, err := d.db.Exec(query, name, keyHash, keyPrefix, createdBy)
+	result, err := d.db.Exec(query, name, keyHash, keyPrefix, createdBy)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get API key ID: %w", err)
+	}
+
+	key, err := d.GetAPIKey(int(id))
+	if err != nil {
+		return nil, "", err
+	}
+	return key, fullKey, nil
+}
+
+// GetAPIKey returns an API key by ID, or nil if there is none
+func (d *Database) GetAPIKey(id int) (*APIKey, error) {
+	query := `
+		SELECT id, name, key_hash, key_prefix, scopes, created_by, created_at, last_used_at, is_active
+		FROM api_keys
+		WHERE id = ?
+	`
+
+	var key APIKey
+	err := d.db.QueryRow(query, id).Scan(
+		&key.ID,
+		&key.Name,
+		&key.KeyHash,
+		&key.KeyPrefix,
+		&key.Scopes,
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.IsActive,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to create API key: %w", err)
+		return nil, fmt.Errorf("failed to get API key: %w", err)
 	}
 
-	return fullKey, nil
+	return &key, nil
 }
 
 func (d *Database) GetAPIKeys() ([]APIKey, error) {
@@ -372,25 +623,63 @@ func (d *Database) ValidateAPIKey(apiKey string) (*APIKey, error) {
 }
 
 // Collection Management
-func (d *Database) CreateCollection(name, slug, description string) (*Collection, error) {
-	query := `INSERT INTO collections (name, slug, description) VALUES (?, ?, ?)`
-	result, err := d.db.Exec(query, name, slug, description)
+
+// errSingletonItemExists is returned when adding a second item to a singleton collection
+var errSingletonItemExists = errors.New("singleton collection already has an item")
+
+// errSingletonHasItems is returned when a collection with several items is made a singleton
+var errSingletonHasItems = errors.New("collection has more than one item and cannot be made a singleton")
+
+// errItemVersionConflict is returned by UpdateItem when the item was saved by
+// someone else since the expected version was read
+var errItemVersionConflict = errors.New("item has been modified since it was loaded")
+
+func (d *Database) CreateCollection(name, slug, description string, singleton bool, previewURL string) (*Collection, error) {
+	tx, err := d.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create collection: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := createCollection(tx, name, slug, description, singleton, previewURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit collection: %w", err)
+	}
+
+	return d.GetCollectionByID(id)
+}
+
+// createCollection inserts a collection inside the caller's transaction and
+// returns its ID. An empty previewURL leaves the collection without one.
+func createCollection(tx *sql.Tx, name, slug, description string, singleton bool, previewURL string) (int, error) {
+	query := `INSERT INTO collections (name, slug, description, singleton, preview_url) VALUES (?, ?, ?, ?, ?)`
+	result, err := tx.Exec(query, name, slug, description, singleton, previewURLParam(previewURL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create collection: %w", err)
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get collection ID: %w", err)
+		return 0, fmt.Errorf("failed to get collection ID: %w", err)
 	}
 
-	return d.GetCollectionByID(int(id))
+	// A live collection takes over any redirect left behind by a renamed one
+	if _, err := tx.Exec(`DELETE FROM collection_slug_history WHERE slug = ?`, slug); err != nil {
+		return 0, fmt.Errorf("failed to clear slug history: %w", err)
+	}
+
+	return int(id), nil
 }
 
 func (d *Database) GetCollections() ([]Collection, error) {
 	query := `
-		SELECT id, name, slug, description, created_at, updated_at
+		SELECT id, name, slug, description, singleton, preview_url, created_at, updated_at
 		FROM collections
+		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -408,6 +697,8 @@ func (d *Database) GetCollections() ([]Collection, error) {
 			&collection.Name,
 			&collection.Slug,
 			&collection.Description,
+			&collection.Singleton,
+			&collection.PreviewURL,
 			&collection.CreatedAt,
 			&collection.UpdatedAt,
 		)
@@ -426,9 +717,9 @@ func (d *Database) GetCollections() ([]Collection, error) {
 
 func (d *Database) GetCollectionByID(id int) (*Collection, error) {
 	query := `
-		SELECT id, name, slug, description, created_at, updated_at
+		SELECT id, name, slug, description, singleton, preview_url, created_at, updated_at
 		FROM collections
-		WHERE id = ?
+		WHERE id = ? AND deleted_at IS NULL
 	`
 
 	var collection Collection
@@ -437,6 +728,8 @@ func (d *Database) GetCollectionByID(id int) (*Collection, error) {
 		&collection.Name,
 		&collection.Slug,
 		&collection.Description,
+		&collection.Singleton,
+		&collection.PreviewURL,
 		&collection.CreatedAt,
 		&collection.UpdatedAt,
 	)
@@ -453,9 +746,9 @@ func (d *Database) GetCollectionByID(id int) (*Collection, error) {
 
 func (d *Database) GetCollectionBySlug(slug string) (*Collection, error) {
 	query := `
-		SELECT id, name, slug, description, created_at, updated_at
+		SELECT id, name, slug, description, singleton, preview_url, created_at, updated_at
 		FROM collections
-		WHERE slug = ?
+		WHERE slug = ? AND deleted_at IS NULL
 	`
 
 	var collection Collection
@@ -464,6 +757,8 @@ func (d *Database) GetCollectionBySlug(slug string) (*Collection, error) {
 		&collection.Name,
 		&collection.Slug,
 		&collection.Description,
+		&collection.Singleton,
+		&collection.PreviewURL,
 		&collection.CreatedAt,
 		&collection.UpdatedAt,
 	)
@@ -478,51 +773,256 @@ func (d *Database) GetCollectionBySlug(slug string) (*Collection, error) {
 	return &collection, nil
 }
 
-func (d *Database) UpdateCollection(id int, name, slug, description string) error {
+// UpdateCollection updates a collection. When the slug changes, the old slug
+// is remembered so public API requests using it can be redirected. An empty
+// previewURL clears the preview URL.
+func (d *Database) UpdateCollection(id int, name, slug, description string, singleton bool, previewURL string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateCollection(tx, id, name, slug, description, singleton, previewURL); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit collection update: %w", err)
+	}
+
+	return nil
+}
+
+// updateCollection updates a collection inside the caller's transaction
+func updateCollection(tx *sql.Tx, id int, name, slug, description string, singleton bool, previewURL string) error {
+	var oldSlug string
+	err := tx.QueryRow(`SELECT slug FROM collections WHERE id = ? AND deleted_at IS NULL`, id).Scan(&oldSlug)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("collection not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	if singleton {
+		var itemCount int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM items WHERE collection_id = ? AND deleted_at IS NULL`, id).Scan(&itemCount); err != nil {
+			return fmt.Errorf("failed to count items: %w", err)
+		}
+		if itemCount > 1 {
+			return errSingletonHasItems
+		}
+	}
+
 	query := `
 		UPDATE collections
-		SET name = ?, slug = ?, description = ?, updated_at = CURRENT_TIMESTAMP
+		SET name = ?, slug = ?, description = ?, singleton = ?, preview_url = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
-	result, err := d.db.Exec(query, name, slug, description, id)
-	if err != nil {
+	if _, err := tx.Exec(query, name, slug, description, singleton, previewURLParam(previewURL), id); err != nil {
 		return fmt.Errorf("failed to update collection: %w", err)
 	}
 
+	if oldSlug != slug {
+		if _, err := tx.Exec(`DELETE FROM collection_slug_history WHERE slug = ?`, slug); err != nil {
+			return fmt.Errorf("failed to clear slug history: %w", err)
+		}
+		historyQuery := `INSERT OR REPLACE INTO collection_slug_history (slug, collection_id) VALUES (?, ?)`
+		if _, err := tx.Exec(historyQuery, oldSlug, id); err != nil {
+			return fmt.Errorf("failed to record slug history: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetCollectionByPreviousSlug finds the collection that used to have the given slug
+func (d *Database) GetCollectionByPreviousSlug(slug string) (*Collection, error) {
+	var id int
+	err := d.db.QueryRow(`SELECT collection_id FROM collection_slug_history WHERE slug = ?`, slug).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slug history: %w", err)
+	}
+
+	return d.GetCollectionByID(id)
+}
+
+// CountCollectionContents returns how many items and fields belong to a collection
+func (d *Database) CountCollectionContents(id int) (int, int, error) {
+	var itemCount, fieldCount int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM items WHERE collection_id = ? AND deleted_at IS NULL`, id).Scan(&itemCount); err != nil {
+		return 0, 0, fmt.Errorf("failed to count items: %w", err)
+	}
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM collection_fields WHERE collection_id = ?`, id).Scan(&fieldCount); err != nil {
+		return 0, 0, fmt.Errorf("failed to count fields: %w", err)
+	}
+	return itemCount, fieldCount, nil
+}
+
+// DeleteCollection moves a collection to the trash, which hides it along with
+// its fields and items until it is restored or purged. It returns how many
+// items and fields went with it.
+func (d *Database) DeleteCollection(id int) (int, int, error) {
+	itemCount, fieldCount, err := d.CountCollectionContents(id)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := deleteCollection(d.db, id); err != nil {
+		return 0, 0, err
+	}
+
+	return itemCount, fieldCount, nil
+}
+
+// deleteCollection moves a collection to the trash using the database or a
+// transaction
+func deleteCollection(e interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}, id int) error {
+	result, err := e.Exec(`UPDATE collections SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
-
 	if rowsAffected == 0 {
 		return fmt.Errorf("collection not found")
 	}
-
 	return nil
 }
 
+// PurgeCollection permanently removes a collection along with its fields and
+// items, including any in the trash, and returns how many items and fields
+// were deleted.
+func (d *Database) PurgeCollection(id int) (int, int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Delete dependent rows explicitly rather than relying on ON DELETE CASCADE,
+	// since foreign key enforcement is a per-connection setting in SQLite.
+	if _, err := tx.Exec(`DELETE FROM item_revisions WHERE item_id IN (SELECT id FROM items WHERE collection_id = ?)`, id); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete item revisions: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_transitions WHERE item_id IN (SELECT id FROM items WHERE collection_id = ?)`, id); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete item transitions: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_locks WHERE item_id IN (SELECT id FROM items WHERE collection_id = ?)`, id); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete item locks: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_comments WHERE item_id IN (SELECT id FROM items WHERE collection_id = ?)`, id); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete item comments: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_search WHERE rowid IN (SELECT id FROM items WHERE collection_id = ?)`, id); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete search index entries: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_translations WHERE item_id IN (SELECT id FROM items WHERE collection_id = ?)`, id); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete item translations: %w", err)
+	}
+	itemsResult, err := tx.Exec(`DELETE FROM items WHERE collection_id = ?`, id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to delete items: %w", err)
+	}
+	fieldsResult, err := tx.Exec(`DELETE FROM collection_fields WHERE collection_id = ?`, id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to delete fields: %w", err)
+	}
+	if err := syncFieldIndexes(tx); err != nil {
+		return 0, 0, err
+	}
+	if _, err := tx.Exec(`DELETE FROM collection_slug_history WHERE collection_id = ?`, id); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete slug history: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM collection_workflows WHERE collection_id = ?`, id); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete workflow: %w", err)
+	}
+
+	result, err := tx.Exec(`DELETE FROM collections WHERE id = ?`, id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to delete collection: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return 0, 0, fmt.Errorf("collection not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit collection purge: %w", err)
+	}
+
+	itemCount, _ := itemsResult.RowsAffected()
+	fieldCount, _ := fieldsResult.RowsAffected()
+	return int(itemCount), int(fieldCount), nil
+}
+
 // Collection Field Management
-func (d *Database) CreateCollectionField(collectionID int, name, label, fieldType string, required bool, placeholder, defaultValue string, sortOrder int) (*CollectionField, error) {
+func (d *Database) CreateCollectionField(collectionID int, name, label, fieldType string, required, indexed, localizable bool, placeholder, defaultValue string, sortOrder int) (*CollectionField, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := createCollectionField(tx, collectionID, name, label, fieldType, required, indexed, localizable, placeholder, defaultValue, sortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	if indexed {
+		if err := syncFieldIndexes(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Items may already hold a value under the new field's name
+	if err := reindexCollection(tx, collectionID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit collection field: %w", err)
+	}
+
+	return d.GetCollectionFieldByID(id)
+}
+
+// createCollectionField inserts a field using the database or a transaction
+// and returns its ID. The caller syncs field indexes when indexed is set.
+func createCollectionField(e interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}, collectionID int, name, label, fieldType string, required, indexed, localizable bool, placeholder, defaultValue string, sortOrder int) (int, error) {
 	query := `
-		INSERT INTO collection_fields (collection_id, name, label, type, required, placeholder, default_value, sort_order)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO collection_fields (collection_id, name, label, type, required, indexed, localizable, placeholder, default_value, sort_order)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	result, err := d.db.Exec(query, collectionID, name, label, fieldType, required, placeholder, defaultValue, sortOrder)
+	result, err := e.Exec(query, collectionID, name, label, fieldType, required, indexed, localizable, placeholder, defaultValue, sortOrder)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create collection field: %w", err)
+		return 0, fmt.Errorf("failed to create collection field: %w", err)
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get field ID: %w", err)
+		return 0, fmt.Errorf("failed to get field ID: %w", err)
 	}
 
-	return d.GetCollectionFieldByID(int(id))
+	return int(id), nil
 }
 
 func (d *Database) GetCollectionFields(collectionID int) ([]CollectionField, error) {
 	query := `
-		SELECT id, collection_id, name, label, type, required, placeholder, default_value, sort_order, created_at
+		SELECT id, collection_id, name, label, type, required, indexed, localizable, placeholder, default_value, sort_order, created_at
 		FROM collection_fields
 		WHERE collection_id = ?
 		ORDER BY sort_order ASC, created_at ASC
@@ -544,6 +1044,8 @@ func (d *Database) GetCollectionFields(collectionID int) ([]CollectionField, err
 			&field.Label,
 			&field.Type,
 			&field.Required,
+			&field.Indexed,
+			&field.Localizable,
 			&field.Placeholder,
 			&field.DefaultValue,
 			&field.SortOrder,
@@ -564,7 +1066,7 @@ func (d *Database) GetCollectionFields(collectionID int) ([]CollectionField, err
 
 func (d *Database) GetCollectionFieldByID(id int) (*CollectionField, error) {
 	query := `
-		SELECT id, collection_id, name, label, type, required, placeholder, default_value, sort_order, created_at
+		SELECT id, collection_id, name, label, type, required, indexed, localizable, placeholder, default_value, sort_order, created_at
 		FROM collection_fields
 		WHERE id = ?
 	`
@@ -577,6 +1079,8 @@ func (d *Database) GetCollectionFieldByID(id int) (*CollectionField, error) {
 		&field.Label,
 		&field.Type,
 		&field.Required,
+		&field.Indexed,
+		&field.Localizable,
 		&field.Placeholder,
 		&field.DefaultValue,
 		&field.SortOrder,
@@ -593,32 +1097,139 @@ func (d *Database) GetCollectionFieldByID(id int) (*CollectionField, error) {
 	return &field, nil
 }
 
-func (d *Database) UpdateCollectionField(id int, name, label, fieldType string, required bool, placeholder, defaultValue string, sortOrder int) error {
+// UpdateCollectionField updates a field definition. When the field is renamed
+// or its type changes, existing item data is migrated in the same transaction.
+func (d *Database) UpdateCollectionField(id int, name, label, fieldType string, required, indexed, localizable bool, placeholder, defaultValue string, sortOrder int, opts FieldMigrationOptions) (*FieldMigrationReport, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	report, err := updateCollectionField(tx, id, name, label, fieldType, required, indexed, localizable, placeholder, defaultValue, sortOrder, opts.DropInvalid)
+	if report != nil {
+		report.DryRun = opts.DryRun
+	}
+	if err != nil {
+		return report, err
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit field update: %w", err)
+	}
+	report.Applied = true
+
+	return report, nil
+}
+
+// updateCollectionField updates a field and migrates item data inside the
+// caller's transaction. When values can't be migrated and dropInvalid isn't
+// set, it returns the report with errFieldConversionFailed.
+func updateCollectionField(tx *sql.Tx, id int, name, label, fieldType string, required, indexed, localizable bool, placeholder, defaultValue string, sortOrder int, dropInvalid bool) (*FieldMigrationReport, error) {
+	var collectionID int
+	var oldName, oldType string
+	var oldIndexed, oldLocalizable bool
+	err := tx.QueryRow(`SELECT collection_id, name, type, indexed, localizable FROM collection_fields WHERE id = ?`, id).Scan(&collectionID, &oldName, &oldType, &oldIndexed, &oldLocalizable)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("collection field not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection field: %w", err)
+	}
+
+	report := &FieldMigrationReport{
+		OldName:  oldName,
+		NewName:  name,
+		OldType:  oldType,
+		NewType:  fieldType,
+		Failures: []FieldConversionFailure{},
+	}
+
 	query := `
 		UPDATE collection_fields
-		SET name = ?, label = ?, type = ?, required = ?, placeholder = ?, default_value = ?, sort_order = ?
+		SET name = ?, label = ?, type = ?, required = ?, indexed = ?, localizable = ?, placeholder = ?, default_value = ?, sort_order = ?
 		WHERE id = ?
 	`
-	result, err := d.db.Exec(query, name, label, fieldType, required, placeholder, defaultValue, sortOrder, id)
-	if err != nil {
-		return fmt.Errorf("failed to update collection field: %w", err)
+	if _, err := tx.Exec(query, name, label, fieldType, required, indexed, localizable, placeholder, defaultValue, sortOrder, id); err != nil {
+		return nil, fmt.Errorf("failed to update collection field: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if name != oldName || fieldType != oldType {
+		err := migrateFieldData(tx, collectionID, oldName, name, oldType, fieldType, report, dropInvalid)
+		if err != nil && err != errFieldConversionFailed {
+			return nil, err
+		}
+		// Translations are checked too so the report lists every failure
+		if terr := migrateTranslationData(tx, collectionID, oldName, name, oldType, fieldType, report, dropInvalid); terr != nil {
+			if terr != errFieldConversionFailed {
+				return nil, terr
+			}
+			err = terr
+		}
+		if err == errFieldConversionFailed {
+			return report, err
+		}
+	}
+
+	// Translated values are only searchable in localizable fields
+	if name != oldName || fieldType != oldType || localizable != oldLocalizable {
+		if err := reindexCollection(tx, collectionID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Comments anchored to a renamed field follow it
+	if name != oldName {
+		if err := renameCommentField(tx, collectionID, oldName, name); err != nil {
+			return nil, err
+		}
+	}
+
+	// A renamed field's index reads a different path
+	if name != oldName || indexed != oldIndexed {
+		if err := syncFieldIndexes(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func (d *Database) DeleteCollectionField(id int) error {
+	tx, err := d.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("collection field not found")
+	if err := deleteCollectionField(tx, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit field deletion: %w", err)
 	}
 
 	return nil
 }
 
-func (d *Database) DeleteCollectionField(id int) error {
+// deleteCollectionField deletes a field inside the caller's transaction
+func deleteCollectionField(tx *sql.Tx, id int) error {
+	var collectionID int
+	err := tx.QueryRow(`SELECT collection_id FROM collection_fields WHERE id = ?`, id).Scan(&collectionID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("collection field not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get collection field: %w", err)
+	}
+
 	query := `DELETE FROM collection_fields WHERE id = ?`
-	result, err := d.db.Exec(query, id)
+	result, err := tx.Exec(query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete collection field: %w", err)
 	}
@@ -632,7 +1243,56 @@ func (d *Database) DeleteCollectionField(id int) error {
 		return fmt.Errorf("collection field not found")
 	}
 
-	return nil
+	if err := syncFieldIndexes(tx); err != nil {
+		return err
+	}
+
+	return reindexCollection(tx, collectionID)
+}
+
+// ReorderCollectionFields rewrites sort_order so fields appear in the given
+// order. fieldIDs must list every field in the collection exactly once; if
+// they don't it returns a message saying why and changes nothing.
+func (d *Database) ReorderCollectionFields(collectionID int, fieldIDs []int) (string, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var fieldCount int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM collection_fields WHERE collection_id = ?`, collectionID).Scan(&fieldCount); err != nil {
+		return "", fmt.Errorf("failed to count fields: %w", err)
+	}
+	if fieldCount != len(fieldIDs) {
+		return fmt.Sprintf("Expected %d field IDs, got %d", fieldCount, len(fieldIDs)), nil
+	}
+
+	seen := make(map[int]bool)
+	for i, fieldID := range fieldIDs {
+		if seen[fieldID] {
+			return fmt.Sprintf("Field %d is listed more than once", fieldID), nil
+		}
+		seen[fieldID] = true
+
+		result, err := tx.Exec(`UPDATE collection_fields SET sort_order = ? WHERE id = ? AND collection_id = ?`, i, fieldID, collectionID)
+		if err != nil {
+			return "", fmt.Errorf("failed to update field order: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return "", fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Sprintf("Field %d does not belong to this collection", fieldID), nil
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit field order: %w", err)
+	}
+
+	return "", nil
 }
 
 type User struct {
@@ -660,6 +1320,8 @@ type Collection struct {
 	Name        string
 	Slug        string
 	Description sql.NullString
+	Singleton   bool
+	PreviewURL  sql.NullString
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -671,6 +1333,8 @@ type CollectionField struct {
 	Label        string
 	Type         string
 	Required     bool
+	Indexed      bool // Whether the field has an index for filtering and sorting
+	Localizable  bool // Whether the field has a value per locale
 	Placeholder  sql.NullString
 	DefaultValue sql.NullString
 	SortOrder    int
@@ -683,16 +1347,48 @@ type Item struct {
 	Slug         sql.NullString `json:"slug,omitempty"`
 	Data         string         `json:"data"` // JSON
 	Status       string         `json:"status"`
+	Version      int            `json:"version"`
+	PublishAt    sql.NullTime   `json:"publishAt,omitempty"`
+	UnpublishAt  sql.NullTime   `json:"unpublishAt,omitempty"`
 	CreatedBy    sql.NullInt64  `json:"createdBy,omitempty"`
 	CreatedAt    time.Time      `json:"createdAt"`
 	UpdatedAt    time.Time      `json:"updatedAt"`
 }
 
 // Items Management
-func (d *Database) CreateItem(collectionID int, slug, data, status string, createdBy int) (*Item, error) {
+// CreateItem adds an item to a collection. schedule may be nil when the item
+// has no publish or unpublish time.
+func (d *Database) CreateItem(collectionID int, slug, data, status string, createdBy int, schedule *ItemSchedule) (*Item, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := createItem(tx, collectionID, slug, data, status, createdBy, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit item: %w", err)
+	}
+
+	return d.GetItem(id)
+}
+
+// createItem inserts an item and its first revision inside the caller's
+// transaction and returns its ID
+func createItem(tx *sql.Tx, collectionID int, slug, data, status string, createdBy int, schedule *ItemSchedule) (int, error) {
+	// Singleton collections hold at most one item, checked in the same statement
 	query := `
 		INSERT INTO items (collection_id, slug, data, status, created_by, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		SELECT ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+		WHERE NOT EXISTS (
+			SELECT 1 FROM collections c
+			WHERE c.id = ? AND c.singleton = 1
+			AND EXISTS (SELECT 1 FROM items WHERE collection_id = c.id AND deleted_at IS NULL)
+		)
 	`
 
 	var slugParam interface{}
@@ -702,23 +1398,43 @@ func (d *Database) CreateItem(collectionID int, slug, data, status string, creat
 		slugParam = nil
 	}
 
-	result, err := d.db.Exec(query, collectionID, slugParam, data, status, createdBy)
+	result, err := tx.Exec(query, collectionID, slugParam, data, status, createdBy, collectionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create item: %w", err)
+		return 0, fmt.Errorf("failed to create item: %w", err)
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return 0, errSingletonItemExists
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get item ID: %w", err)
+		return 0, fmt.Errorf("failed to get item ID: %w", err)
+	}
+
+	if schedule != nil {
+		if err := setItemSchedule(tx, int(id), schedule); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := recordItemRevision(tx, int(id), createdBy); err != nil {
+		return 0, err
 	}
 
-	return d.GetItem(int(id))
+	if err := indexItem(tx, int(id)); err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
 }
 
 func (d *Database) GetItem(id int) (*Item, error) {
 	query := `
-		SELECT id, collection_id, slug, data, status, created_by, created_at, updated_at
-		FROM items WHERE id = ?
+		SELECT id, collection_id, slug, data, status, version, publish_at, unpublish_at, created_by, created_at, updated_at
+		FROM items
+		WHERE id = ? AND deleted_at IS NULL
+		AND collection_id IN (SELECT id FROM collections WHERE deleted_at IS NULL)
 	`
 
 	var item Item
@@ -728,6 +1444,9 @@ func (d *Database) GetItem(id int) (*Item, error) {
 		&item.Slug,
 		&item.Data,
 		&item.Status,
+		&item.Version,
+		&item.PublishAt,
+		&item.UnpublishAt,
 		&item.CreatedBy,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -745,9 +1464,9 @@ func (d *Database) GetItem(id int) (*Item, error) {
 
 func (d *Database) GetItemsByCollection(collectionID int) ([]Item, error) {
 	query := `
-		SELECT id, collection_id, slug, data, status, created_by, created_at, updated_at
+		SELECT id, collection_id, slug, data, status, version, publish_at, unpublish_at, created_by, created_at, updated_at
 		FROM items
-		WHERE collection_id = ?
+		WHERE collection_id = ? AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -766,6 +1485,9 @@ func (d *Database) GetItemsByCollection(collectionID int) ([]Item, error) {
 			&item.Slug,
 			&item.Data,
 			&item.Status,
+			&item.Version,
+			&item.PublishAt,
+			&item.UnpublishAt,
 			&item.CreatedBy,
 			&item.CreatedAt,
 			&item.UpdatedAt,
@@ -788,69 +1510,78 @@ func (d *Database) GetItemsByCollection(collectionID int) ([]Item, error) {
 	return items, nil
 }
 
-func (d *Database) GetItemsByCollectionWithPagination(collectionID int, limit, offset int) ([]Item, error) {
+// UpdateItem saves an item and records the result as a new revision
+// attributed to updatedBy (0 if unknown). When expectedVersion is not 0 the
+// item is only saved if it is still at that version; otherwise
+// errItemVersionConflict is returned. A nil schedule leaves the item's
+// publish and unpublish times unchanged.
+func (d *Database) UpdateItem(id int, slug, data, status string, updatedBy, expectedVersion int, schedule *ItemSchedule) error {
 	query := `
-		SELECT id, collection_id, slug, data, status, created_by, created_at, updated_at
-		FROM items
-		WHERE collection_id = ?
-		ORDER BY created_at DESC
-		LIMIT ? OFFSET ?
+		UPDATE items
+		SET slug = ?, data = ?, status = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)
 	`
 
-	rows, err := d.db.Query(query, collectionID, limit, offset)
+	var slugParam interface{}
+	if slug != "" {
+		slugParam = slug
+	} else {
+		slugParam = nil
+	}
+
+	tx, err := d.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get items: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	var items []Item
-	for rows.Next() {
-		var item Item
-		err := rows.Scan(
-			&item.ID,
-			&item.CollectionID,
-			&item.Slug,
-			&item.Data,
-			&item.Status,
-			&item.CreatedBy,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan item: %w", err)
-		}
-		items = append(items, item)
+	result, err := tx.Exec(query, slugParam, data, status, id, expectedVersion, expectedVersion)
+	if err != nil {
+		return fmt.Errorf("failed to update item: %w", err)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating items: %w", err)
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	// Return empty slice instead of nil if no items found
-	if items == nil {
-		items = []Item{}
+	if rowsAffected == 0 {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM items WHERE id = ? AND deleted_at IS NULL)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check item: %w", err)
+		}
+		if exists {
+			return errItemVersionConflict
+		}
+		return fmt.Errorf("item not found")
 	}
 
-	return items, nil
-}
+	if schedule != nil {
+		if err := setItemSchedule(tx, id, schedule); err != nil {
+			return err
+		}
+	}
 
-func (d *Database) UpdateItem(id int, slug, data, status string) error {
-	query := `
-		UPDATE items
-		SET slug = ?, data = ?, status = ?, updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
-	`
+	if err := recordItemRevision(tx, id, updatedBy); err != nil {
+		return err
+	}
 
-	var slugParam interface{}
-	if slug != "" {
-		slugParam = slug
-	} else {
-		slugParam = nil
+	if err := indexItem(tx, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit item update: %w", err)
 	}
 
-	result, err := d.db.Exec(query, slugParam, data, status, id)
+	return nil
+}
+
+// DeleteItem moves an item to the trash
+func (d *Database) DeleteItem(id int) error {
+	result, err := d.db.Exec(`UPDATE items SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`, id)
 	if err != nil {
-		return fmt.Errorf("failed to update item: %w", err)
+		return fmt.Errorf("failed to delete item: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -865,9 +1596,35 @@ func (d *Database) UpdateItem(id int, slug, data, status string) error {
 	return nil
 }
 
-func (d *Database) DeleteItem(id int) error {
+// PurgeItem permanently removes an item and its history
+func (d *Database) PurgeItem(id int) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM item_revisions WHERE item_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete item revisions: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_transitions WHERE item_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete item transitions: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_locks WHERE item_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete item locks: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_comments WHERE item_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete item comments: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_search WHERE rowid = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete search index entry: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_translations WHERE item_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete item translations: %w", err)
+	}
+
 	query := `DELETE FROM items WHERE id = ?`
-	result, err := d.db.Exec(query, id)
+	result, err := tx.Exec(query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete item: %w", err)
 	}
@@ -881,6 +1638,10 @@ func (d *Database) DeleteItem(id int) error {
 		return fmt.Errorf("item not found")
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit item purge: %w", err)
+	}
+
 	return nil
 }
 // GetStats returns counts for dashboard statistics
@@ -889,7 +1650,7 @@ func (d *Database) GetStats() (map[string]int, error) {
 
 	// Get collection count
 	var collectionCount int
-	err := d.db.QueryRow("SELECT COUNT(*) FROM collections").Scan(&collectionCount)
+	err := d.db.QueryRow("SELECT COUNT(*) FROM collections WHERE deleted_at IS NULL").Scan(&collectionCount)
 	if err != nil {
 		log.Printf("Failed to get collection count: %v", err)
 		collectionCount = 0
@@ -898,7 +1659,7 @@ func (d *Database) GetStats() (map[string]int, error) {
 
 	// Get item count
 	var itemCount int
-	err = d.db.QueryRow("SELECT COUNT(*) FROM items").Scan(&itemCount)
+	err = d.db.QueryRow("SELECT COUNT(*) FROM items WHERE deleted_at IS NULL AND collection_id IN (SELECT id FROM collections WHERE deleted_at IS NULL)").Scan(&itemCount)
 	if err != nil {
 		log.Printf("Failed to get item count: %v", err)
 		itemCount = 0
@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestCreateAPIKey(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.CreateUser("admin", "password", "admin@example.com", "admin"); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	admin, err := db.GetUserByUsername("admin")
+	if err != nil {
+		t.Fatalf("GetUserByUsername: %v", err)
+	}
+
+	// Two keys with the same name, so only the returned row tells them apart
+	ids := make(map[int]bool)
+	for i := 0; i < 2; i++ {
+		key, fullKey, err := db.CreateAPIKey("site", admin.ID)
+		if err != nil {
+			t.Fatalf("CreateAPIKey: %v", err)
+		}
+		validated, err := db.ValidateAPIKey(fullKey)
+		if err != nil || validated == nil {
+			t.Fatalf("ValidateAPIKey = %v, %v", validated, err)
+		}
+		if validated.ID != key.ID || key.KeyPrefix != fullKey[:12]+"..." || !key.IsActive {
+			t.Errorf("CreateAPIKey returned key %d (%s), but the full key is key %d (%s)", key.ID, key.KeyPrefix, validated.ID, validated.KeyPrefix)
+		}
+		ids[key.ID] = true
+	}
+	if len(ids) != 2 {
+		t.Errorf("the keys have IDs %v, want two different ones", ids)
+	}
+
+	missing, err := db.GetAPIKey(999)
+	if err != nil || missing != nil {
+		t.Errorf("GetAPIKey(999) = %v, %v; want nil, nil", missing, err)
+	}
+}
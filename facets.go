@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxFacetValues caps how many distinct values are counted for each facet
+const maxFacetValues = 100
+
+// facetFieldTypes are the field types the public API can count values of
+var facetFieldTypes = map[string]bool{
+	"text":    true,
+	"boolean": true,
+}
+
+// FacetValue is how many items have one value of a field
+type FacetValue struct {
+	Value interface{} `json:"value"`
+	Count int         `json:"count"`
+}
+
+// GetItemFacets counts the values of each of fields among the items matching
+// filter, most common first. Items without a value are not counted.
+func (d *Database) GetItemFacets(filter ItemListFilter, fields []CollectionField) (map[string][]FacetValue, error) {
+	where, args := filter.where()
+
+	facets := map[string][]FacetValue{}
+	for _, field := range fields {
+		query := `
+			SELECT ` + fieldValueExpr("i", field.Name) + ` AS value, COUNT(*) AS count
+			FROM ` + filter.from() + `
+			WHERE ` + where + ` AND value IS NOT NULL AND value != ''
+			GROUP BY value
+			ORDER BY count DESC, value ASC
+			LIMIT ?
+		`
+		rows, err := d.db.Query(query, append(args, maxFacetValues)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count values of %s: %w", field.Name, err)
+		}
+
+		values := []FacetValue{}
+		for rows.Next() {
+			var value interface{}
+			var count int
+			if err := rows.Scan(&value, &count); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan facet value: %w", err)
+			}
+			// JSON booleans come back from SQLite as 1 and 0
+			if field.Type == "boolean" {
+				value = value == int64(1)
+			} else if b, ok := value.([]byte); ok {
+				value = string(b)
+			}
+			values = append(values, FacetValue{Value: value, Count: count})
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error iterating facet values: %w", err)
+		}
+		rows.Close()
+
+		facets[field.Name] = values
+	}
+
+	return facets, nil
+}
+
+// facetFields looks up the fields named in a comma-separated facets
+// parameter. If a name can't be faceted it returns a message saying why.
+func facetFields(fields []CollectionField, param string) ([]CollectionField, string) {
+	byName := map[string]CollectionField{}
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+
+	var facets []CollectionField
+	seen := map[string]bool{}
+	for _, name := range strings.Split(param, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		field, ok := byName[name]
+		if !ok {
+			return nil, fmt.Sprintf("The collection has no field named '%s'", name)
+		}
+		if !facetFieldTypes[field.Type] {
+			return nil, fmt.Sprintf("Field '%s' is %s; only text and boolean fields have facets", name, field.Type)
+		}
+		facets = append(facets, field)
+	}
+
+	if len(facets) == 0 {
+		return nil, "facets must name at least one field"
+	}
+	return facets, ""
+}
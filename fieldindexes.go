@@ -0,0 +1,107 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// unindexableFieldTypes are field types too long to be worth indexing
+var unindexableFieldTypes = map[string]bool{
+	"textarea": true,
+	"markdown": true,
+}
+
+// fieldIndexPrefix starts the name of every index Lodge maintains for an
+// indexed field. The rest of the name is the field's ID.
+const fieldIndexPrefix = "idx_items_field_"
+
+// fieldIndexable reports whether fields of a type can be marked indexed
+func fieldIndexable(fieldType string) bool {
+	return !unindexableFieldTypes[fieldType]
+}
+
+// fieldValueExpr returns the SQL expression reading a field's value from the
+// data column of table, which may be empty for an unqualified column. Queries
+// must use exactly this expression for SQLite to use a field's index.
+func fieldValueExpr(table, name string) string {
+	column := "data"
+	if table != "" {
+		column = table + ".data"
+	}
+	path := `$."` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+	return fmt.Sprintf("json_extract(%s, '%s')", column, strings.ReplaceAll(path, "'", "''"))
+}
+
+// fieldIndexStatement returns the statement creating the index of a field.
+// Leading with collection_id lets one index serve a field in one collection
+// for both filters and sorts.
+func fieldIndexStatement(fieldID int, name string) string {
+	return fmt.Sprintf("CREATE INDEX %s%d ON items(collection_id, %s)", fieldIndexPrefix, fieldID, fieldValueExpr("", name))
+}
+
+// syncFieldIndexes creates the indexes of indexed fields that are missing or
+// out of date, e.g. after a rename, and drops those of fields that are no
+// longer indexed or no longer exist. e may be the database or a transaction.
+func syncFieldIndexes(e interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}) error {
+	wanted := map[string]string{}
+	rows, err := e.Query(`SELECT id, name FROM collection_fields WHERE indexed = 1`)
+	if err != nil {
+		return fmt.Errorf("failed to get indexed fields: %w", err)
+	}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan indexed field: %w", err)
+		}
+		wanted[fmt.Sprintf("%s%d", fieldIndexPrefix, id)] = fieldIndexStatement(id, name)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("error iterating indexed fields: %w", err)
+	}
+	rows.Close()
+
+	existing := map[string]string{}
+	rows, err = e.Query(`SELECT name, sql FROM sqlite_master WHERE type = 'index' AND tbl_name = 'items' AND substr(name, 1, ?) = ?`, len(fieldIndexPrefix), fieldIndexPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to get field indexes: %w", err)
+	}
+	for rows.Next() {
+		var name, statement string
+		if err := rows.Scan(&name, &statement); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan field index: %w", err)
+		}
+		existing[name] = statement
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("error iterating field indexes: %w", err)
+	}
+	rows.Close()
+
+	for name, statement := range existing {
+		if wanted[name] == statement {
+			continue
+		}
+		if _, err := e.Exec("DROP INDEX " + name); err != nil {
+			return fmt.Errorf("failed to drop index %s: %w", name, err)
+		}
+	}
+	for name, statement := range wanted {
+		if existing[name] == statement {
+			continue
+		}
+		if _, err := e.Exec(statement); err != nil {
+			return fmt.Errorf("failed to create index %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
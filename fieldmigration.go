@@ -0,0 +1,281 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errFieldConversionFailed is returned when a field type change would leave
+// items with values that can't be represented in the new type, or a rename
+// would overwrite a value already stored under the new name
+var errFieldConversionFailed = errors.New("some items cannot be migrated to the new field")
+
+// FieldMigrationOptions controls how existing item data is migrated when a
+// field is renamed or its type changes
+type FieldMigrationOptions struct {
+	DryRun      bool // Report what would change without writing anything
+	DropInvalid bool // Remove values that can't be converted instead of failing
+}
+
+// FieldConversionFailure describes an item value that can't be converted
+type FieldConversionFailure struct {
+	ItemID int         `json:"itemId"`
+	Slug   string      `json:"slug,omitempty"`
+	Locale string      `json:"locale,omitempty"` // Set when the value is a translation
+	Value  interface{} `json:"value"`
+	Error  string      `json:"error"`
+}
+
+// FieldMigrationReport summarises the effect of a field update on item data
+type FieldMigrationReport struct {
+	OldName      string                   `json:"oldName"`
+	NewName      string                   `json:"newName"`
+	OldType      string                   `json:"oldType"`
+	NewType      string                   `json:"newType"`
+	ItemsChecked int                      `json:"itemsChecked"`
+	ItemsUpdated int                      `json:"itemsUpdated"`
+	Failures     []FieldConversionFailure `json:"failures"`
+	DryRun       bool                     `json:"dryRun"`
+	Applied      bool                     `json:"applied"`
+}
+
+// migrateFieldData rewrites every item in a collection for a field rename
+// and/or type change. It runs inside the caller's transaction and bumps the
+// version of, and records a revision for, every item it changes.
+func migrateFieldData(tx *sql.Tx, collectionID int, oldName, newName, oldType, newType string, report *FieldMigrationReport, dropInvalid bool) error {
+	rows, err := tx.Query(`SELECT id, slug, data FROM items WHERE collection_id = ? ORDER BY id`, collectionID)
+	if err != nil {
+		return fmt.Errorf("failed to get items: %w", err)
+	}
+
+	type pendingItem struct {
+		id   int
+		data string
+	}
+	var updates []pendingItem
+
+	for rows.Next() {
+		var id int
+		var slug sql.NullString
+		var raw string
+		if err := rows.Scan(&id, &slug, &raw); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan item: %w", err)
+		}
+		report.ItemsChecked++
+
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			// Leave unparseable items alone rather than failing the whole migration
+			continue
+		}
+
+		value, exists := data[oldName]
+		if !exists {
+			continue
+		}
+
+		// Data left behind by a deleted field may already use the new name
+		if existing, taken := data[newName]; taken && newName != oldName {
+			report.Failures = append(report.Failures, FieldConversionFailure{
+				ItemID: id,
+				Slug:   slug.String,
+				Value:  existing,
+				Error:  renameConflictError(newName),
+			})
+			if !dropInvalid {
+				continue
+			}
+			delete(data, oldName)
+			updated, err := json.Marshal(data)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to encode item %d: %w", id, err)
+			}
+			updates = append(updates, pendingItem{id, string(updated)})
+			continue
+		}
+
+		if oldType != newType {
+			converted, err := convertFieldValue(value, oldType, newType)
+			if err != nil {
+				report.Failures = append(report.Failures, FieldConversionFailure{
+					ItemID: id,
+					Slug:   slug.String,
+					Value:  value,
+					Error:  err.Error(),
+				})
+				if !dropInvalid {
+					continue
+				}
+				delete(data, oldName)
+				updated, err := json.Marshal(data)
+				if err != nil {
+					rows.Close()
+					return fmt.Errorf("failed to encode item %d: %w", id, err)
+				}
+				updates = append(updates, pendingItem{id, string(updated)})
+				continue
+			}
+			value = converted
+		}
+
+		delete(data, oldName)
+		data[newName] = value
+		updated, err := json.Marshal(data)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to encode item %d: %w", id, err)
+		}
+		updates = append(updates, pendingItem{id, string(updated)})
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("error iterating items: %w", err)
+	}
+	rows.Close()
+
+	report.ItemsUpdated = len(updates)
+
+	if len(report.Failures) > 0 && !dropInvalid {
+		return errFieldConversionFailed
+	}
+
+	for _, item := range updates {
+		if _, err := tx.Exec(`UPDATE items SET data = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, item.data, item.id); err != nil {
+			return fmt.Errorf("failed to update item %d: %w", item.id, err)
+		}
+		if err := recordItemRevision(tx, item.id, 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renameConflictError describes a value that blocks renaming a field
+func renameConflictError(newName string) string {
+	return fmt.Sprintf("a value named '%s' already exists and would be overwritten", newName)
+}
+
+// convertFieldValue converts a stored JSON value from one field type to
+// another, returning an error if it has no sensible representation
+func convertFieldValue(value interface{}, fromType, toType string) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	// Markdown may be stored as {md, html}; only the source text carries over
+	if fromType == "markdown" && toType != "markdown" {
+		if m, ok := value.(map[string]interface{}); ok {
+			md, _ := m["md"].(string)
+			value = md
+		}
+	}
+
+	switch toType {
+	case "number":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			s := strings.TrimSpace(v)
+			if s == "" {
+				return nil, nil
+			}
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, fmt.Errorf("%q is not a number", v)
+			}
+			return f, nil
+		}
+
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case float64:
+			if v == 0 || v == 1 {
+				return v == 1, nil
+			}
+			return nil, fmt.Errorf("%v is not 0 or 1", v)
+		case string:
+			switch strings.ToLower(strings.TrimSpace(v)) {
+			case "true", "1", "yes", "on":
+				return true, nil
+			case "false", "0", "no", "off", "":
+				return false, nil
+			}
+			return nil, fmt.Errorf("%q is not a boolean", v)
+		}
+
+	case "asset":
+		switch v := value.(type) {
+		case float64:
+			if v > 0 && v == math.Trunc(v) {
+				return int(v), nil
+			}
+			return nil, fmt.Errorf("%v is not an asset ID", v)
+		case string:
+			s := strings.TrimSpace(v)
+			if s == "" {
+				return nil, nil
+			}
+			id, err := strconv.Atoi(s)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("%q is not an asset ID", v)
+			}
+			return id, nil
+		}
+
+	case "date":
+		v, ok := value.(string)
+		if !ok {
+			break
+		}
+		s := strings.TrimSpace(v)
+		if s == "" {
+			return "", nil
+		}
+		for _, layout := range []string{"2006-01-02", time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04:05"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t.Format("2006-01-02"), nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not a date (expected YYYY-MM-DD)", v)
+
+	case "markdown":
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case map[string]interface{}:
+			return v, nil
+		}
+		return stringifyFieldValue(value)
+
+	default:
+		// text, textarea, email, url - all strings
+		return stringifyFieldValue(value)
+	}
+
+	return nil, fmt.Errorf("cannot convert %s value to %s", fromType, toType)
+}
+
+// stringifyFieldValue renders scalar values as text
+func stringifyFieldValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return nil, fmt.Errorf("cannot convert %T value to text", value)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertFieldValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		from, to string
+		want     interface{}
+		wantErr  bool
+	}{
+		{"nil stays nil", nil, "text", "number", nil, false},
+		{"number from text", " 3.5 ", "text", "number", 3.5, false},
+		{"empty text to number", "  ", "text", "number", nil, false},
+		{"text that isn't a number", "abc", "text", "number", nil, true},
+		{"infinity isn't a number", "Inf", "text", "number", nil, true},
+		{"boolean to number", true, "boolean", "number", nil, true},
+		{"yes to boolean", "Yes", "text", "boolean", true, false},
+		{"off to boolean", "off", "text", "boolean", false, false},
+		{"empty text to boolean", "", "text", "boolean", false, false},
+		{"one to boolean", 1.0, "number", "boolean", true, false},
+		{"two to boolean", 2.0, "number", "boolean", nil, true},
+		{"text that isn't a boolean", "maybe", "text", "boolean", nil, true},
+		{"asset from number", 7.0, "number", "asset", 7, false},
+		{"asset from text", "12", "text", "asset", 12, false},
+		{"fractional asset ID", 1.5, "number", "asset", nil, true},
+		{"negative asset ID", "-3", "text", "asset", nil, true},
+		{"date", "2024-02-29", "text", "date", "2024-02-29", false},
+		{"date from timestamp", "2024-02-29T10:00:00Z", "text", "date", "2024-02-29", false},
+		{"date with minutes", "2024-02-29T10:00", "text", "date", "2024-02-29", false},
+		{"empty date", " ", "text", "date", "", false},
+		{"text that isn't a date", "29/02/2024", "text", "date", nil, true},
+		{"number to date", 20240229.0, "number", "date", nil, true},
+		{"number to text", 1.25, "number", "text", "1.25", false},
+		{"boolean to text", false, "boolean", "text", "false", false},
+		{"markdown to text", map[string]interface{}{"md": "# Hi", "html": "<h1>Hi</h1>"}, "markdown", "textarea", "# Hi", false},
+		{"text to markdown", "*hi*", "text", "markdown", "*hi*", false},
+		{"number to markdown", 2.0, "number", "markdown", "2", false},
+		{"markdown stays markdown", map[string]interface{}{"md": "a", "html": "<p>a</p>"}, "markdown", "markdown", map[string]interface{}{"md": "a", "html": "<p>a</p>"}, false},
+		{"list to text", []interface{}{"a"}, "text", "text", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertFieldValue(tt.value, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("convertFieldValue(%v, %s, %s) error = %v, wantErr %t", tt.value, tt.from, tt.to, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("convertFieldValue(%v, %s, %s) = %#v, want %#v", tt.value, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
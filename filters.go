@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldFilter narrows a listing down to items whose value of a field is one
+// of Values
+type FieldFilter struct {
+	Field  CollectionField
+	Values []interface{}
+}
+
+// ItemListSort orders a listing by the value of a field
+type ItemListSort struct {
+	Field      CollectionField
+	Descending bool
+}
+
+// ItemListFilter is the set of items a public API listing covers
+type ItemListFilter struct {
+	CollectionID int           // 0 covers every collection, as searches may
+	ItemID       int           // Set to cover a single item, as item previews do
+	Search       string        // Full-text search query, if any
+	Fields       []FieldFilter // Items must match every one
+	IncludeAll   bool          // Include items whatever their status or schedule, as previews do
+	Now          time.Time     // The time used to decide which scheduled items are live
+}
+
+// from returns the tables the filter selects from, with items aliased as i
+func (f *ItemListFilter) from() string {
+	if f.Search != "" {
+		return "items i JOIN item_search ON item_search.rowid = i.id"
+	}
+	return "items i"
+}
+
+// where returns the SQL condition selecting the filter's items, and its
+// arguments. It matches the rules of the public API: published items that
+// are live now, unless IncludeAll is set.
+func (f *ItemListFilter) where() (string, []interface{}) {
+	conditions := []string{"i.deleted_at IS NULL"}
+	var args []interface{}
+
+	if f.CollectionID != 0 {
+		conditions = append(conditions, "i.collection_id = ?")
+		args = append(args, f.CollectionID)
+	}
+	if f.ItemID != 0 {
+		conditions = append(conditions, "i.id = ?")
+		args = append(args, f.ItemID)
+	}
+
+	if !f.IncludeAll {
+		now := f.Now.UTC().Format(scheduleTimeLayout)
+		conditions = append(conditions,
+			"i.status = 'published'",
+			"(i.publish_at IS NULL OR i.publish_at <= ?)",
+			"(i.unpublish_at IS NULL OR i.unpublish_at > ?)",
+		)
+		args = append(args, now, now)
+	}
+
+	if f.Search != "" {
+		conditions = append(conditions, "item_search MATCH ?")
+		args = append(args, searchMatchQuery(f.Search))
+	}
+
+	for _, filter := range f.Fields {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(filter.Values)), ", ")
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", fieldValueExpr("i", filter.Field.Name), placeholders))
+		args = append(args, filter.Values...)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// ListItems returns a page of the items matching filter. Items are ordered by
+// sort if given, else by relevance when searching, else newest first. When
+// searching, the snippet of matching text of each item is returned by ID.
+func (d *Database) ListItems(filter ItemListFilter, sort *ItemListSort, limit, offset int) ([]Item, map[int]string, error) {
+	where, args := filter.where()
+
+	snippet := "''"
+	orderBy := "i.created_at DESC, i.id DESC"
+	if filter.Search != "" {
+		snippet = "snippet(item_search, 0, ?, ?, '…', 12)"
+		args = append([]interface{}{snippetOpen, snippetClose}, args...)
+		orderBy = "bm25(item_search) ASC, i.id ASC"
+	}
+	if sort != nil {
+		direction := "ASC"
+		if sort.Descending {
+			direction = "DESC"
+		}
+		orderBy = fmt.Sprintf("%s %s, i.id %s", fieldValueExpr("i", sort.Field.Name), direction, direction)
+	}
+
+	query := `
+		SELECT i.id, i.collection_id, i.slug, i.data, i.status, i.version, i.publish_at, i.unpublish_at, i.created_by, i.created_at, i.updated_at,
+			` + snippet + `
+		FROM ` + filter.from() + `
+		WHERE ` + where + `
+		ORDER BY ` + orderBy + `
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := d.db.Query(query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []Item{}
+	snippets := map[int]string{}
+	for rows.Next() {
+		var item Item
+		var text string
+		err := rows.Scan(
+			&item.ID,
+			&item.CollectionID,
+			&item.Slug,
+			&item.Data,
+			&item.Status,
+			&item.Version,
+			&item.PublishAt,
+			&item.UnpublishAt,
+			&item.CreatedBy,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&text,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan item: %w", err)
+		}
+		if filter.Search != "" {
+			snippets[item.ID] = highlightSnippet(text)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating items: %w", err)
+	}
+
+	return items, snippets, nil
+}
+
+// parseFieldFilterValue converts a value from a query string to how a field
+// of the given type is stored, so it compares equal in SQL
+func parseFieldFilterValue(fieldType, value string) (interface{}, error) {
+	switch fieldType {
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		// JSON booleans read from SQLite are 1 and 0
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return value, nil
+	}
+}
+
+// parseItemListQuery reads the field filters and sort of a listing from its
+// query string: filter[name]=value, repeated to match any of several values,
+// and sort=name or sort=-name for descending order. Only indexed fields can
+// be used, so public queries never scan a whole collection. If the query
+// can't be used it returns a message saying why.
+func parseItemListQuery(fields []CollectionField, query url.Values) ([]FieldFilter, *ItemListSort, string) {
+	byName := map[string]CollectionField{}
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+	lookup := func(name string) (CollectionField, string) {
+		field, ok := byName[name]
+		if !ok {
+			return field, fmt.Sprintf("The collection has no field named '%s'", name)
+		}
+		if !field.Indexed {
+			return field, fmt.Sprintf("Field '%s' is not indexed; only indexed fields can be filtered and sorted by", name)
+		}
+		return field, ""
+	}
+
+	var filters []FieldFilter
+	for key, values := range query {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]")
+		field, problem := lookup(name)
+		if problem != "" {
+			return nil, nil, problem
+		}
+
+		filter := FieldFilter{Field: field}
+		for _, value := range values {
+			parsed, err := parseFieldFilterValue(field.Type, value)
+			if err != nil {
+				return nil, nil, fmt.Sprintf("Invalid value for %s field '%s': %q", field.Type, name, value)
+			}
+			filter.Values = append(filter.Values, parsed)
+		}
+		filters = append(filters, filter)
+	}
+
+	var sort *ItemListSort
+	if value := query.Get("sort"); value != "" {
+		name := strings.TrimPrefix(value, "-")
+		field, problem := lookup(name)
+		if problem != "" {
+			return nil, nil, problem
+		}
+		sort = &ItemListSort{Field: field, Descending: strings.HasPrefix(value, "-")}
+	}
+
+	return filters, sort, ""
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"database/sql"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestListItemsVisibility(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.CreateUser("author", "password", "author@example.com", "admin"); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	author, err := db.GetUserByUsername("author")
+	if err != nil {
+		t.Fatalf("GetUserByUsername: %v", err)
+	}
+	collection, err := db.CreateCollection("Posts", "posts", "", false, "")
+	if err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	at := func(d time.Duration) sql.NullTime {
+		return sql.NullTime{Time: now.Add(d), Valid: true}
+	}
+	items := []struct {
+		slug     string
+		status   string
+		schedule *ItemSchedule
+		public   bool
+	}{
+		{"published", "published", nil, true},
+		{"draft", "draft", nil, false},
+		{"in-review", "in_review", nil, false},
+		{"archived", "archived", nil, false},
+		{"in-window", "published", &ItemSchedule{PublishAt: at(-time.Hour), UnpublishAt: at(time.Hour)}, true},
+		{"not-yet-published", "published", &ItemSchedule{PublishAt: at(time.Hour)}, false},
+		{"unpublished", "published", &ItemSchedule{UnpublishAt: at(-time.Hour)}, false},
+		{"scheduled-draft", "draft", &ItemSchedule{PublishAt: at(-time.Hour)}, false},
+	}
+
+	var public, all []string
+	for _, tt := range items {
+		item, err := db.CreateItem(collection.ID, tt.slug, `{}`, tt.status, author.ID, tt.schedule)
+		if err != nil {
+			t.Fatalf("CreateItem(%q): %v", tt.slug, err)
+		}
+		if got := item.isPublic(now); got != tt.public {
+			t.Errorf("%s: isPublic = %t, want %t", tt.slug, got, tt.public)
+		}
+		if tt.public {
+			public = append(public, tt.slug)
+		}
+		all = append(all, tt.slug)
+	}
+
+	slugs := func(filter ItemListFilter) []string {
+		t.Helper()
+		listed, _, err := db.ListItems(filter, nil, 100, 0)
+		if err != nil {
+			t.Fatalf("ListItems: %v", err)
+		}
+		// Listings are newest first
+		got := []string{}
+		for i := len(listed) - 1; i >= 0; i-- {
+			got = append(got, listed[i].Slug.String)
+		}
+		return got
+	}
+
+	if got := slugs(ItemListFilter{CollectionID: collection.ID, Now: now}); !reflect.DeepEqual(got, public) {
+		t.Errorf("public listing = %v, want %v", got, public)
+	}
+	if got := slugs(ItemListFilter{CollectionID: collection.ID, IncludeAll: true, Now: now}); !reflect.DeepEqual(got, all) {
+		t.Errorf("preview listing = %v, want %v", got, all)
+	}
+}
+
+func TestParseItemListQuery(t *testing.T) {
+	category := CollectionField{Name: "category", Type: "text", Indexed: true}
+	price := CollectionField{Name: "price", Type: "number", Indexed: true}
+	featured := CollectionField{Name: "featured", Type: "boolean", Indexed: true}
+	body := CollectionField{Name: "body", Type: "textarea"}
+	fields := []CollectionField{category, price, featured, body}
+
+	tests := []struct {
+		name    string
+		query   string
+		filters []FieldFilter
+		sort    *ItemListSort
+		problem string
+	}{
+		{name: "nothing", query: "limit=10&q=mug"},
+		{name: "text filter", query: "filter[category]=mugs", filters: []FieldFilter{{category, []interface{}{"mugs"}}}},
+		{name: "repeated filter", query: "filter[category]=mugs&filter[category]=cups", filters: []FieldFilter{{category, []interface{}{"mugs", "cups"}}}},
+		{name: "number filter", query: "filter[price]=12.5", filters: []FieldFilter{{price, []interface{}{12.5}}}},
+		{name: "boolean filters", query: "filter[featured]=true&filter[featured]=0", filters: []FieldFilter{{featured, []interface{}{1, 0}}}},
+		{
+			name:    "several filters and a sort",
+			query:   "filter[price]=3&filter[category]=mugs&sort=-price",
+			filters: []FieldFilter{{category, []interface{}{"mugs"}}, {price, []interface{}{3.0}}},
+			sort:    &ItemListSort{Field: price, Descending: true},
+		},
+		{name: "ascending sort", query: "sort=category", sort: &ItemListSort{Field: category}},
+		{name: "empty sort is ignored", query: "sort="},
+		{name: "unknown filter field", query: "filter[colour]=red", problem: "The collection has no field named 'colour'"},
+		{name: "empty filter name", query: "filter[]=red", problem: "The collection has no field named ''"},
+		{name: "unindexed filter field", query: "filter[body]=x", problem: "Field 'body' is not indexed; only indexed fields can be filtered and sorted by"},
+		{name: "unindexed sort field", query: "sort=-body", problem: "Field 'body' is not indexed; only indexed fields can be filtered and sorted by"},
+		{name: "unknown sort field", query: "sort=--price", problem: "The collection has no field named '-price'"},
+		{name: "invalid number", query: "filter[price]=cheap", problem: `Invalid value for number field 'price': "cheap"`},
+		{name: "invalid boolean", query: "filter[featured]=yes", problem: `Invalid value for boolean field 'featured': "yes"`},
+		{name: "not a filter", query: "filter[category=mugs&filtercategory]=mugs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			filters, sortBy, problem := parseItemListQuery(fields, query)
+			if problem != tt.problem {
+				t.Fatalf("problem = %q, want %q", problem, tt.problem)
+			}
+			// Filters come out in query map order
+			sort.Slice(filters, func(i, j int) bool { return filters[i].Field.Name < filters[j].Field.Name })
+			if !reflect.DeepEqual(filters, tt.filters) {
+				t.Errorf("filters = %+v, want %+v", filters, tt.filters)
+			}
+			if !reflect.DeepEqual(sortBy, tt.sort) {
+				t.Errorf("sort = %+v, want %+v", sortBy, tt.sort)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Limits to keep image transformations from being used to exhaust the server
+const (
+	maxImageDimension    = 4096     // Largest width or height that can be requested
+	maxSourceImagePixels = 50000000 // Refuse to decode images larger than this
+	maxVariantsPerAsset  = 32       // Cached derived images kept per source file
+	defaultImageQuality  = 85
+	imageQualityStep     = 5 // Requested qualities are rounded to a multiple of this
+)
+
+// imageSizes are the widths and heights that can be requested. A fixed set
+// keeps the number of distinct variants small, so caches stay warm and
+// random sizes can't be used to churn them.
+var imageSizes = []int{
+	16, 32, 48, 64, 80, 96, 128, 150, 160, 192, 200, 240, 256, 300, 320, 360,
+	400, 480, 500, 512, 600, 640, 720, 768, 800, 960, 1024, 1080, 1200, 1280,
+	1440, 1536, 1600, 1920, 2048, 2400, 2560, 3072, 3840, maxImageDimension,
+}
+
+// transformableImageTypes lists the source formats that can be decoded
+var transformableImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// ImageTransform describes a requested derived image
+type ImageTransform struct {
+	Width   int
+	Height  int
+	Fit     string // cover, contain or fill
+	Format  string // jpeg or png
+	Quality int
+}
+
+// hasImageTransformParams reports whether the query asks for a derived image
+func hasImageTransformParams(query url.Values) bool {
+	for _, key := range []string{"w", "h", "fit", "format", "q"} {
+		if query.Has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseImageTransform validates the transformation query parameters. The
+// source MIME type decides the output format when none is requested.
+func parseImageTransform(query url.Values, sourceType string) (*ImageTransform, error) {
+	t := &ImageTransform{
+		Fit:     "cover",
+		Quality: defaultImageQuality,
+	}
+
+	parseDimension := func(key string) (int, error) {
+		value := query.Get(key)
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("%s must be a positive integer", key)
+		}
+		if n > maxImageDimension {
+			return 0, fmt.Errorf("%s must not exceed %d", key, maxImageDimension)
+		}
+		i := sort.SearchInts(imageSizes, n)
+		if imageSizes[i] != n {
+			if i == 0 {
+				return 0, fmt.Errorf("%s must be one of the supported image sizes; the smallest is %d", key, imageSizes[0])
+			}
+			return 0, fmt.Errorf("%s must be one of the supported image sizes; the nearest are %d and %d", key, imageSizes[i-1], imageSizes[i])
+		}
+		return n, nil
+	}
+
+	var err error
+	if t.Width, err = parseDimension("w"); err != nil {
+		return nil, err
+	}
+	if t.Height, err = parseDimension("h"); err != nil {
+		return nil, err
+	}
+
+	if fit := query.Get("fit"); fit != "" {
+		switch fit {
+		case "cover", "contain", "fill":
+			t.Fit = fit
+		default:
+			return nil, fmt.Errorf("fit must be one of cover, contain or fill")
+		}
+	}
+
+	switch format := strings.ToLower(query.Get("format")); format {
+	case "":
+		if sourceType == "image/jpeg" {
+			t.Format = "jpeg"
+		} else {
+			t.Format = "png"
+		}
+	case "jpeg", "jpg":
+		t.Format = "jpeg"
+	case "png":
+		t.Format = "png"
+	case "webp":
+		return nil, fmt.Errorf("webp output is not supported; use jpeg or png")
+	default:
+		return nil, fmt.Errorf("format must be jpeg or png")
+	}
+
+	if q := query.Get("q"); q != "" {
+		quality, err := strconv.Atoi(q)
+		if err != nil || quality < 1 || quality > 100 {
+			return nil, fmt.Errorf("q must be an integer between 1 and 100")
+		}
+		t.Quality = max(imageQualityStep, (quality+imageQualityStep/2)/imageQualityStep*imageQualityStep)
+	}
+
+	return t, nil
+}
+
+// cacheKey identifies a derived image for a given source file
+func (t *ImageTransform) cacheKey() string {
+	key := fmt.Sprintf("%dx%d-%s", t.Width, t.Height, t.Fit)
+	if t.Format == "jpeg" {
+		key += fmt.Sprintf("-q%d", t.Quality)
+	}
+	return key + "." + t.Format
+}
+
+func (t *ImageTransform) contentType() string {
+	return "image/" + t.Format
+}
+
+// targetSize computes the output size and the source rectangle to scale from.
+// Images are never enlarged beyond their original dimensions.
+func (t *ImageTransform) targetSize(src image.Rectangle) (image.Rectangle, image.Rectangle) {
+	sw, sh := src.Dx(), src.Dy()
+	w, h := t.Width, t.Height
+
+	switch {
+	case w == 0 && h == 0:
+		return image.Rect(0, 0, sw, sh), src
+	case h == 0:
+		w = min(w, sw)
+		h = max(1, sh*w/sw)
+		return image.Rect(0, 0, w, h), src
+	case w == 0:
+		h = min(h, sh)
+		w = max(1, sw*h/sh)
+		return image.Rect(0, 0, w, h), src
+	}
+
+	switch t.Fit {
+	case "fill":
+		return image.Rect(0, 0, min(w, sw), min(h, sh)), src
+	case "contain":
+		// Scale down so the whole image fits within the box
+		scale := min(float64(w)/float64(sw), float64(h)/float64(sh), 1)
+		return image.Rect(0, 0, max(1, int(float64(sw)*scale)), max(1, int(float64(sh)*scale))), src
+	default:
+		// cover: crop the centre of the source to the requested aspect ratio
+		scale := min(max(float64(w)/float64(sw), float64(h)/float64(sh)), 1)
+		w = min(w, max(1, int(float64(sw)*scale)))
+		h = min(h, max(1, int(float64(sh)*scale)))
+		cropW := min(sw, int(float64(w)/scale))
+		cropH := min(sh, int(float64(h)/scale))
+		x0 := src.Min.X + (sw-cropW)/2
+		y0 := src.Min.Y + (sh-cropH)/2
+		return image.Rect(0, 0, w, h), image.Rect(x0, y0, x0+cropW, y0+cropH)
+	}
+}
+
+// transformImage decodes, resizes and re-encodes an image
+func transformImage(src io.ReadSeeker, t *ImageTransform) ([]byte, error) {
+	config, _, err := image.DecodeConfig(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image header: %w", err)
+	}
+	if config.Width*config.Height > maxSourceImagePixels {
+		return nil, fmt.Errorf("source image is too large to transform")
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	dstRect, srcRect := t.targetSize(img.Bounds())
+	dst := image.NewRGBA(dstRect)
+
+	// JPEG has no alpha channel, so flatten transparent images onto white
+	if t.Format == "jpeg" {
+		draw.Draw(dst, dstRect, image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(dst, dstRect, img, srcRect, draw.Over, nil)
+	} else {
+		draw.CatmullRom.Scale(dst, dstRect, img, srcRect, draw.Src, nil)
+	}
+
+	var buf bytes.Buffer
+	switch t.Format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: t.Quality})
+	default:
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// derivedImageDir holds all cached variants of a source file
+func (s *Server) derivedImageDir(hash string) string {
+	return filepath.Join(s.dataDir, "cache", "images", hash[:2], hash)
+}
+
+// getDerivedImage returns a transformed image, generating and caching it on
+// disk the first time a set of parameters is requested.
+func (s *Server) getDerivedImage(asset *Asset, t *ImageTransform) ([]byte, error) {
+	dir := s.derivedImageDir(asset.Hash)
+	cachePath := filepath.Join(dir, t.cacheKey())
+
+	if data, err := os.ReadFile(cachePath); err == nil {
+		// The modification time records when a variant was last used
+		now := time.Now()
+		os.Chtimes(cachePath, now, now)
+		return data, nil
+	}
+
+	file, err := s.storage.Get(assetKey(asset.Hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open asset file: %w", err)
+	}
+	source, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read asset file: %w", err)
+	}
+
+	data, err := transformImage(bytes.NewReader(source), t)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Printf("Warning: Failed to create image cache directory: %v", err)
+		return data, nil
+	}
+	evictDerivedImages(dir, maxVariantsPerAsset-1)
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		log.Printf("Warning: Failed to cache derived image: %v", err)
+		return data, nil
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		log.Printf("Warning: Failed to cache derived image for asset %d", asset.ID)
+		return data, nil
+	}
+	if err := os.Rename(tmp.Name(), cachePath); err != nil {
+		os.Remove(tmp.Name())
+		log.Printf("Warning: Failed to cache derived image: %v", err)
+	}
+
+	return data, nil
+}
+
+// evictDerivedImages removes the least recently used variants in dir until
+// at most keep remain
+func evictDerivedImages(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	type variant struct {
+		path   string
+		usedAt time.Time
+	}
+	var variants []variant
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".tmp-") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		variants = append(variants, variant{filepath.Join(dir, entry.Name()), info.ModTime()})
+	}
+	if len(variants) <= keep {
+		return
+	}
+
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].usedAt.Before(variants[j].usedAt)
+	})
+	for _, v := range variants[:len(variants)-keep] {
+		if err := os.Remove(v.path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Warning: Failed to evict derived image: %v", err)
+		}
+	}
+}
+
+// serveTransformedAsset handles /assets/{id}?w=&h=&fit=&format=&q=
+func (s *Server) serveTransformedAsset(w http.ResponseWriter, r *http.Request, asset *Asset, cacheControl string) {
+	if !transformableImageTypes[asset.MimeType] {
+		http.Error(w, "Asset is not a transformable image", http.StatusBadRequest)
+		return
+	}
+
+	t, err := parseImageTransform(r.URL.Query(), asset.MimeType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	etag := fmt.Sprintf(`"%s-%s"`, asset.Hash, t.cacheKey())
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", etag)
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	data, err := s.getDerivedImage(asset, t)
+	if err != nil {
+		log.Printf("Error transforming asset %d: %v", asset.ID, err)
+		http.Error(w, "Failed to transform image", http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", t.contentType())
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(w, r, "", asset.CreatedAt, bytes.NewReader(data))
+}
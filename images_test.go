@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseImageTransform(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		sourceType string
+		want       ImageTransform
+		wantErr    string
+	}{
+		{"defaults for jpeg", "w=400", "image/jpeg", ImageTransform{Width: 400, Fit: "cover", Format: "jpeg", Quality: 85}, ""},
+		{"defaults for png", "h=300", "image/png", ImageTransform{Height: 300, Fit: "cover", Format: "png", Quality: 85}, ""},
+		{"webp source becomes png", "w=64", "image/webp", ImageTransform{Width: 64, Fit: "cover", Format: "png", Quality: 85}, ""},
+		{"all parameters", "w=800&h=600&fit=contain&format=JPG&q=70", "image/png", ImageTransform{Width: 800, Height: 600, Fit: "contain", Format: "jpeg", Quality: 70}, ""},
+		{"quality rounds to a step", "q=83", "image/jpeg", ImageTransform{Fit: "cover", Format: "jpeg", Quality: 85}, ""},
+		{"lowest quality", "q=1", "image/jpeg", ImageTransform{Fit: "cover", Format: "jpeg", Quality: 5}, ""},
+		{"largest size", "w=4096", "image/jpeg", ImageTransform{Width: 4096, Fit: "cover", Format: "jpeg", Quality: 85}, ""},
+		{"unsupported size", "w=401", "image/jpeg", ImageTransform{}, "nearest are 400 and 480"},
+		{"below the smallest size", "h=10", "image/jpeg", ImageTransform{}, "the smallest is 16"},
+		{"too large", "w=5000", "image/jpeg", ImageTransform{}, "must not exceed 4096"},
+		{"zero width", "w=0", "image/jpeg", ImageTransform{}, "positive integer"},
+		{"non-numeric height", "h=big", "image/jpeg", ImageTransform{}, "positive integer"},
+		{"unknown fit", "fit=crop", "image/jpeg", ImageTransform{}, "fit must be"},
+		{"webp output", "format=webp", "image/jpeg", ImageTransform{}, "webp output is not supported"},
+		{"unknown format", "format=gif", "image/jpeg", ImageTransform{}, "format must be"},
+		{"quality out of range", "q=101", "image/jpeg", ImageTransform{}, "between 1 and 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parseImageTransform(query, tt.sourceType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseImageTransform(%q) = %v, want an error containing %q", tt.query, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseImageTransform(%q): %v", tt.query, err)
+			}
+			if *got != tt.want {
+				t.Errorf("parseImageTransform(%q) = %+v, want %+v", tt.query, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageTransformTargetSize(t *testing.T) {
+	src := image.Rect(0, 0, 1000, 500)
+
+	tests := []struct {
+		name    string
+		t       ImageTransform
+		dst     image.Rectangle
+		srcRect image.Rectangle
+	}{
+		{"no size keeps the original", ImageTransform{Fit: "cover"}, image.Rect(0, 0, 1000, 500), src},
+		{"width keeps the aspect ratio", ImageTransform{Width: 400, Fit: "cover"}, image.Rect(0, 0, 400, 200), src},
+		{"height keeps the aspect ratio", ImageTransform{Height: 100, Fit: "cover"}, image.Rect(0, 0, 200, 100), src},
+		{"width is never enlarged", ImageTransform{Width: 2048, Fit: "cover"}, image.Rect(0, 0, 1000, 500), src},
+		{"height is never enlarged", ImageTransform{Height: 1024, Fit: "cover"}, image.Rect(0, 0, 1000, 500), src},
+		{"cover crops the centre", ImageTransform{Width: 200, Height: 200, Fit: "cover"}, image.Rect(0, 0, 200, 200), image.Rect(250, 0, 750, 500)},
+		{"cover a box larger than the source", ImageTransform{Width: 2048, Height: 2048, Fit: "cover"}, image.Rect(0, 0, 1000, 500), image.Rect(0, 0, 1000, 500)},
+		{"contain fits inside the box", ImageTransform{Width: 200, Height: 200, Fit: "contain"}, image.Rect(0, 0, 200, 100), src},
+		{"contain is never enlarged", ImageTransform{Width: 2048, Height: 2048, Fit: "contain"}, image.Rect(0, 0, 1000, 500), src},
+		{"fill stretches", ImageTransform{Width: 200, Height: 200, Fit: "fill"}, image.Rect(0, 0, 200, 200), src},
+		{"fill is never enlarged", ImageTransform{Width: 2048, Height: 300, Fit: "fill"}, image.Rect(0, 0, 1000, 300), src},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst, srcRect := tt.t.targetSize(src)
+			if dst != tt.dst || srcRect != tt.srcRect {
+				t.Errorf("targetSize = %v from %v, want %v from %v", dst, srcRect, tt.dst, tt.srcRect)
+			}
+		})
+	}
+}
+
+func TestDerivedImagesEvictLeastRecentlyUsed(t *testing.T) {
+	dataDir := t.TempDir()
+	s := &Server{dataDir: dataDir, storage: NewFSStorage(filepath.Join(dataDir, "assets"))}
+
+	var source bytes.Buffer
+	if err := png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 64, 64))); err != nil {
+		t.Fatal(err)
+	}
+	asset := &Asset{ID: 1, Hash: strings.Repeat("ab", 32), MimeType: "image/png"}
+	if err := s.storage.Put(assetKey(asset.Hash), bytes.NewReader(source.Bytes()), int64(source.Len()), asset.MimeType); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	dir := s.derivedImageDir(asset.Hash)
+	variant := func(quality int) *ImageTransform {
+		return &ImageTransform{Width: 16, Fit: "cover", Format: "jpeg", Quality: quality}
+	}
+
+	// Fill the cache, oldest first
+	for i := 0; i < maxVariantsPerAsset; i++ {
+		tr := variant(i + 1)
+		if _, err := s.getDerivedImage(asset, tr); err != nil {
+			t.Fatalf("getDerivedImage: %v", err)
+		}
+		used := time.Now().Add(time.Duration(i-maxVariantsPerAsset) * time.Minute)
+		if err := os.Chtimes(filepath.Join(dir, tr.cacheKey()), used, used); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Reading the oldest variant makes it the most recently used
+	if _, err := s.getDerivedImage(asset, variant(1)); err != nil {
+		t.Fatalf("getDerivedImage: %v", err)
+	}
+	if _, err := s.getDerivedImage(asset, variant(100)); err != nil {
+		t.Fatalf("getDerivedImage with a full cache: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != maxVariantsPerAsset {
+		t.Errorf("cache holds %d variants, want %d", len(entries), maxVariantsPerAsset)
+	}
+	for _, tt := range []struct {
+		quality int
+		cached  bool
+	}{{1, true}, {2, false}, {3, true}, {100, true}} {
+		_, err := os.Stat(filepath.Join(dir, variant(tt.quality).cacheKey()))
+		if cached := err == nil; cached != tt.cached {
+			t.Errorf("variant with quality %d cached = %t, want %t", tt.quality, cached, tt.cached)
+		}
+	}
+}
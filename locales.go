@@ -0,0 +1,947 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// localeSettingsKey is the settings key the site's locales are stored under
+const localeSettingsKey = "locales"
+
+// errDefaultLocaleChanged is returned when new locale settings would change
+// the default locale, which item data is written in
+var errDefaultLocaleChanged = errors.New("the default locale can't be changed")
+
+// localeInUseError is returned when new locale settings would remove locales
+// that items still have translations in
+type localeInUseError struct {
+	locales []string
+}
+
+func (e *localeInUseError) Error() string {
+	quoted := make([]string, len(e.locales))
+	for i, locale := range e.locales {
+		quoted[i] = "'" + locale + "'"
+	}
+	return fmt.Sprintf("Items have translations in %s; remove them before removing the locale", strings.Join(quoted, ", "))
+}
+
+// localeCodePattern matches BCP 47 style locale codes such as en, de or pt-BR
+var localeCodePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// LocaleSettings lists the locales content is published in. Item data holds
+// the values of the default locale; other locales only store the values of
+// localizable fields.
+type LocaleSettings struct {
+	Locales   []string            `json:"locales"`   // The first is the default locale
+	Fallbacks map[string][]string `json:"fallbacks"` // Locales tried in order when a value is missing, before the default
+}
+
+// defaultLocale returns the locale item data is written in, or "" if no
+// locales are configured
+func (l *LocaleSettings) defaultLocale() string {
+	if len(l.Locales) == 0 {
+		return ""
+	}
+	return l.Locales[0]
+}
+
+// has reports whether locale is one of the site's locales
+func (l *LocaleSettings) has(locale string) bool {
+	for _, code := range l.Locales {
+		if code == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// chain returns the locales to read a value from, in order: locale itself,
+// its fallbacks, then the default locale
+func (l *LocaleSettings) chain(locale string) []string {
+	chain := []string{locale}
+	seen := map[string]bool{locale: true, "": true}
+	for _, code := range append(l.Fallbacks[locale], l.defaultLocale()) {
+		if !seen[code] {
+			seen[code] = true
+			chain = append(chain, code)
+		}
+	}
+	return chain
+}
+
+// validate checks the locale codes and fallbacks
+func (l *LocaleSettings) validate() error {
+	seen := map[string]bool{}
+	for _, code := range l.Locales {
+		if !localeCodePattern.MatchString(code) {
+			return fmt.Errorf("'%s' is not a valid locale code", code)
+		}
+		if seen[code] {
+			return fmt.Errorf("locale '%s' is listed more than once", code)
+		}
+		seen[code] = true
+	}
+	for code, fallbacks := range l.Fallbacks {
+		if !seen[code] {
+			return fmt.Errorf("fallbacks are given for '%s', which is not a locale", code)
+		}
+		for _, fallback := range fallbacks {
+			if !seen[fallback] {
+				return fmt.Errorf("'%s' falls back to '%s', which is not a locale", code, fallback)
+			}
+			if fallback == code {
+				return fmt.Errorf("'%s' can't fall back to itself", code)
+			}
+		}
+	}
+	return nil
+}
+
+// GetSetting returns the value of a setting, or "" if it isn't set
+func (d *Database) GetSetting(key string) (string, error) {
+	var value string
+	err := d.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get setting %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// SetSetting saves the value of a setting
+func (d *Database) SetSetting(key, value string) error {
+	_, err := d.db.Exec(`
+		INSERT INTO settings (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, key, value)
+	if err != nil {
+		return fmt.Errorf("failed to save setting %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetLocaleSettings returns the site's locales. Sites that haven't set any
+// have none.
+func (d *Database) GetLocaleSettings() (*LocaleSettings, error) {
+	settings := &LocaleSettings{Locales: []string{}, Fallbacks: map[string][]string{}}
+	value, err := d.GetSetting(localeSettingsKey)
+	if err != nil || value == "" {
+		return settings, err
+	}
+	if err := json.Unmarshal([]byte(value), settings); err != nil {
+		return nil, fmt.Errorf("failed to parse locale settings: %w", err)
+	}
+	if settings.Fallbacks == nil {
+		settings.Fallbacks = map[string][]string{}
+	}
+	return settings, nil
+}
+
+// SetLocaleSettings saves the site's locales. Once set, the default locale
+// can't be changed (errDefaultLocaleChanged), and locales can't be removed
+// while items have translations in them (*localeInUseError).
+func (d *Database) SetLocaleSettings(settings *LocaleSettings) error {
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to encode locale settings: %w", err)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current := &LocaleSettings{}
+	var currentValue string
+	err = tx.QueryRow(`SELECT value FROM settings WHERE key = ?`, localeSettingsKey).Scan(&currentValue)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get locale settings: %w", err)
+	}
+	if currentValue != "" {
+		if err := json.Unmarshal([]byte(currentValue), current); err != nil {
+			return fmt.Errorf("failed to parse locale settings: %w", err)
+		}
+	}
+
+	if current.defaultLocale() != "" && current.defaultLocale() != settings.defaultLocale() {
+		return errDefaultLocaleChanged
+	}
+
+	inUse := &localeInUseError{}
+	for _, locale := range current.Locales {
+		if settings.has(locale) {
+			continue
+		}
+		var translated bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM item_translations WHERE locale = ?)`, locale).Scan(&translated); err != nil {
+			return fmt.Errorf("failed to check translations: %w", err)
+		}
+		if translated {
+			inUse.locales = append(inUse.locales, locale)
+		}
+	}
+	if len(inUse.locales) > 0 {
+		return inUse
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO settings (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, localeSettingsKey, string(value))
+	if err != nil {
+		return fmt.Errorf("failed to save locale settings: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit locale settings: %w", err)
+	}
+	return nil
+}
+
+// GetItemTranslations returns the localized values of an item by locale
+func (d *Database) GetItemTranslations(itemID int) (map[string]map[string]interface{}, error) {
+	translations, err := d.getTranslations([]int{itemID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if translations[itemID] == nil {
+		return map[string]map[string]interface{}{}, nil
+	}
+	return translations[itemID], nil
+}
+
+// getTranslations returns the localized values of items by item ID and
+// locale. A nil locales returns every locale.
+func (d *Database) getTranslations(itemIDs []int, locales []string) (map[int]map[string]map[string]interface{}, error) {
+	translations := map[int]map[string]map[string]interface{}{}
+	if len(itemIDs) == 0 || (locales != nil && len(locales) == 0) {
+		return translations, nil
+	}
+
+	var args []interface{}
+	for _, id := range itemIDs {
+		args = append(args, id)
+	}
+	query := `SELECT item_id, locale, data FROM item_translations WHERE item_id IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(itemIDs)), ", ") + `)`
+	if locales != nil {
+		query += ` AND locale IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(locales)), ", ") + `)`
+		for _, locale := range locales {
+			args = append(args, locale)
+		}
+	}
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item translations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID int
+		var locale, raw string
+		if err := rows.Scan(&itemID, &locale, &raw); err != nil {
+			return nil, fmt.Errorf("failed to scan item translation: %w", err)
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return nil, fmt.Errorf("failed to parse translation of item %d to %s: %w", itemID, locale, err)
+		}
+		if translations[itemID] == nil {
+			translations[itemID] = map[string]map[string]interface{}{}
+		}
+		translations[itemID][locale] = data
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating item translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+// SetItemTranslation replaces the localized values of an item in one locale.
+// Empty data removes the translation. Translations are part of the item, so
+// its version goes up and, unless expectedVersion is 0, must match first.
+// They aren't kept in the item's revisions.
+func (d *Database) SetItemTranslation(itemID int, locale string, data map[string]interface{}, expectedVersion int) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE items SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)
+	`, itemID, expectedVersion, expectedVersion)
+	if err != nil {
+		return fmt.Errorf("failed to update item version: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM items WHERE id = ? AND deleted_at IS NULL)", itemID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check item: %w", err)
+		}
+		if exists {
+			return errItemVersionConflict
+		}
+		return fmt.Errorf("item not found")
+	}
+
+	if len(data) == 0 {
+		if _, err := tx.Exec(`DELETE FROM item_translations WHERE item_id = ? AND locale = ?`, itemID, locale); err != nil {
+			return fmt.Errorf("failed to delete item translation: %w", err)
+		}
+	} else {
+		dataJSON, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to encode translation: %w", err)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO item_translations (item_id, locale, data, updated_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (item_id, locale) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at
+		`, itemID, locale, string(dataJSON))
+		if err != nil {
+			return fmt.Errorf("failed to save item translation: %w", err)
+		}
+	}
+
+	// Translations are searchable too
+	if err := indexItem(tx, itemID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit item translation: %w", err)
+	}
+	return nil
+}
+
+// DeleteItemTranslation removes the localized values of an item in one
+// locale, checking expectedVersion like SetItemTranslation
+func (d *Database) DeleteItemTranslation(itemID int, locale string, expectedVersion int) error {
+	return d.SetItemTranslation(itemID, locale, nil, expectedVersion)
+}
+
+// copyItemTranslations copies every translation of one item to another
+// inside the caller's transaction, and reindexes the copy
+func copyItemTranslations(tx *sql.Tx, sourceID, targetID int) error {
+	_, err := tx.Exec(`
+		INSERT INTO item_translations (item_id, locale, data, updated_at)
+		SELECT ?, locale, data, CURRENT_TIMESTAMP FROM item_translations WHERE item_id = ?
+	`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to copy item translations: %w", err)
+	}
+	return indexItem(tx, targetID)
+}
+
+// migrateTranslationData applies a field rename and/or type change to the
+// translations of a collection's items, like migrateFieldData does for item
+// data. Failures are added to report with their locale.
+func migrateTranslationData(tx *sql.Tx, collectionID int, oldName, newName, oldType, newType string, report *FieldMigrationReport, dropInvalid bool) error {
+	rows, err := tx.Query(`
+		SELECT t.item_id, i.slug, t.locale, t.data
+		FROM item_translations t
+		JOIN items i ON i.id = t.item_id
+		WHERE i.collection_id = ?
+		ORDER BY t.item_id, t.locale
+	`, collectionID)
+	if err != nil {
+		return fmt.Errorf("failed to get item translations: %w", err)
+	}
+
+	type pendingTranslation struct {
+		itemID int
+		locale string
+		data   string
+	}
+	var updates []pendingTranslation
+	failed := false
+
+	for rows.Next() {
+		var itemID int
+		var slug sql.NullString
+		var locale, raw string
+		if err := rows.Scan(&itemID, &slug, &locale, &raw); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan item translation: %w", err)
+		}
+
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			continue
+		}
+		value, exists := data[oldName]
+		if !exists {
+			continue
+		}
+
+		delete(data, oldName)
+		if existing, taken := data[newName]; taken && newName != oldName {
+			failed = true
+			report.Failures = append(report.Failures, FieldConversionFailure{
+				ItemID: itemID,
+				Slug:   slug.String,
+				Locale: locale,
+				Value:  existing,
+				Error:  renameConflictError(newName),
+			})
+			if !dropInvalid {
+				continue
+			}
+			value = nil
+		} else if oldType != newType {
+			converted, err := convertFieldValue(value, oldType, newType)
+			if err != nil {
+				failed = true
+				report.Failures = append(report.Failures, FieldConversionFailure{
+					ItemID: itemID,
+					Slug:   slug.String,
+					Locale: locale,
+					Value:  value,
+					Error:  err.Error(),
+				})
+				if !dropInvalid {
+					continue
+				}
+				value = nil
+			} else {
+				value = converted
+			}
+		}
+		if value != nil {
+			data[newName] = value
+		}
+
+		updated, err := json.Marshal(data)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to encode translation of item %d: %w", itemID, err)
+		}
+		updates = append(updates, pendingTranslation{itemID, locale, string(updated)})
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("error iterating item translations: %w", err)
+	}
+	rows.Close()
+
+	if failed && !dropInvalid {
+		return errFieldConversionFailed
+	}
+
+	bumped := make(map[int]bool)
+	for _, t := range updates {
+		if _, err := tx.Exec(`UPDATE item_translations SET data = ? WHERE item_id = ? AND locale = ?`, t.data, t.itemID, t.locale); err != nil {
+			return fmt.Errorf("failed to update translation of item %d: %w", t.itemID, err)
+		}
+		// Changing a translation changes the item, as with SetItemTranslation
+		if !bumped[t.itemID] {
+			bumped[t.itemID] = true
+			if _, err := tx.Exec(`UPDATE items SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, t.itemID); err != nil {
+				return fmt.Errorf("failed to update item %d: %w", t.itemID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// localizeItems replaces the values of localizable fields in items, which all
+// belong to one collection, with their values in locale. Values missing in
+// locale are read from the next locale in its fallback chain.
+func (d *Database) localizeItems(items []ItemResponse, collectionID int, settings *LocaleSettings, locale string) error {
+	if len(items) == 0 || locale == "" {
+		return nil
+	}
+
+	fields, err := d.GetCollectionFields(collectionID)
+	if err != nil {
+		return err
+	}
+	var localizable []string
+	for _, field := range fields {
+		if field.Localizable {
+			localizable = append(localizable, field.Name)
+		}
+	}
+
+	// The default locale's values are the item data itself
+	chain := settings.chain(locale)
+	var stored []string
+	for _, code := range chain {
+		if code != settings.defaultLocale() {
+			stored = append(stored, code)
+		}
+	}
+
+	var ids []int
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	translations, err := d.getTranslations(ids, stored)
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		items[i].Locale = locale
+		for _, name := range localizable {
+			for _, code := range chain {
+				if code == settings.defaultLocale() {
+					break
+				}
+				if value, ok := translations[items[i].ID][code][name]; ok && value != nil {
+					if items[i].Data == nil {
+						items[i].Data = map[string]interface{}{}
+					}
+					items[i].Data[name] = value
+					break
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// requestLocale reads the locale= parameter of a public API request, writing
+// an error response if it isn't one of the site's locales. It returns the
+// locale settings and "" when no locale was asked for.
+func (s *Server) requestLocale(w http.ResponseWriter, r *http.Request) (*LocaleSettings, string, bool) {
+	locale := r.URL.Query().Get("locale")
+	if locale == "" {
+		return nil, "", true
+	}
+
+	settings, err := s.db.GetLocaleSettings()
+	if err != nil {
+		log.Printf("Error getting locale settings: %v", err)
+		s.sendJSONError(w, "Failed to get locales", http.StatusInternalServerError)
+		return nil, "", false
+	}
+	if !settings.has(locale) {
+		s.sendJSONError(w, fmt.Sprintf("Unknown locale '%s'", locale), http.StatusBadRequest)
+		return nil, "", false
+	}
+	return settings, locale, true
+}
+
+// sendLocalizedItem writes an item as a public API response in locale, or
+// as stored when locale is ""
+func (s *Server) sendLocalizedItem(w http.ResponseWriter, item *Item, settings *LocaleSettings, locale string) {
+	response := []ItemResponse{convertItemToResponse(item)}
+	if err := s.db.localizeItems(response, item.CollectionID, settings, locale); err != nil {
+		log.Printf("Error localizing item %d to %s: %v", item.ID, locale, err)
+		s.sendJSONError(w, "Failed to localize item", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response[0])
+}
+
+// handleAdminLocales reads and, for admins, replaces the site's locales:
+//
+//	GET /admin-api/settings/locales
+//	PUT /admin-api/settings/locales  {"locales": ["en", "de", "de-AT"], "fallbacks": {"de-AT": ["de"]}}
+func (s *Server) handleAdminLocales(w http.ResponseWriter, r *http.Request) {
+	username, err := s.validateJWTToken(r)
+	if err != nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := s.db.GetUserByUsername(username)
+	if err != nil || user == nil {
+		s.sendJSONError(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	current, err := s.db.GetLocaleSettings()
+	if err != nil {
+		log.Printf("Error getting locale settings: %v", err)
+		s.sendJSONError(w, "Failed to get locales", http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(current)
+
+	case http.MethodPut:
+		if user.Role != "admin" {
+			s.sendJSONError(w, "Only admins can change locales", http.StatusForbidden)
+			return
+		}
+
+		var settings LocaleSettings
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			s.sendJSONError(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if settings.Locales == nil {
+			settings.Locales = []string{}
+		}
+		if settings.Fallbacks == nil {
+			settings.Fallbacks = map[string][]string{}
+		}
+		if err := settings.validate(); err != nil {
+			s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err := s.db.SetLocaleSettings(&settings)
+		var inUse *localeInUseError
+		if errors.As(err, &inUse) {
+			s.sendJSONError(w, inUse.Error(), http.StatusConflict)
+			return
+		}
+		if err == errDefaultLocaleChanged {
+			s.sendJSONError(w, fmt.Sprintf("The default locale can't be changed from '%s', since item data is written in it", current.defaultLocale()), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			log.Printf("Error saving locale settings: %v", err)
+			s.sendJSONError(w, "Failed to save locales", http.StatusInternalServerError)
+			return
+		}
+
+		s.audit(r, user, "settings.update", "settings", localeSettingsKey, current, &settings)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&settings)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAdminItemTranslations manages the localized values of an item:
+//
+//	GET    /admin-api/items/{id}/translations           (every locale)
+//	PUT    /admin-api/items/{id}/translations/{locale}  {"data": {"title": "Hallo"}}
+//	DELETE /admin-api/items/{id}/translations/{locale}
+//
+// Only localizable fields can be translated. A PUT replaces every value of
+// the locale.
+func (s *Server) handleAdminItemTranslations(w http.ResponseWriter, r *http.Request, user *User, itemID int, parts []string) {
+	item, err := s.db.GetItem(itemID)
+	if err != nil {
+		log.Printf("Error getting item %d: %v", itemID, err)
+		s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+		return
+	}
+	if item == nil {
+		s.sendJSONError(w, "Item not found", http.StatusNotFound)
+		return
+	}
+
+	translations, err := s.db.GetItemTranslations(itemID)
+	if err != nil {
+		log.Printf("Error getting translations of item %d: %v", itemID, err)
+		s.sendJSONError(w, "Failed to get translations", http.StatusInternalServerError)
+		return
+	}
+
+	if len(parts) == 0 {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(translations)
+		return
+	}
+
+	if len(parts) != 1 {
+		s.sendJSONError(w, "Invalid translations endpoint", http.StatusBadRequest)
+		return
+	}
+	locale := parts[0]
+
+	settings, err := s.db.GetLocaleSettings()
+	if err != nil {
+		log.Printf("Error getting locale settings: %v", err)
+		s.sendJSONError(w, "Failed to get locales", http.StatusInternalServerError)
+		return
+	}
+	if !settings.has(locale) {
+		s.sendJSONError(w, fmt.Sprintf("Unknown locale '%s'", locale), http.StatusBadRequest)
+		return
+	}
+	if locale == settings.defaultLocale() {
+		s.sendJSONError(w, fmt.Sprintf("'%s' is the default locale; edit the item itself", locale), http.StatusBadRequest)
+		return
+	}
+
+	// Translations change the item, so like an item PUT they need the
+	// version being edited
+	var request struct {
+		Data    map[string]interface{} `json:"data"`
+		Version int                    `json:"version"`
+	}
+	if r.Method == http.MethodPut {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			s.sendJSONError(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+	expectedVersion, err := requestedItemVersion(r, request.Version)
+	if err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if (r.Method == http.MethodPut || r.Method == http.MethodDelete) && expectedVersion == 0 && r.Header.Get("If-Match") == "" {
+		s.sendJSONError(w, "An If-Match header or version is required to change a translation", http.StatusPreconditionRequired)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		fields, err := s.db.GetCollectionFields(item.CollectionID)
+		if err != nil {
+			log.Printf("Error getting collection fields: %v", err)
+			s.sendJSONError(w, "Failed to get collection fields", http.StatusInternalServerError)
+			return
+		}
+		byName := map[string]CollectionField{}
+		for _, field := range fields {
+			byName[field.Name] = field
+		}
+
+		data := map[string]interface{}{}
+		for name, value := range request.Data {
+			field, ok := byName[name]
+			if !ok || !field.Localizable {
+				s.sendJSONError(w, fmt.Sprintf("Field '%s' is not localizable", name), http.StatusBadRequest)
+				return
+			}
+			converted, err := convertFieldValue(value, field.Type, field.Type)
+			if err != nil {
+				s.sendJSONError(w, fmt.Sprintf("Invalid value for %s field '%s': %v", field.Type, name, err), http.StatusBadRequest)
+				return
+			}
+			if converted != nil && converted != "" {
+				data[name] = converted
+			}
+		}
+
+		err = s.db.SetItemTranslation(itemID, locale, data, expectedVersion)
+		if err == errItemVersionConflict {
+			s.sendItemConflict(w, itemID)
+			return
+		}
+		if err != nil {
+			log.Printf("Error saving translation of item %d to %s: %v", itemID, locale, err)
+			s.sendJSONError(w, "Failed to save translation", http.StatusInternalServerError)
+			return
+		}
+
+		s.audit(r, user, "item.translate", "item", itemID, translationSnapshot(locale, translations[locale]), translationSnapshot(locale, data))
+
+		updated, err := s.db.GetItem(itemID)
+		if err != nil || updated == nil {
+			log.Printf("Error getting item %d after saving translation: %v", itemID, err)
+			s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", itemETag(updated))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"locale":  locale,
+			"data":    data,
+			"version": updated.Version,
+		})
+
+	case http.MethodDelete:
+		err = s.db.DeleteItemTranslation(itemID, locale, expectedVersion)
+		if err == errItemVersionConflict {
+			s.sendItemConflict(w, itemID)
+			return
+		}
+		if err != nil {
+			log.Printf("Error deleting translation of item %d to %s: %v", itemID, locale, err)
+			s.sendJSONError(w, "Failed to delete translation", http.StatusInternalServerError)
+			return
+		}
+
+		if translations[locale] != nil {
+			s.audit(r, user, "item.translate", "item", itemID, translationSnapshot(locale, translations[locale]), nil)
+		}
+
+		updated, err := s.db.GetItem(itemID)
+		if err != nil || updated == nil {
+			log.Printf("Error getting item %d after deleting translation: %v", itemID, err)
+			s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", itemETag(updated))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "Translation deleted",
+			"version": updated.Version,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// importCSVTranslations imports the values of a collection's localizable
+// fields in locale from a CSV file, as exported with ?locale=. Each row must
+// have the _id of an existing item; its cells replace that item's values in
+// locale and empty cells remove them. Other columns are ignored.
+func (s *Server) importCSVTranslations(w http.ResponseWriter, r *http.Request, user *User, collectionID int, fields []CollectionField, csvReader *csv.Reader, headers []string, locale string) {
+	settings, err := s.db.GetLocaleSettings()
+	if err != nil {
+		log.Printf("Error getting locale settings: %v", err)
+		s.sendJSONError(w, "Failed to get locales", http.StatusInternalServerError)
+		return
+	}
+	if !settings.has(locale) {
+		s.sendJSONError(w, fmt.Sprintf("Unknown locale '%s'", locale), http.StatusBadRequest)
+		return
+	}
+	if locale == settings.defaultLocale() {
+		s.sendJSONError(w, fmt.Sprintf("'%s' is the default locale; import it without a locale", locale), http.StatusBadRequest)
+		return
+	}
+
+	localizable := map[string]CollectionField{}
+	for _, field := range fields {
+		if field.Localizable {
+			localizable[field.Name] = field
+		}
+	}
+
+	var successCount, errorCount, skippedCount int
+	errors := []string{}
+	rowNumber := 1
+
+	for {
+		rowNumber++
+		record, err := csvReader.Read()
+		if err != nil {
+			if err.Error() == "EOF" {
+				break
+			}
+			errors = append(errors, fmt.Sprintf("Row %d: Failed to read - %v", rowNumber, err))
+			errorCount++
+			continue
+		}
+
+		itemID := 0
+		for i, header := range headers {
+			if header == "_id" && i < len(record) {
+				itemID, _ = strconv.Atoi(record[i])
+			}
+		}
+		if itemID == 0 {
+			errors = append(errors, fmt.Sprintf("Row %d: _id is required to import a translation", rowNumber))
+			errorCount++
+			continue
+		}
+		item, err := s.db.GetItem(itemID)
+		if err != nil || item == nil || item.CollectionID != collectionID {
+			errors = append(errors, fmt.Sprintf("Row %d: Item %d not found in this collection", rowNumber, itemID))
+			errorCount++
+			continue
+		}
+
+		translations, err := s.db.GetItemTranslations(itemID)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Row %d: Failed to get translations - %v", rowNumber, err))
+			errorCount++
+			continue
+		}
+		before := translations[locale]
+		data := map[string]interface{}{}
+		for name, value := range before {
+			data[name] = value
+		}
+
+		changed := false
+		invalid := false
+		for i, header := range headers {
+			field, ok := localizable[header]
+			if !ok || i >= len(record) {
+				continue
+			}
+			value, err := convertFieldValue(record[i], "text", field.Type)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("Row %d: Invalid value for field '%s' - %v", rowNumber, header, err))
+				invalid = true
+				break
+			}
+			if value == nil || value == "" {
+				if _, exists := data[header]; exists {
+					delete(data, header)
+					changed = true
+				}
+				continue
+			}
+			data[header] = value
+			changed = true
+		}
+		if invalid {
+			errorCount++
+			continue
+		}
+		if !changed {
+			skippedCount++
+			continue
+		}
+
+		// Imports overwrite whatever is stored, like the main CSV import
+		if err := s.db.SetItemTranslation(itemID, locale, data, 0); err != nil {
+			errors = append(errors, fmt.Sprintf("Row %d: Failed to save translation - %v", rowNumber, err))
+			errorCount++
+			continue
+		}
+		successCount++
+	}
+
+	response := map[string]interface{}{
+		"locale":        locale,
+		"success":       successCount,
+		"errors":        errorCount,
+		"skipped":       skippedCount,
+		"totalRows":     rowNumber - 1,
+		"errorMessages": errors,
+	}
+	s.audit(r, user, "collection.import", "collection", collectionID, nil, response)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// translationSnapshot is the audit snapshot of an item's values in a locale
+func translationSnapshot(locale string, data map[string]interface{}) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"locale": locale,
+		"data":   data,
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocaleSettingsChain(t *testing.T) {
+	settings := &LocaleSettings{
+		Locales: []string{"en", "fr", "fr-CA", "de"},
+		Fallbacks: map[string][]string{
+			"fr-CA": {"fr"},
+			"de":    {"fr", "en"},
+		},
+	}
+
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{"en", []string{"en"}},
+		{"fr", []string{"fr", "en"}},
+		{"fr-CA", []string{"fr-CA", "fr", "en"}},
+		{"de", []string{"de", "fr", "en"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if got := settings.chain(tt.locale); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chain(%q) = %v, want %v", tt.locale, got, tt.want)
+			}
+		})
+	}
+
+	// Without locales there is nothing to fall back to
+	if got := (&LocaleSettings{}).chain("en"); !reflect.DeepEqual(got, []string{"en"}) {
+		t.Errorf("chain with no locales = %v, want [en]", got)
+	}
+}
+
+func TestLocaleSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings LocaleSettings
+		wantErr  string
+	}{
+		{"no locales", LocaleSettings{}, ""},
+		{"valid", LocaleSettings{
+			Locales:   []string{"en", "fr", "fr-CA"},
+			Fallbacks: map[string][]string{"fr-CA": {"fr"}},
+		}, ""},
+		{"invalid code", LocaleSettings{Locales: []string{"en", "french!"}}, "not a valid locale code"},
+		{"duplicate locale", LocaleSettings{Locales: []string{"en", "en"}}, "more than once"},
+		{"fallbacks for unknown locale", LocaleSettings{
+			Locales:   []string{"en"},
+			Fallbacks: map[string][]string{"fr": {"en"}},
+		}, "which is not a locale"},
+		{"fallback to unknown locale", LocaleSettings{
+			Locales:   []string{"en", "fr"},
+			Fallbacks: map[string][]string{"fr": {"de"}},
+		}, "falls back to 'de'"},
+		{"fallback to itself", LocaleSettings{
+			Locales:   []string{"en", "fr"},
+			Fallbacks: map[string][]string{"fr": {"fr"}},
+		}, "can't fall back to itself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetLocaleSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial []string // Saved before the change, with a German translation
+		locales []string
+		wantErr string
+	}{
+		{name: "first locales", locales: []string{"en", "de"}},
+		{name: "adding a locale", initial: []string{"en", "de"}, locales: []string{"en", "de", "fr"}},
+		{name: "reordering other locales", initial: []string{"en", "de", "fr"}, locales: []string{"en", "fr", "de"}},
+		{name: "removing an untranslated locale", initial: []string{"en", "de", "fr"}, locales: []string{"en", "de"}},
+		{name: "changing the default", initial: []string{"en", "de"}, locales: []string{"de", "en"}, wantErr: errDefaultLocaleChanged.Error()},
+		{name: "removing every locale", initial: []string{"en", "de"}, locales: []string{}, wantErr: errDefaultLocaleChanged.Error()},
+		{name: "removing a translated locale", initial: []string{"en", "de", "fr"}, locales: []string{"en", "fr"}, wantErr: "Items have translations in 'de'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			if tt.initial != nil {
+				if err := db.SetLocaleSettings(&LocaleSettings{Locales: tt.initial}); err != nil {
+					t.Fatalf("SetLocaleSettings(initial): %v", err)
+				}
+				if err := db.CreateUser("author", "password", "author@example.com", "admin"); err != nil {
+					t.Fatalf("CreateUser: %v", err)
+				}
+				author, err := db.GetUserByUsername("author")
+				if err != nil {
+					t.Fatalf("GetUserByUsername: %v", err)
+				}
+				collection, err := db.CreateCollection("Posts", "posts", "", false, "")
+				if err != nil {
+					t.Fatalf("CreateCollection: %v", err)
+				}
+				item, err := db.CreateItem(collection.ID, "hello", `{"title":"Hello"}`, "draft", author.ID, nil)
+				if err != nil {
+					t.Fatalf("CreateItem: %v", err)
+				}
+				if err := db.SetItemTranslation(item.ID, "de", map[string]interface{}{"title": "Hallo"}, 0); err != nil {
+					t.Fatalf("SetItemTranslation: %v", err)
+				}
+			}
+
+			err := db.SetLocaleSettings(&LocaleSettings{Locales: tt.locales})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("SetLocaleSettings = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("SetLocaleSettings = %v, want an error containing %q", err, tt.wantErr)
+			}
+
+			want := tt.locales
+			if tt.wantErr != "" {
+				want = tt.initial
+			}
+			saved, err := db.GetLocaleSettings()
+			if err != nil {
+				t.Fatalf("GetLocaleSettings: %v", err)
+			}
+			if !reflect.DeepEqual(saved.Locales, want) {
+				t.Errorf("saved locales = %v, want %v", saved.Locales, want)
+			}
+		})
+	}
+}
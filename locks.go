@@ -0,0 +1,232 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// itemLockTTL is how long an edit lock lasts without being renewed. Editors
+// renew their lock well within this while an item stays open.
+const itemLockTTL = 2 * time.Minute
+
+// errItemLocked is returned when another user holds an item's edit lock
+var errItemLocked = errors.New("item is locked by another user")
+
+// ItemLock records who has an item open for editing. Locks are advisory: they
+// let editors warn each other but don't block writes.
+type ItemLock struct {
+	ItemID     int
+	UserID     int
+	Username   sql.NullString
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+}
+
+// ItemLockResponse represents an edit lock for JSON API responses
+type ItemLockResponse struct {
+	UserID     int    `json:"userId"`
+	Username   string `json:"username"`
+	AcquiredAt string `json:"acquiredAt"`
+	ExpiresAt  string `json:"expiresAt"`
+}
+
+func convertItemLockToResponse(lock *ItemLock) *ItemLockResponse {
+	if lock == nil {
+		return nil
+	}
+	return &ItemLockResponse{
+		UserID:     lock.UserID,
+		Username:   lock.Username.String,
+		AcquiredAt: lock.AcquiredAt.UTC().Format(time.RFC3339),
+		ExpiresAt:  lock.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// getItemLock reads an item's lock using q, which may be the database or a
+// transaction. Expired locks are ignored, so it returns nil if nobody holds
+// the lock.
+func getItemLock(q interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}, itemID int, now time.Time) (*ItemLock, error) {
+	query := `
+		SELECT l.item_id, l.user_id, u.username, l.acquired_at, l.expires_at
+		FROM item_locks l
+		LEFT JOIN users u ON u.id = l.user_id
+		WHERE l.item_id = ? AND l.expires_at > ?
+	`
+
+	var lock ItemLock
+	err := q.QueryRow(query, itemID, now.UTC().Format(scheduleTimeLayout)).Scan(&lock.ItemID, &lock.UserID, &lock.Username, &lock.AcquiredAt, &lock.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item lock: %w", err)
+	}
+	return &lock, nil
+}
+
+// GetItemLock returns the current edit lock of an item, or nil if it isn't
+// locked
+func (d *Database) GetItemLock(itemID int) (*ItemLock, error) {
+	return getItemLock(d.db, itemID, time.Now())
+}
+
+// AcquireItemLock locks an item for user, or renews the lock if user already
+// holds it. If someone else holds the lock it returns errItemLocked along with
+// their lock, unless force is set, in which case the lock is taken over.
+func (d *Database) AcquireItemLock(itemID int, user *User, force bool) (*ItemLock, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	current, err := getItemLock(tx, itemID, now)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.UserID != user.ID && !force {
+		return current, errItemLocked
+	}
+
+	// A renewal keeps the time the lock was first taken
+	acquiredAt := now
+	if current != nil && current.UserID == user.ID {
+		acquiredAt = current.AcquiredAt
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO item_locks (item_id, user_id, acquired_at, expires_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (item_id) DO UPDATE SET
+			user_id = excluded.user_id,
+			acquired_at = excluded.acquired_at,
+			expires_at = excluded.expires_at
+	`, itemID, user.ID, acquiredAt.UTC().Format(scheduleTimeLayout), now.Add(itemLockTTL).Format(scheduleTimeLayout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save item lock: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit item lock: %w", err)
+	}
+
+	return d.GetItemLock(itemID)
+}
+
+// ReleaseItemLock removes an item's edit lock
+func (d *Database) ReleaseItemLock(itemID int) error {
+	if _, err := d.db.Exec(`DELETE FROM item_locks WHERE item_id = ?`, itemID); err != nil {
+		return fmt.Errorf("failed to release item lock: %w", err)
+	}
+	return nil
+}
+
+// handleAdminItemLock manages an item's edit lock:
+//
+//	GET    /admin-api/items/{id}/lock
+//	POST   /admin-api/items/{id}/lock              (acquire or renew)
+//	POST   /admin-api/items/{id}/lock?force=true   (take over, admins only)
+//	DELETE /admin-api/items/{id}/lock              (others' locks: admins only)
+func (s *Server) handleAdminItemLock(w http.ResponseWriter, r *http.Request, user *User, itemID int) {
+	item, err := s.db.GetItem(itemID)
+	if err != nil {
+		log.Printf("Error getting item %d: %v", itemID, err)
+		s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+		return
+	}
+	if item == nil {
+		s.sendJSONError(w, "Item not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		lock, err := s.db.GetItemLock(itemID)
+		if err != nil {
+			log.Printf("Error getting lock for item %d: %v", itemID, err)
+			s.sendJSONError(w, "Failed to get item lock", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"lock": convertItemLockToResponse(lock),
+		})
+
+	case http.MethodPost:
+		force := r.URL.Query().Get("force") == "true"
+		if force && user.Role != "admin" {
+			s.sendJSONError(w, "Only admins can break edit locks", http.StatusForbidden)
+			return
+		}
+
+		previous, err := s.db.GetItemLock(itemID)
+		if err != nil {
+			log.Printf("Error getting lock for item %d: %v", itemID, err)
+			s.sendJSONError(w, "Failed to lock item", http.StatusInternalServerError)
+			return
+		}
+
+		lock, err := s.db.AcquireItemLock(itemID, user, force)
+		if err == errItemLocked {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": fmt.Sprintf("Item is being edited by %s", lock.Username.String),
+				"lock":  convertItemLockToResponse(lock),
+			})
+			return
+		}
+		if err != nil {
+			log.Printf("Error locking item %d: %v", itemID, err)
+			s.sendJSONError(w, "Failed to lock item", http.StatusInternalServerError)
+			return
+		}
+
+		// Acquiring and renewing locks is routine; taking one over is not
+		if previous != nil && previous.UserID != user.ID {
+			s.audit(r, user, "item.lock_break", "item", itemID, convertItemLockToResponse(previous), convertItemLockToResponse(lock))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"lock": convertItemLockToResponse(lock),
+		})
+
+	case http.MethodDelete:
+		lock, err := s.db.GetItemLock(itemID)
+		if err != nil {
+			log.Printf("Error getting lock for item %d: %v", itemID, err)
+			s.sendJSONError(w, "Failed to get item lock", http.StatusInternalServerError)
+			return
+		}
+		if lock != nil && lock.UserID != user.ID && user.Role != "admin" {
+			s.sendJSONError(w, "Only admins can break edit locks", http.StatusForbidden)
+			return
+		}
+
+		if err := s.db.ReleaseItemLock(itemID); err != nil {
+			log.Printf("Error unlocking item %d: %v", itemID, err)
+			s.sendJSONError(w, "Failed to unlock item", http.StatusInternalServerError)
+			return
+		}
+
+		if lock != nil && lock.UserID != user.ID {
+			s.audit(r, user, "item.lock_break", "item", itemID, convertItemLockToResponse(lock), nil)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Item unlocked"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	flag "github.com/spf13/pflag"
 )
@@ -16,13 +17,34 @@ func main() {
 	var adminUser string
 	var adminPassword string
 	var dataDir string
+	var maxUploadMB int64
+	var storageBackend string
+	var s3Config S3Config
 	var showVersion bool
+	var trashRetention time.Duration
+	var auditRetention time.Duration
 
 	flag.StringVarP(&adminUser, "admin-user", "u", "", "Admin username for initial setup")
 	flag.StringVarP(&adminPassword, "admin-password", "p", "", "Admin password for initial setup")
 	flag.StringVarP(&dataDir, "data-dir", "d", ".", "Directory where database will be stored")
+	flag.Int64Var(&maxUploadMB, "max-upload-size", defaultMaxUploadSize>>20, "Maximum asset upload size in megabytes")
+	flag.StringVar(&storageBackend, "storage", "fs", "Asset storage backend: fs or s3")
+	flag.StringVar(&s3Config.Endpoint, "s3-endpoint", "", "S3-compatible endpoint URL (e.g. https://s3.us-east-1.amazonaws.com)")
+	flag.StringVar(&s3Config.Bucket, "s3-bucket", "", "S3 bucket for assets")
+	flag.StringVar(&s3Config.Region, "s3-region", "us-east-1", "S3 region")
+	flag.StringVar(&s3Config.Prefix, "s3-prefix", "", "Key prefix for assets within the S3 bucket")
+	flag.BoolVar(&s3Config.PathStyle, "s3-path-style", false, "Use path-style S3 URLs (needed for MinIO and most self-hosted servers)")
+	flag.StringVar(&s3Config.AccessKey, "s3-access-key", "", "S3 access key ID")
+	flag.StringVar(&s3Config.SecretKey, "s3-secret-key", "", "S3 secret access key")
+	flag.DurationVar(&trashRetention, "trash-retention", defaultTrashRetention, "How long deleted items and collections stay in the trash before being purged (0 keeps them until purged by hand)")
+	flag.DurationVar(&auditRetention, "audit-retention", defaultAuditRetention, "How long audit log entries are kept (0 keeps them forever)")
 	flag.BoolVarP(&showVersion, "version", "v", false, "Show version information")
 
+	// Subcommands run against the database without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "schema" {
+		os.Exit(runSchemaCommand(os.Args[2:]))
+	}
+
 	// Custom usage function
 	flag.Usage = func() {
 		fmt.Println("Lodge CMS")
@@ -31,6 +53,8 @@ func main() {
 		fmt.Println("  lodge --admin-user <username> --admin-password <password>")
 		fmt.Println("  lodge -u <username> -p <password>")
 		fmt.Println("  ADMIN_USER=<username> ADMIN_PASSWORD=<password> lodge")
+		fmt.Println("  lodge schema apply -f <file> [--dry-run] [--allow-destructive]")
+		fmt.Println("  lodge schema dump [-o <file>]")
 		fmt.Println()
 		fmt.Println("Flags:")
 		flag.PrintDefaults()
@@ -38,6 +62,8 @@ func main() {
 		fmt.Println("Environment Variables:")
 		fmt.Println("  ADMIN_USER      Admin username (fallback for --admin-user)")
 		fmt.Println("  ADMIN_PASSWORD  Admin password (fallback for --admin-password)")
+		fmt.Println("  S3_ACCESS_KEY   S3 access key ID (fallback for --s3-access-key)")
+		fmt.Println("  S3_SECRET_KEY   S3 secret access key (fallback for --s3-secret-key)")
 	}
 
 	flag.Parse()
@@ -56,6 +82,13 @@ func main() {
 		adminPassword = os.Getenv("ADMIN_PASSWORD")
 	}
 
+	if s3Config.AccessKey == "" {
+		s3Config.AccessKey = os.Getenv("S3_ACCESS_KEY")
+	}
+	if s3Config.SecretKey == "" {
+		s3Config.SecretKey = os.Getenv("S3_SECRET_KEY")
+	}
+
 	if adminUser == "" || adminPassword == "" {
 		fmt.Println("Error: Admin user and password are required")
 		fmt.Println()
@@ -100,6 +133,22 @@ func main() {
 		log.Printf("Admin user '%s' authenticated successfully", adminUser)
 	}
 
+	// Set up asset storage
+	var storage AssetStorage
+	switch storageBackend {
+	case "fs":
+		storage = NewFSStorage(dataDir)
+	case "s3":
+		s3Storage, err := NewS3Storage(s3Config)
+		if err != nil {
+			log.Fatal("Failed to initialize S3 storage:", err)
+		}
+		storage = s3Storage
+		log.Printf("Storing assets in S3 bucket '%s'", s3Config.Bucket)
+	default:
+		log.Fatalf("Unknown storage backend '%s'. Use 'fs' or 's3'.", storageBackend)
+	}
+
 	// Start esbuild watch in development mode
 	if isDevelopmentMode() {
 		if err := startEsbuildWatch(); err != nil {
@@ -107,7 +156,9 @@ func main() {
 		}
 	}
 
-	server := NewServer(adminUser, adminPassword, db)
+	server := NewServer(adminUser, adminPassword, db, dataDir, storage, maxUploadMB<<20)
+	server.trashRetention = trashRetention
+	server.auditRetention = auditRetention
 	if err := server.Start(); err != nil {
 		log.Fatal(err)
 	}
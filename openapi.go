@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// errorResponses are the failures every content API route can return, in
+// the {"error": "..."} format written by sendJSONError
+func errorResponses(codes ...int) map[string]interface{} {
+	descriptions := map[int]string{
+		http.StatusBadRequest:          "Invalid request",
+		http.StatusUnauthorized:        "Invalid or missing API key",
+		http.StatusForbidden:           "The preview token doesn't cover what was asked for",
+		http.StatusNotFound:            "Not found",
+		http.StatusInternalServerError: "Server error",
+	}
+
+	responses := make(map[string]interface{})
+	for _, code := range codes {
+		responses[fmt.Sprint(code)] = map[string]interface{}{
+			"description": descriptions[code],
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"},
+				},
+			},
+		}
+	}
+	return responses
+}
+
+// jsonContent wraps a schema as an application/json response
+func jsonContent(description string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+// generateOpenAPI builds an OpenAPI 3.1 document for the public content API
+// with a concrete item schema for every collection
+func generateOpenAPI(defs []CollectionDefinition) map[string]interface{} {
+	schemas := map[string]interface{}{
+		"Markdown": markdownJSONSchema,
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"error": map[string]interface{}{"type": "string"},
+			},
+			"required": []string{"error"},
+		},
+		"Facets": map[string]interface{}{
+			"type":        "object",
+			"description": "Value counts of each faceted field, most common first",
+			"additionalProperties": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"value": map[string]interface{}{"type": []string{"string", "boolean"}},
+						"count": map[string]interface{}{"type": "integer", "minimum": 1},
+					},
+					"required": []string{"value", "count"},
+				},
+			},
+		},
+	}
+
+	localeParameter := map[string]interface{}{
+		"name":        "locale",
+		"in":          "query",
+		"description": "Locale to return localizable fields in. Missing values fall back along the locale's fallback chain to the default locale",
+		"schema":      map[string]interface{}{"type": "string"},
+	}
+
+	paths := make(map[string]interface{})
+	names := collectionTypeNames(defs)
+	slugs := []string{}
+	for _, def := range defs {
+		slugs = append(slugs, def.Collection.Slug)
+		name := names[def.Collection.ID]
+		schemas[name] = collectionJSONSchema(def, "#/components/schemas/")
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + name}
+		tag := def.Collection.Name
+
+		if def.Collection.Singleton {
+			responses := errorResponses(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)
+			responses["200"] = jsonContent("The singleton's item", ref)
+			paths["/api/singletons/"+def.Collection.Slug] = map[string]interface{}{
+				"get": map[string]interface{}{
+					"operationId": "get" + name,
+					"summary":     fmt.Sprintf("Get %s", def.Collection.Name),
+					"tags":        []string{tag},
+					"parameters":  []interface{}{localeParameter},
+					"responses":   responses,
+				},
+			}
+			continue
+		}
+
+		listResponses := errorResponses(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)
+		// Asking for facets wraps the items in an object with the counts
+		itemList := map[string]interface{}{
+			"type":  "array",
+			"items": ref,
+		}
+		listResponses["200"] = jsonContent("Items in the collection, or with facets an object holding the items and their value counts", map[string]interface{}{
+			"oneOf": []interface{}{
+				itemList,
+				map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"items":  itemList,
+						"facets": map[string]interface{}{"$ref": "#/components/schemas/Facets"},
+					},
+					"required": []string{"items", "facets"},
+				},
+			},
+		})
+		listParameters := []interface{}{}
+		var sortValues []string
+		for _, field := range def.Fields {
+			if !field.Indexed {
+				continue
+			}
+			listParameters = append(listParameters, map[string]interface{}{
+				"name":        "filter[" + field.Name + "]",
+				"in":          "query",
+				"description": fmt.Sprintf("Only return items whose %s is this value. Repeat to match any of several values", field.Label),
+				"schema":      fieldJSONSchema(field, "#/components/schemas/"),
+			})
+			sortValues = append(sortValues, field.Name, "-"+field.Name)
+		}
+		if sortValues != nil {
+			listParameters = append(listParameters, map[string]interface{}{
+				"name":        "sort",
+				"in":          "query",
+				"description": "Field to order items by, prefixed with - for descending order",
+				"schema":      map[string]interface{}{"type": "string", "enum": sortValues},
+			})
+		}
+
+		paths["/api/collections/"+def.Collection.Slug] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "list" + name,
+				"summary":     fmt.Sprintf("List %s items", def.Collection.Name),
+				"tags":        []string{tag},
+				"parameters": append([]interface{}{
+					map[string]interface{}{
+						"name":        "limit",
+						"in":          "query",
+						"description": "Number of items to return",
+						"schema":      map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 100, "default": 50},
+					},
+					map[string]interface{}{
+						"name":        "offset",
+						"in":          "query",
+						"description": "Number of items to skip",
+						"schema":      map[string]interface{}{"type": "integer", "minimum": 0, "default": 0},
+					},
+					map[string]interface{}{
+						"name":        "q",
+						"in":          "query",
+						"description": "Only return items whose text matches every word, best matches first",
+						"schema":      map[string]interface{}{"type": "string"},
+					},
+					map[string]interface{}{
+						"name":        "facets",
+						"in":          "query",
+						"description": "Comma-separated text and boolean fields to count values of. The response becomes an object with the items and facets",
+						"schema":      map[string]interface{}{"type": "string"},
+					},
+					localeParameter,
+				}, listParameters...),
+				"responses": listResponses,
+			},
+		}
+
+		itemResponses := errorResponses(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)
+		itemResponses["200"] = jsonContent("The item", ref)
+		paths["/api/collections/"+def.Collection.Slug+"/{id}"] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "get" + name + "Item",
+				"summary":     fmt.Sprintf("Get a %s item", def.Collection.Name),
+				"tags":        []string{tag},
+				"parameters": []interface{}{
+					map[string]interface{}{
+						"name":     "id",
+						"in":       "path",
+						"required": true,
+						"schema":   map[string]interface{}{"type": "integer"},
+					},
+					localeParameter,
+				},
+				"responses": itemResponses,
+			},
+		}
+	}
+
+	schemas["SearchResult"] = searchResultJSONSchema
+	collectionParameter := map[string]interface{}{
+		"name":        "collection",
+		"in":          "query",
+		"description": "Slug of a collection to search in. Defaults to every collection",
+		"schema":      map[string]interface{}{"type": "string"},
+	}
+	if len(slugs) > 0 {
+		collectionParameter["schema"] = map[string]interface{}{"type": "string", "enum": slugs}
+	}
+	searchResponses := errorResponses(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)
+	searchResponses["200"] = jsonContent("Matching items from any collection, best matches first", map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"$ref": "#/components/schemas/SearchResult"},
+	})
+	paths["/api/search"] = map[string]interface{}{
+		"get": map[string]interface{}{
+			"operationId": "search",
+			"summary":     "Search items",
+			"description": "Searches the text of items across collections. With a preview token the search covers the token's scope, and naming another collection is refused with 403",
+			"tags":        []string{"Search"},
+			"parameters": []interface{}{
+				map[string]interface{}{
+					"name":        "q",
+					"in":          "query",
+					"required":    true,
+					"description": "Words every result must match",
+					"schema":      map[string]interface{}{"type": "string", "minLength": 1},
+				},
+				collectionParameter,
+				map[string]interface{}{
+					"name":        "limit",
+					"in":          "query",
+					"description": "Number of results to return",
+					"schema":      map[string]interface{}{"type": "integer", "minimum": 1, "maximum": maxSearchLimit, "default": defaultSearchLimit},
+				},
+				map[string]interface{}{
+					"name":        "offset",
+					"in":          "query",
+					"description": "Number of results to skip",
+					"schema":      map[string]interface{}{"type": "integer", "minimum": 0, "default": 0},
+				},
+				localeParameter,
+			},
+			"responses": searchResponses,
+		},
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":   "Lodge CMS Content API",
+			"version": version,
+		},
+		"servers": []interface{}{map[string]interface{}{"url": "/"}},
+		"security": []interface{}{
+			map[string]interface{}{"ApiKeyAuth": []string{}},
+			map[string]interface{}{"PreviewToken": []string{}},
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"ApiKeyAuth": map[string]interface{}{
+					"type": "apiKey",
+					"in":   "header",
+					"name": "X-API-Key",
+				},
+				"PreviewToken": map[string]interface{}{
+					"type":        "apiKey",
+					"in":          "header",
+					"name":        "X-Preview-Token",
+					"description": "Short-lived token from POST /admin-api/preview-tokens; returns unpublished items in its scope",
+				},
+			},
+		},
+	}
+}
+
+// searchResultJSONSchema describes a result of GET /api/search. Results come
+// from any collection, so data isn't described beyond being an object.
+var searchResultJSONSchema = map[string]interface{}{
+	"type":        "object",
+	"description": "An item matching a search. data holds the fields of the item's collection",
+	"properties": map[string]interface{}{
+		"id":           map[string]interface{}{"type": "integer"},
+		"collectionId": map[string]interface{}{"type": "integer"},
+		"collection":   map[string]interface{}{"type": "string", "description": "Slug of the item's collection"},
+		"slug":         map[string]interface{}{"type": "string"},
+		"status":       map[string]interface{}{"type": "string"},
+		"version":      map[string]interface{}{"type": "integer", "minimum": 1},
+		"publishAt":    map[string]interface{}{"type": "string", "format": "date-time"},
+		"unpublishAt":  map[string]interface{}{"type": "string", "format": "date-time"},
+		"createdAt":    map[string]interface{}{"type": "string", "format": "date-time"},
+		"updatedAt":    map[string]interface{}{"type": "string", "format": "date-time"},
+		"data":         map[string]interface{}{"type": "object"},
+		"snippet":      map[string]interface{}{"type": "string", "description": "HTML-escaped matching text, with the matched words wrapped in <mark>"},
+		"score":        map[string]interface{}{"type": "number", "description": "Relevance; higher is a better match"},
+	},
+	"required": []string{"id", "collectionId", "collection", "status", "version", "createdAt", "updatedAt", "data", "score"},
+}
+
+// handleAPIOpenAPI serves GET /api/openapi.json
+func (s *Server) handleAPIOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	_, err := s.validateAPIKey(r)
+	if err != nil {
+		s.sendJSONError(w, "Invalid or missing API key", http.StatusUnauthorized)
+		return
+	}
+
+	defs, err := s.db.loadCollectionDefinitions("")
+	if err != nil {
+		log.Printf("Error loading collection definitions: %v", err)
+		s.sendJSONError(w, "Failed to load collections", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(generateOpenAPI(defs))
+}
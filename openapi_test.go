@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateOpenAPIPaths(t *testing.T) {
+	defs := []CollectionDefinition{
+		{Collection: Collection{ID: 1, Name: "Posts", Slug: "posts"}, Fields: []CollectionField{{Name: "title", Label: "Title", Type: "text"}}},
+		{Collection: Collection{ID: 2, Name: "Home", Slug: "home", Singleton: true}},
+	}
+
+	// Round-trip through JSON so the test reads the document as clients do
+	encoded, err := json.Marshal(generateOpenAPI(defs))
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name     string
+				Required bool
+				Schema   map[string]interface{}
+			}
+			Responses map[string]interface{}
+		}
+		Components struct {
+			Schemas map[string]map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal(encoded, &doc); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+
+	tests := []struct {
+		path      string
+		responses []string
+	}{
+		{"/api/collections/posts", []string{"200", "400", "401", "403", "404", "500"}},
+		{"/api/collections/posts/{id}", []string{"200", "400", "401", "403", "404", "500"}},
+		{"/api/singletons/home", []string{"200", "400", "401", "403", "404", "500"}},
+		{"/api/search", []string{"200", "400", "401", "403", "404", "500"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			get, ok := doc.Paths[tt.path]["get"]
+			if !ok {
+				t.Fatalf("no GET %s", tt.path)
+			}
+			for _, code := range tt.responses {
+				if _, ok := get.Responses[code]; !ok {
+					t.Errorf("no %s response", code)
+				}
+			}
+		})
+	}
+
+	search := doc.Paths["/api/search"]["get"]
+	params := map[string]bool{}
+	for _, p := range search.Parameters {
+		params[p.Name] = p.Required
+		if p.Name == "collection" && !reflect.DeepEqual(p.Schema["enum"], []interface{}{"posts", "home"}) {
+			t.Errorf("collection enum = %v, want every slug", p.Schema["enum"])
+		}
+	}
+	if want := map[string]bool{"q": true, "collection": false, "limit": false, "offset": false, "locale": false}; !reflect.DeepEqual(params, want) {
+		t.Errorf("search parameters = %v, want %v", params, want)
+	}
+	if _, ok := doc.Components.Schemas["SearchResult"]; !ok {
+		t.Error("no SearchResult schema")
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultPreviewTTL = time.Hour
+	maxPreviewTTL     = 24 * time.Hour
+)
+
+// previewScope is what a preview token grants read access to: every item of
+// a collection, or only one item when ItemID is set
+type previewScope struct {
+	CollectionID int
+	ItemID       int
+}
+
+// allows reports whether the scope covers an item of a collection. An itemID
+// of 0 asks for the collection as a whole, e.g. to list it.
+func (p *previewScope) allows(collectionID, itemID int) bool {
+	if p.CollectionID != collectionID {
+		return false
+	}
+	return p.ItemID == 0 || p.ItemID == itemID
+}
+
+// PreviewSigningKey returns the key preview tokens are signed with. It is
+// separate from the admin login secret, and like the asset signing key it is
+// generated the first time and saved in the settings, so tokens stay valid
+// across restarts.
+func (d *Database) PreviewSigningKey() ([]byte, error) {
+	return d.signingKey("preview_signing_key")
+}
+
+// signPreviewToken mints a token for scope that expires after ttl. Preview
+// tokens are signed with their own key and have no username claim, so they
+// are never accepted by the admin API.
+func (s *Server) signPreviewToken(scope previewScope, ttl time.Duration, issuedBy string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	claims := jwt.MapClaims{
+		"preview":    true,
+		"collection": scope.CollectionID,
+		"sub":        issuedBy,
+		"exp":        expiresAt.Unix(),
+		"iat":        now.Unix(),
+	}
+	if scope.ItemID != 0 {
+		claims["item"] = scope.ItemID
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.previewKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// previewScopeFromRequest reads a preview token from the X-Preview-Token
+// header or the preview query parameter. It returns nil if there is none.
+func (s *Server) previewScopeFromRequest(r *http.Request) (*previewScope, error) {
+	tokenString := r.Header.Get("X-Preview-Token")
+	if tokenString == "" {
+		tokenString = r.URL.Query().Get("preview")
+	}
+	if tokenString == "" {
+		return nil, nil
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.previewKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["preview"] != true {
+		return nil, fmt.Errorf("not a preview token")
+	}
+
+	// JSON numbers decode as float64
+	collectionID, ok := claims["collection"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("preview token has no collection")
+	}
+	scope := &previewScope{CollectionID: int(collectionID)}
+	if itemID, ok := claims["item"].(float64); ok {
+		scope.ItemID = int(itemID)
+	}
+	return scope, nil
+}
+
+// authorizeContentRequest checks that a public API request has either an API
+// key or a preview token, writing an error response if not. It returns the
+// preview scope, or nil for API key requests.
+func (s *Server) authorizeContentRequest(w http.ResponseWriter, r *http.Request) (*previewScope, bool) {
+	preview, err := s.previewScopeFromRequest(r)
+	if err != nil {
+		s.sendJSONError(w, "Invalid or expired preview token", http.StatusUnauthorized)
+		return nil, false
+	}
+	if preview != nil {
+		// Drafts must not end up in shared caches
+		w.Header().Set("Cache-Control", "private, no-store")
+		return preview, true
+	}
+
+	if _, err := s.validateAPIKey(r); err != nil {
+		s.sendJSONError(w, "Invalid or missing API key", http.StatusUnauthorized)
+		return nil, false
+	}
+	return nil, true
+}
+
+// validatePreviewURL checks a collection's preview URL template
+func validatePreviewURL(template string) error {
+	if template == "" {
+		return nil
+	}
+	if !strings.HasPrefix(template, "http://") && !strings.HasPrefix(template, "https://") {
+		return fmt.Errorf("Preview URL must start with http:// or https://")
+	}
+	if !strings.Contains(template, "{token}") {
+		return fmt.Errorf("Preview URL must contain {token}")
+	}
+	return nil
+}
+
+// expandPreviewURL fills in a preview URL template. Supported placeholders
+// are {token}, {collection} (the collection slug), {id} and {slug}.
+func expandPreviewURL(template string, collection *Collection, item *Item, token string) string {
+	id, slug := "", ""
+	if item != nil {
+		id = strconv.Itoa(item.ID)
+		slug = item.Slug.String
+	}
+	// Escape spaces as %20 so values work in both paths and query strings
+	escape := func(s string) string {
+		return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+	}
+	return strings.NewReplacer(
+		"{token}", escape(token),
+		"{collection}", escape(collection.Slug),
+		"{id}", id,
+		"{slug}", escape(slug),
+	).Replace(template)
+}
+
+// previewURLParam stores an empty preview URL template as NULL
+func previewURLParam(template string) interface{} {
+	if template == "" {
+		return nil
+	}
+	return template
+}
+
+// handleAdminPreviewTokens mints preview tokens:
+//
+//	POST /admin-api/preview-tokens  {"itemId": 12} or {"collectionId": 3}, optional "ttl" in seconds
+func (s *Server) handleAdminPreviewTokens(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Validate JWT token
+	username, err := s.validateJWTToken(r)
+	if err != nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := s.db.GetUserByUsername(username)
+	if err != nil || user == nil {
+		s.sendJSONError(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	var request struct {
+		ItemID       int `json:"itemId"`
+		CollectionID int `json:"collectionId"`
+		TTL          int `json:"ttl"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.sendJSONError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	ttl := defaultPreviewTTL
+	if request.TTL != 0 {
+		ttl = time.Duration(request.TTL) * time.Second
+		if ttl <= 0 || ttl > maxPreviewTTL {
+			s.sendJSONError(w, fmt.Sprintf("ttl must be between 1 and %d seconds", int(maxPreviewTTL.Seconds())), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var item *Item
+	var collectionID int
+	switch {
+	case request.ItemID != 0:
+		item, err = s.db.GetItem(request.ItemID)
+		if err != nil {
+			log.Printf("Error getting item %d: %v", request.ItemID, err)
+			s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+			return
+		}
+		if item == nil {
+			s.sendJSONError(w, "Item not found", http.StatusNotFound)
+			return
+		}
+		collectionID = item.CollectionID
+	case request.CollectionID != 0:
+		collectionID = request.CollectionID
+	default:
+		s.sendJSONError(w, "itemId or collectionId is required", http.StatusBadRequest)
+		return
+	}
+
+	collection, err := s.db.GetCollectionByID(collectionID)
+	if err != nil {
+		log.Printf("Error getting collection %d: %v", collectionID, err)
+		s.sendJSONError(w, "Failed to get collection", http.StatusInternalServerError)
+		return
+	}
+	if collection == nil {
+		s.sendJSONError(w, "Collection not found", http.StatusNotFound)
+		return
+	}
+
+	scope := previewScope{CollectionID: collection.ID}
+	if item != nil {
+		scope.ItemID = item.ID
+	}
+
+	token, expiresAt, err := s.signPreviewToken(scope, ttl, username)
+	if err != nil {
+		log.Printf("Error signing preview token: %v", err)
+		s.sendJSONError(w, "Failed to create preview token", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"token":        token,
+		"expiresAt":    expiresAt.UTC().Format(time.RFC3339),
+		"collectionId": scope.CollectionID,
+		"previewUrl":   nil,
+	}
+	if item != nil {
+		response["itemId"] = item.ID
+	}
+
+	// The token itself is left out of the audit log
+	if item != nil {
+		s.audit(r, user, "preview_token.create", "item", item.ID, nil, map[string]interface{}{"expiresAt": response["expiresAt"]})
+	} else {
+		s.audit(r, user, "preview_token.create", "collection", collection.ID, nil, map[string]interface{}{"expiresAt": response["expiresAt"]})
+	}
+
+	if collection.PreviewURL.Valid {
+		response["previewUrl"] = expandPreviewURL(collection.PreviewURL.String, collection, item, token)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestPreviewSigningKey(t *testing.T) {
+	db := newTestDatabase(t)
+
+	first, err := db.PreviewSigningKey()
+	if err != nil {
+		t.Fatalf("PreviewSigningKey: %v", err)
+	}
+	if len(first) != 32 {
+		t.Fatalf("key is %d bytes, want 32", len(first))
+	}
+	second, err := db.PreviewSigningKey()
+	if err != nil {
+		t.Fatalf("PreviewSigningKey: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("PreviewSigningKey returned a different key the second time")
+	}
+
+	assetKey, err := db.AssetSigningKey()
+	if err != nil {
+		t.Fatalf("AssetSigningKey: %v", err)
+	}
+	if bytes.Equal(first, assetKey) {
+		t.Error("preview and asset signing keys are the same")
+	}
+}
+
+func TestPreviewScopeFromRequest(t *testing.T) {
+	s := &Server{jwtSecret: []byte("login secret"), previewKey: []byte("preview key")}
+
+	valid, _, err := s.signPreviewToken(previewScope{CollectionID: 3, ItemID: 7}, time.Hour, "admin")
+	if err != nil {
+		t.Fatalf("signPreviewToken: %v", err)
+	}
+	expired, _, err := s.signPreviewToken(previewScope{CollectionID: 3}, -time.Minute, "admin")
+	if err != nil {
+		t.Fatalf("signPreviewToken: %v", err)
+	}
+	sign := func(key []byte, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name  string
+		token string
+		want  *previewScope
+		err   bool
+	}{
+		{"no token", "", nil, false},
+		{"valid token", valid, &previewScope{CollectionID: 3, ItemID: 7}, false},
+		{"expired token", expired, nil, true},
+		{"signed with the login secret", sign(s.jwtSecret, jwt.MapClaims{"preview": true, "collection": 3, "exp": exp}), nil, true},
+		{"admin login token", sign(s.jwtSecret, jwt.MapClaims{"username": "admin", "exp": exp}), nil, true},
+		{"not a preview token", sign(s.previewKey, jwt.MapClaims{"collection": 3, "exp": exp}), nil, true},
+		{"no collection", sign(s.previewKey, jwt.MapClaims{"preview": true, "exp": exp}), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/collections/posts", nil)
+			if tt.token != "" {
+				r.Header.Set("X-Preview-Token", tt.token)
+			}
+			scope, err := s.previewScopeFromRequest(r)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %t", err, tt.err)
+			}
+			if (scope == nil) != (tt.want == nil) || (scope != nil && *scope != *tt.want) {
+				t.Errorf("scope = %+v, want %+v", scope, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,491 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ItemRevision is a snapshot of an item after one change
+type ItemRevision struct {
+	ID            int
+	ItemID        int
+	Revision      int
+	Slug          sql.NullString
+	Data          string // JSON
+	Status        string
+	CreatedBy     sql.NullInt64
+	CreatedByName sql.NullString
+	CreatedAt     time.Time
+}
+
+// recordItemRevision snapshots the current state of an item as its next
+// revision. Saves that don't change anything are not recorded.
+func recordItemRevision(tx *sql.Tx, itemID int, author int) error {
+	var slug sql.NullString
+	var data, status string
+	err := tx.QueryRow(`SELECT slug, data, status FROM items WHERE id = ?`, itemID).Scan(&slug, &data, &status)
+	if err != nil {
+		return fmt.Errorf("failed to read item for revision: %w", err)
+	}
+
+	var lastRevision int
+	var lastSlug sql.NullString
+	var lastData, lastStatus string
+	err = tx.QueryRow(`
+		SELECT revision, slug, data, status FROM item_revisions
+		WHERE item_id = ? ORDER BY revision DESC LIMIT 1
+	`, itemID).Scan(&lastRevision, &lastSlug, &lastData, &lastStatus)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get latest revision: %w", err)
+	}
+	if err == nil && lastSlug == slug && lastData == data && lastStatus == status {
+		return nil
+	}
+
+	var authorParam interface{}
+	if author > 0 {
+		authorParam = author
+	}
+
+	query := `
+		INSERT INTO item_revisions (item_id, revision, slug, data, status, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`
+	if _, err := tx.Exec(query, itemID, lastRevision+1, slug, data, status, authorParam); err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	return nil
+}
+
+// GetItemRevisions returns all revisions of an item, newest first
+func (d *Database) GetItemRevisions(itemID int) ([]ItemRevision, error) {
+	query := `
+		SELECT r.id, r.item_id, r.revision, r.slug, r.data, r.status, r.created_by, u.username, r.created_at
+		FROM item_revisions r
+		LEFT JOIN users u ON u.id = r.created_by
+		WHERE r.item_id = ?
+		ORDER BY r.revision DESC
+	`
+
+	rows, err := d.db.Query(query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []ItemRevision{}
+	for rows.Next() {
+		var rev ItemRevision
+		err := rows.Scan(&rev.ID, &rev.ItemID, &rev.Revision, &rev.Slug, &rev.Data, &rev.Status, &rev.CreatedBy, &rev.CreatedByName, &rev.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetItemRevision returns one revision of an item, or nil if it doesn't exist
+func (d *Database) GetItemRevision(itemID, revision int) (*ItemRevision, error) {
+	query := `
+		SELECT r.id, r.item_id, r.revision, r.slug, r.data, r.status, r.created_by, u.username, r.created_at
+		FROM item_revisions r
+		LEFT JOIN users u ON u.id = r.created_by
+		WHERE r.item_id = ? AND r.revision = ?
+	`
+
+	var rev ItemRevision
+	err := d.db.QueryRow(query, itemID, revision).Scan(&rev.ID, &rev.ItemID, &rev.Revision, &rev.Slug, &rev.Data, &rev.Status, &rev.CreatedBy, &rev.CreatedByName, &rev.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return &rev, nil
+}
+
+// GetLatestItemRevision returns the newest revision number of an item
+func (d *Database) GetLatestItemRevision(itemID int) (int, error) {
+	var revision sql.NullInt64
+	err := d.db.QueryRow(`SELECT MAX(revision) FROM item_revisions WHERE item_id = ?`, itemID).Scan(&revision)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest revision: %w", err)
+	}
+	return int(revision.Int64), nil
+}
+
+// FieldDiff describes how one data field differs between two revisions
+type FieldDiff struct {
+	Field  string      `json:"field"`
+	Change string      `json:"change"` // added, removed or changed
+	From   interface{} `json:"from"`
+	To     interface{} `json:"to"`
+}
+
+// diffItemData compares two JSON data objects field by field
+func diffItemData(fromJSON, toJSON string) []FieldDiff {
+	var from, to map[string]interface{}
+	json.Unmarshal([]byte(fromJSON), &from)
+	json.Unmarshal([]byte(toJSON), &to)
+
+	keys := make(map[string]bool)
+	for k := range from {
+		keys[k] = true
+	}
+	for k := range to {
+		keys[k] = true
+	}
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	diffs := []FieldDiff{}
+	for _, name := range names {
+		before, hadBefore := from[name]
+		after, hasAfter := to[name]
+		switch {
+		case !hadBefore:
+			diffs = append(diffs, FieldDiff{Field: name, Change: "added", To: after})
+		case !hasAfter:
+			diffs = append(diffs, FieldDiff{Field: name, Change: "removed", From: before})
+		case !reflect.DeepEqual(before, after):
+			diffs = append(diffs, FieldDiff{Field: name, Change: "changed", From: before, To: after})
+		}
+	}
+	return diffs
+}
+
+// RevisionMismatch describes a value in a revision that doesn't fit the
+// collection's current fields
+type RevisionMismatch struct {
+	Field   string `json:"field"`
+	Problem string `json:"problem"`
+}
+
+// mapRevisionData converts the data of a revision to the collection's current
+// fields. Revisions keep the data as it was saved, so fields renamed, deleted
+// or given another type since don't match it. Values are converted to their
+// field's current type as a type change would; those of fields that no longer
+// exist, or that can't be converted, are left out and reported.
+func mapRevisionData(dataJSON string, fields []CollectionField) (map[string]interface{}, []RevisionMismatch) {
+	var data map[string]interface{}
+	json.Unmarshal([]byte(dataJSON), &data)
+
+	types := make(map[string]string)
+	for _, f := range fields {
+		types[f.Name] = f.Type
+	}
+
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mapped := make(map[string]interface{})
+	mismatches := []RevisionMismatch{}
+	for _, name := range names {
+		fieldType, ok := types[name]
+		if !ok {
+			mismatches = append(mismatches, RevisionMismatch{Field: name, Problem: "no longer a field of this collection"})
+			continue
+		}
+
+		// Markdown saved from the editor is {md, html}; only the source
+		// carries over if the field is no longer markdown
+		fromType := fieldType
+		if m, ok := data[name].(map[string]interface{}); ok {
+			if _, ok := m["md"]; ok {
+				fromType = "markdown"
+			}
+		}
+		value, err := convertFieldValue(data[name], fromType, fieldType)
+		if err != nil {
+			mismatches = append(mismatches, RevisionMismatch{Field: name, Problem: fmt.Sprintf("can't be converted to %s: %v", fieldType, err)})
+			continue
+		}
+		mapped[name] = value
+	}
+	return mapped, mismatches
+}
+
+// revisionResponse converts a revision to its JSON API representation
+func revisionResponse(rev *ItemRevision, includeData bool) map[string]interface{} {
+	response := map[string]interface{}{
+		"revision":  rev.Revision,
+		"itemId":    rev.ItemID,
+		"slug":      rev.Slug.String,
+		"status":    rev.Status,
+		"createdBy": nil,
+		"author":    nil,
+		"createdAt": rev.CreatedAt.Format(time.RFC3339),
+	}
+	if rev.CreatedBy.Valid {
+		response["createdBy"] = rev.CreatedBy.Int64
+	}
+	if rev.CreatedByName.Valid {
+		response["author"] = rev.CreatedByName.String
+	}
+	if includeData {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(rev.Data), &data); err != nil {
+			data = make(map[string]interface{})
+		}
+		response["data"] = data
+	}
+	return response
+}
+
+// handleAdminItemRevisions serves the revision history of an item:
+//
+//	GET  /admin-api/items/{id}/revisions
+//	GET  /admin-api/items/{id}/revisions/{revision}
+//	GET  /admin-api/items/{id}/revisions/diff?from=1&to=3
+//	POST /admin-api/items/{id}/revisions/{revision}/restore[?dropInvalid=true]
+func (s *Server) handleAdminItemRevisions(w http.ResponseWriter, r *http.Request, user *User, itemID int, parts []string) {
+	item, err := s.db.GetItem(itemID)
+	if err != nil {
+		log.Printf("Error getting item %d: %v", itemID, err)
+		s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+		return
+	}
+	if item == nil {
+		s.sendJSONError(w, "Item not found", http.StatusNotFound)
+		return
+	}
+
+	// List revisions
+	if len(parts) == 0 || parts[0] == "" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		revisions, err := s.db.GetItemRevisions(itemID)
+		if err != nil {
+			log.Printf("Error getting revisions for item %d: %v", itemID, err)
+			s.sendJSONError(w, "Failed to get revisions", http.StatusInternalServerError)
+			return
+		}
+
+		response := []map[string]interface{}{}
+		for i := range revisions {
+			response = append(response, revisionResponse(&revisions[i], false))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// Diff two revisions
+	if parts[0] == "diff" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleAdminItemRevisionDiff(w, r, itemID)
+		return
+	}
+
+	revision, err := strconv.Atoi(parts[0])
+	if err != nil {
+		s.sendJSONError(w, "Invalid revision number", http.StatusBadRequest)
+		return
+	}
+
+	rev, err := s.db.GetItemRevision(itemID, revision)
+	if err != nil {
+		log.Printf("Error getting revision %d of item %d: %v", revision, itemID, err)
+		s.sendJSONError(w, "Failed to get revision", http.StatusInternalServerError)
+		return
+	}
+	if rev == nil {
+		s.sendJSONError(w, "Revision not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(revisionResponse(rev, true))
+
+	case len(parts) == 2 && parts[1] == "restore":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Like an item PUT, restoring needs the version being replaced
+		var request struct {
+			Version int `json:"version"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+				s.sendJSONError(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+		}
+		expectedVersion, err := requestedItemVersion(r, request.Version)
+		if err != nil {
+			s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if expectedVersion == 0 && r.Header.Get("If-Match") == "" {
+			s.sendJSONError(w, "An If-Match header or version is required to restore a revision", http.StatusPreconditionRequired)
+			return
+		}
+
+		// The revision's values are mapped to the current fields. Values that
+		// don't fit are only dropped when asked to, as in a field migration.
+		fields, err := s.db.GetCollectionFields(item.CollectionID)
+		if err != nil {
+			log.Printf("Error getting fields of collection %d: %v", item.CollectionID, err)
+			s.sendJSONError(w, "Failed to restore revision", http.StatusInternalServerError)
+			return
+		}
+		data, mismatches := mapRevisionData(rev.Data, fields)
+		if len(mismatches) > 0 && r.URL.Query().Get("dropInvalid") != "true" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":      fmt.Sprintf("%d value(s) in revision %d don't fit the collection's current fields; retry with dropInvalid=true to restore without them", len(mismatches), revision),
+				"mismatches": mismatches,
+			})
+			return
+		}
+		dataJSON, err := json.Marshal(data)
+		if err != nil {
+			log.Printf("Error encoding revision %d of item %d: %v", revision, itemID, err)
+			s.sendJSONError(w, "Failed to restore revision", http.StatusInternalServerError)
+			return
+		}
+
+		// With a workflow the status only changes through transitions, so
+		// restoring brings back the content but keeps the current status
+		status := rev.Status
+		if _, err := s.db.resolveItemStatus(item.CollectionID, item, rev.Status); err == errStatusBypassesWorkflow {
+			status = item.Status
+		}
+
+		// Restoring saves the old content as a new revision, so it can be undone
+		err = s.db.UpdateItem(itemID, rev.Slug.String, string(dataJSON), status, user.ID, expectedVersion, nil)
+		if err == errItemVersionConflict {
+			s.sendItemConflict(w, itemID)
+			return
+		}
+		if err != nil {
+			log.Printf("Error restoring revision %d of item %d: %v", revision, itemID, err)
+			s.sendJSONError(w, "Failed to restore revision", http.StatusInternalServerError)
+			return
+		}
+
+		restored, err := s.db.GetItem(itemID)
+		if err != nil || restored == nil {
+			log.Printf("Error getting restored item %d: %v", itemID, err)
+			s.sendJSONError(w, "Failed to get restored item", http.StatusInternalServerError)
+			return
+		}
+
+		s.audit(r, user, "item.restore_revision", "item", itemID, auditItem(item), auditItem(restored))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(convertItemToResponse(restored))
+
+	default:
+		s.sendJSONError(w, "Invalid revisions endpoint", http.StatusBadRequest)
+	}
+}
+
+// handleAdminItemRevisionDiff compares two revisions. "to" defaults to the
+// latest revision and "from" to the one before it. Revision 0 stands for the
+// empty item before the first revision, so the first one can be diffed too.
+func (s *Server) handleAdminItemRevisionDiff(w http.ResponseWriter, r *http.Request, itemID int) {
+	query := r.URL.Query()
+
+	to := 0
+	if value := query.Get("to"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			s.sendJSONError(w, "Invalid 'to' revision", http.StatusBadRequest)
+			return
+		}
+		to = n
+	} else {
+		latest, err := s.db.GetLatestItemRevision(itemID)
+		if err != nil {
+			log.Printf("Error getting latest revision of item %d: %v", itemID, err)
+			s.sendJSONError(w, "Failed to get revisions", http.StatusInternalServerError)
+			return
+		}
+		to = latest
+	}
+
+	from := to - 1
+	if value := query.Get("from"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			s.sendJSONError(w, "Invalid 'from' revision", http.StatusBadRequest)
+			return
+		}
+		from = n
+	}
+
+	fromRev := &ItemRevision{ItemID: itemID, Data: "{}"}
+	if from != 0 {
+		var err error
+		fromRev, err = s.db.GetItemRevision(itemID, from)
+		if err != nil {
+			log.Printf("Error getting revision %d of item %d: %v", from, itemID, err)
+			s.sendJSONError(w, "Failed to get revision", http.StatusInternalServerError)
+			return
+		}
+	}
+	toRev, err := s.db.GetItemRevision(itemID, to)
+	if err != nil {
+		log.Printf("Error getting revision %d of item %d: %v", to, itemID, err)
+		s.sendJSONError(w, "Failed to get revision", http.StatusInternalServerError)
+		return
+	}
+	if fromRev == nil || toRev == nil {
+		s.sendJSONError(w, fmt.Sprintf("Revisions %d and %d must both exist", from, to), http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"from":   from,
+		"to":     to,
+		"fields": diffItemData(fromRev.Data, toRev.Data),
+	}
+	if fromRev.Slug.String != toRev.Slug.String {
+		response["slug"] = map[string]string{"from": fromRev.Slug.String, "to": toRev.Slug.String}
+	}
+	if fromRev.Status != toRev.Status {
+		response["status"] = map[string]string{"from": fromRev.Status, "to": toRev.Status}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapRevisionData(t *testing.T) {
+	fields := []CollectionField{
+		{Name: "title", Type: "text"},
+		{Name: "views", Type: "number"},
+		{Name: "body", Type: "markdown"},
+		{Name: "summary", Type: "textarea"},
+		{Name: "featured", Type: "boolean"},
+	}
+
+	tests := []struct {
+		name       string
+		data       string
+		want       map[string]interface{}
+		mismatches []string
+	}{
+		{
+			name: "matching fields",
+			data: `{"title":"Hi","views":3,"body":{"md":"# Hi","html":"<h1>Hi</h1>"},"featured":true}`,
+			want: map[string]interface{}{"title": "Hi", "views": 3.0, "body": map[string]interface{}{"md": "# Hi", "html": "<h1>Hi</h1>"}, "featured": true},
+		},
+		{
+			name: "values converted to the current type",
+			data: `{"title":42,"views":"7","featured":"yes"}`,
+			want: map[string]interface{}{"title": "42", "views": 7.0, "featured": true},
+		},
+		{
+			name: "markdown kept as the source in a field that isn't markdown any more",
+			data: `{"summary":{"md":"*short*","html":"<em>short</em>"}}`,
+			want: map[string]interface{}{"summary": "*short*"},
+		},
+		{
+			name: "null values",
+			data: `{"title":null}`,
+			want: map[string]interface{}{"title": nil},
+		},
+		{
+			name:       "renamed or deleted field",
+			data:       `{"title":"Hi","headline":"Old"}`,
+			want:       map[string]interface{}{"title": "Hi"},
+			mismatches: []string{"headline"},
+		},
+		{
+			name:       "value that can't be converted",
+			data:       `{"views":"many","featured":"maybe","title":"Hi"}`,
+			want:       map[string]interface{}{"title": "Hi"},
+			mismatches: []string{"featured", "views"},
+		},
+		{
+			name: "empty revision",
+			data: `{}`,
+			want: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, mismatches := mapRevisionData(tt.data, fields)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("data = %#v, want %#v", got, tt.want)
+			}
+			var names []string
+			for _, m := range mismatches {
+				names = append(names, m.Field)
+			}
+			if !reflect.DeepEqual(names, tt.mismatches) {
+				t.Errorf("mismatched fields = %v, want %v (%+v)", names, tt.mismatches, mismatches)
+			}
+		})
+	}
+}
+
+func TestDiffItemData(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []FieldDiff
+	}{
+		{"identical", `{"title":"Hi","tags":["a"]}`, `{"tags":["a"],"title":"Hi"}`, []FieldDiff{}},
+		{"from the empty item", `{}`, `{"title":"Hi","views":2}`, []FieldDiff{
+			{Field: "title", Change: "added", To: "Hi"},
+			{Field: "views", Change: "added", To: 2.0},
+		}},
+		{"removed field", `{"title":"Hi","old":true}`, `{"title":"Hi"}`, []FieldDiff{
+			{Field: "old", Change: "removed", From: true},
+		}},
+		{"changed values sorted by field", `{"b":1,"a":"x"}`, `{"b":2,"a":"y"}`, []FieldDiff{
+			{Field: "a", Change: "changed", From: "x", To: "y"},
+			{Field: "b", Change: "changed", From: 1.0, To: 2.0},
+		}},
+		{"nested values", `{"body":{"md":"a","html":"<p>a</p>"}}`, `{"body":{"md":"b","html":"<p>b</p>"}}`, []FieldDiff{
+			{Field: "body", Change: "changed", From: map[string]interface{}{"md": "a", "html": "<p>a</p>"}, To: map[string]interface{}{"md": "b", "html": "<p>b</p>"}},
+		}},
+		{"null is a value", `{"title":null}`, `{"title":"Hi"}`, []FieldDiff{
+			{Field: "title", Change: "changed", From: nil, To: "Hi"},
+		}},
+		{"null added", `{}`, `{"title":null}`, []FieldDiff{
+			{Field: "title", Change: "added", To: nil},
+		}},
+		{"type change", `{"views":"3"}`, `{"views":3}`, []FieldDiff{
+			{Field: "views", Change: "changed", From: "3", To: 3.0},
+		}},
+		{"invalid JSON counts as empty", `not json`, `{"title":"Hi"}`, []FieldDiff{
+			{Field: "title", Change: "added", To: "Hi"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffItemData(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffItemData(%s, %s) = %#v, want %#v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
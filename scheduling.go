@@ -0,0 +1,213 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// schedulerInterval is how often the server checks for items due to be
+// published or unpublished
+const schedulerInterval = 30 * time.Second
+
+// scheduleTimeLayout matches SQLite's CURRENT_TIMESTAMP so stored times
+// compare correctly as text
+const scheduleTimeLayout = "2006-01-02 15:04:05"
+
+// ItemSchedule holds when an item should be published and unpublished
+type ItemSchedule struct {
+	PublishAt   sql.NullTime
+	UnpublishAt sql.NullTime
+}
+
+// parseScheduleTime parses an RFC 3339 timestamp from a request. An empty
+// value means no time is set.
+func parseScheduleTime(name string, value *string) (sql.NullTime, error) {
+	if value == nil || *value == "" {
+		return sql.NullTime{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return sql.NullTime{}, fmt.Errorf("%s must be an RFC 3339 timestamp, e.g. 2025-10-01T09:00:00Z", name)
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}, nil
+}
+
+// parseItemSchedule reads the publishAt and unpublishAt values of an item
+// request. Missing values clear that side of the schedule.
+func parseItemSchedule(publishAt, unpublishAt *string) (*ItemSchedule, error) {
+	var schedule ItemSchedule
+	var err error
+
+	if schedule.PublishAt, err = parseScheduleTime("publishAt", publishAt); err != nil {
+		return nil, err
+	}
+	if schedule.UnpublishAt, err = parseScheduleTime("unpublishAt", unpublishAt); err != nil {
+		return nil, err
+	}
+
+	if schedule.PublishAt.Valid && schedule.UnpublishAt.Valid && !schedule.UnpublishAt.Time.After(schedule.PublishAt.Time) {
+		return nil, fmt.Errorf("unpublishAt must be after publishAt")
+	}
+
+	return &schedule, nil
+}
+
+// scheduleParam converts a schedule time to the value stored in the database
+func scheduleParam(t sql.NullTime) interface{} {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time.UTC().Format(scheduleTimeLayout)
+}
+
+// setItemSchedule stores an item's publish and unpublish times
+func setItemSchedule(tx *sql.Tx, itemID int, schedule *ItemSchedule) error {
+	_, err := tx.Exec(
+		"UPDATE items SET publish_at = ?, unpublish_at = ? WHERE id = ?",
+		scheduleParam(schedule.PublishAt), scheduleParam(schedule.UnpublishAt), itemID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set item schedule: %w", err)
+	}
+	return nil
+}
+
+// isLive reports whether the item is inside its publishing window at now.
+// The scheduler applies transitions periodically, so the public API checks
+// the window too rather than waiting for the next run.
+func (item *Item) isLive(now time.Time) bool {
+	if item.PublishAt.Valid && item.PublishAt.Time.After(now) {
+		return false
+	}
+	if item.UnpublishAt.Valid && !item.UnpublishAt.Time.After(now) {
+		return false
+	}
+	return true
+}
+
+// isPublic reports whether the public API shows the item at now: it must be
+// published and inside its publishing window, as ItemListFilter requires.
+func (item *Item) isPublic(now time.Time) bool {
+	return item.Status == "published" && item.isLive(now)
+}
+
+// ApplyScheduledTransitions publishes and unpublishes every item whose time
+// has come, including any missed while the server was down. Items are
+// unpublished (set to archived) before publishing, so an item whose whole
+// window has passed ends up archived. Applied times are cleared, and each
+// change is recorded as a revision. In collections with a workflow, items are
+// only moved if the workflow has a transition from their current state.
+func (d *Database) ApplyScheduledTransitions(now time.Time) (published, unpublished int, err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	nowParam := now.UTC().Format(scheduleTimeLayout)
+
+	unpublished, err = transitionScheduledItems(tx, `
+		UPDATE items
+		SET status = 'archived', publish_at = NULL, unpublish_at = NULL,
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, "SELECT id, collection_id, status FROM items WHERE unpublish_at IS NOT NULL AND unpublish_at <= ? AND deleted_at IS NULL", nowParam, "archived")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	published, err = transitionScheduledItems(tx, `
+		UPDATE items
+		SET status = 'published', publish_at = NULL,
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, "SELECT id, collection_id, status FROM items WHERE publish_at IS NOT NULL AND publish_at <= ? AND deleted_at IS NULL", nowParam, "published")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit scheduled transitions: %w", err)
+	}
+
+	return published, unpublished, nil
+}
+
+// transitionScheduledItems runs update for every item selected by dueQuery
+// that may move to the status to, and records a revision for each
+func transitionScheduledItems(tx *sql.Tx, update, dueQuery, now, to string) (int, error) {
+	rows, err := tx.Query(dueQuery, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find scheduled items: %w", err)
+	}
+
+	type dueItem struct {
+		id, collectionID int
+		status           string
+	}
+	var due []dueItem
+	for rows.Next() {
+		var item dueItem
+		if err := rows.Scan(&item.id, &item.collectionID, &item.status); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan scheduled item: %w", err)
+		}
+		due = append(due, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating scheduled items: %w", err)
+	}
+
+	applied := 0
+	workflows := make(map[int]*Workflow)
+	for _, item := range due {
+		wf, loaded := workflows[item.collectionID]
+		if !loaded {
+			if wf, err = getCollectionWorkflow(tx, item.collectionID); err != nil {
+				return 0, err
+			}
+			workflows[item.collectionID] = wf
+		}
+		// Leave the time set so the item moves once it reaches a state that allows it
+		if wf != nil && item.status != to && wf.transition(item.status, to) == nil {
+			continue
+		}
+
+		if _, err := tx.Exec(update, item.id); err != nil {
+			return 0, fmt.Errorf("failed to apply schedule to item %d: %w", item.id, err)
+		}
+		if wf != nil && item.status != to {
+			if err := recordItemTransition(tx, item.id, item.status, to, "Scheduled", 0); err != nil {
+				return 0, err
+			}
+		}
+		if err := recordItemRevision(tx, item.id, 0); err != nil {
+			return 0, err
+		}
+		applied++
+	}
+
+	return applied, nil
+}
+
+// runScheduler applies scheduled transitions at startup, to catch up on any
+// missed while the server was stopped, and then every interval
+func (s *Server) runScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		published, unpublished, err := s.db.ApplyScheduledTransitions(time.Now())
+		if err != nil {
+			log.Printf("Error applying scheduled transitions: %v", err)
+		} else if published > 0 || unpublished > 0 {
+			log.Printf("Scheduler published %d and unpublished %d items", published, unpublished)
+		}
+
+		<-ticker.C
+	}
+}
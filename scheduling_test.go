@@ -0,0 +1,43 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestItemIsLive(t *testing.T) {
+	now := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) sql.NullTime {
+		return sql.NullTime{Time: now.Add(d), Valid: true}
+	}
+
+	tests := []struct {
+		name        string
+		publishAt   sql.NullTime
+		unpublishAt sql.NullTime
+		want        bool
+	}{
+		{"no schedule", sql.NullTime{}, sql.NullTime{}, true},
+		{"publish time passed", at(-time.Hour), sql.NullTime{}, true},
+		{"publish time is now", at(0), sql.NullTime{}, true},
+		{"publish time a second away", at(time.Second), sql.NullTime{}, false},
+		{"unpublish time ahead", sql.NullTime{}, at(time.Hour), true},
+		{"unpublish time is now", sql.NullTime{}, at(0), false},
+		{"unpublish time passed", sql.NullTime{}, at(-time.Second), false},
+		{"inside the window", at(-time.Hour), at(time.Hour), true},
+		{"before the window", at(time.Hour), at(2 * time.Hour), false},
+		{"after the window", at(-2 * time.Hour), at(-time.Hour), false},
+		{"window ending before it starts", at(time.Hour), at(-time.Hour), false},
+		{"null times are ignored", sql.NullTime{Time: now.Add(time.Hour)}, sql.NullTime{Time: now.Add(-time.Hour)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &Item{Status: "published", PublishAt: tt.publishAt, UnpublishAt: tt.unpublishAt}
+			if got := item.isLive(now); got != tt.want {
+				t.Errorf("isLive = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
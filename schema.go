@@ -0,0 +1,732 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// fieldTypes lists the field types a schema file may use
+var fieldTypes = map[string]bool{
+	"text":     true,
+	"textarea": true,
+	"markdown": true,
+	"email":    true,
+	"url":      true,
+	"number":   true,
+	"date":     true,
+	"boolean":  true,
+	"asset":    true,
+}
+
+// SchemaFile is the declarative description of a content model, kept in
+// version control and synced to the database with `lodge schema apply`
+type SchemaFile struct {
+	Collections []SchemaCollection `json:"collections" yaml:"collections"`
+}
+
+type SchemaCollection struct {
+	Name        string        `json:"name" yaml:"name"`
+	Slug        string        `json:"slug" yaml:"slug"`
+	Description string        `json:"description,omitempty" yaml:"description,omitempty"`
+	Singleton   bool          `json:"singleton,omitempty" yaml:"singleton,omitempty"`
+	Fields      []SchemaField `json:"fields" yaml:"fields"`
+}
+
+type SchemaField struct {
+	Name         string `json:"name" yaml:"name"`
+	Label        string `json:"label" yaml:"label"`
+	Type         string `json:"type" yaml:"type"`
+	Required     bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	Indexed      bool   `json:"indexed,omitempty" yaml:"indexed,omitempty"`
+	Localizable  bool   `json:"localizable,omitempty" yaml:"localizable,omitempty"`
+	Placeholder  string `json:"placeholder,omitempty" yaml:"placeholder,omitempty"`
+	DefaultValue string `json:"defaultValue,omitempty" yaml:"defaultValue,omitempty"`
+	// RenamedFrom keeps existing item data when a field is renamed, instead
+	// of deleting the old field and creating a new one
+	RenamedFrom string `json:"renamedFrom,omitempty" yaml:"renamedFrom,omitempty"`
+}
+
+// SchemaChange is a single step of a schema plan
+type SchemaChange struct {
+	Action      string // create, update or delete
+	Collection  string // Collection slug
+	Field       string // Field name, empty for collection-level changes
+	Details     []string
+	Destructive bool
+
+	apply func(tx *sql.Tx, allowDestructive bool) error
+}
+
+func (c SchemaChange) String() string {
+	symbol := map[string]string{"create": "+", "update": "~", "delete": "-"}[c.Action]
+	target := "collection " + c.Collection
+	if c.Field != "" {
+		target = "field " + c.Collection + "." + c.Field
+	}
+	line := fmt.Sprintf("  %s %s", symbol, target)
+	if len(c.Details) > 0 {
+		line += ": " + strings.Join(c.Details, ", ")
+	}
+	return line
+}
+
+// recordSchemaApply adds the changes applied from a schema file to the audit
+// log. They are made from the command line, so there is no user to record.
+func recordSchemaApply(db *Database, file string, applied []string) {
+	if len(applied) == 0 {
+		return
+	}
+	entry := &AuditEntry{
+		Action:     "schema.apply",
+		TargetType: "schema",
+		TargetID:   sql.NullString{String: file, Valid: true},
+		After:      auditSnapshot(map[string]interface{}{"changes": applied}),
+	}
+	if err := db.RecordAudit(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record schema changes in the audit log: %v\n", err)
+	}
+}
+
+// loadSchemaFile reads a schema from YAML or JSON, chosen by file extension
+func loadSchemaFile(path string) (*SchemaFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema SchemaFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&schema)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&schema)
+		if err == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if err := schema.validate(); err != nil {
+		return nil, fmt.Errorf("invalid schema in %s: %w", path, err)
+	}
+
+	return &schema, nil
+}
+
+// validate checks the schema for mistakes before anything is compared
+func (s *SchemaFile) validate() error {
+	slugs := make(map[string]bool)
+	for i, c := range s.Collections {
+		if c.Slug == "" {
+			return fmt.Errorf("collection #%d has no slug", i+1)
+		}
+		if c.Name == "" {
+			return fmt.Errorf("collection '%s' has no name", c.Slug)
+		}
+		if slugs[c.Slug] {
+			return fmt.Errorf("collection '%s' is defined more than once", c.Slug)
+		}
+		slugs[c.Slug] = true
+
+		names := make(map[string]bool)
+		renamed := make(map[string]string)
+		for j, f := range c.Fields {
+			if f.Name == "" {
+				return fmt.Errorf("field #%d in collection '%s' has no name", j+1, c.Slug)
+			}
+			if names[f.Name] {
+				return fmt.Errorf("field '%s' is defined more than once in collection '%s'", f.Name, c.Slug)
+			}
+			names[f.Name] = true
+			if f.Label == "" {
+				return fmt.Errorf("field '%s.%s' has no label", c.Slug, f.Name)
+			}
+			if !fieldTypes[f.Type] {
+				return fmt.Errorf("field '%s.%s' has unknown type '%s'", c.Slug, f.Name, f.Type)
+			}
+			if f.Indexed && !fieldIndexable(f.Type) {
+				return fmt.Errorf("field '%s.%s' is %s, which can't be indexed", c.Slug, f.Name, f.Type)
+			}
+			if f.RenamedFrom != "" {
+				if _, ok := renamed[f.RenamedFrom]; ok {
+					return fmt.Errorf("more than one field in collection '%s' is renamed from '%s'", c.Slug, f.RenamedFrom)
+				}
+				renamed[f.RenamedFrom] = f.Name
+			}
+		}
+		// A field can't take over data from one that is still defined
+		for from, to := range renamed {
+			if from != to && names[from] {
+				return fmt.Errorf("field '%s.%s' is renamed from '%s', which is still defined", c.Slug, to, from)
+			}
+		}
+	}
+	return nil
+}
+
+// dumpSchema builds a schema file from the collections in the database
+func dumpSchema(db *Database) (*SchemaFile, error) {
+	collections, err := db.GetCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &SchemaFile{Collections: []SchemaCollection{}}
+	for _, c := range collections {
+		fields, err := db.GetCollectionFields(c.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		sc := SchemaCollection{
+			Name:        c.Name,
+			Slug:        c.Slug,
+			Description: c.Description.String,
+			Singleton:   c.Singleton,
+			Fields:      []SchemaField{},
+		}
+		for _, f := range fields {
+			sc.Fields = append(sc.Fields, SchemaField{
+				Name:         f.Name,
+				Label:        f.Label,
+				Type:         f.Type,
+				Required:     f.Required,
+				Indexed:      f.Indexed,
+				Localizable:  f.Localizable,
+				Placeholder:  f.Placeholder.String,
+				DefaultValue: f.DefaultValue.String,
+			})
+		}
+		schema.Collections = append(schema.Collections, sc)
+	}
+
+	return schema, nil
+}
+
+// planSchema compares a schema file with the database and returns the
+// changes needed to make the database match it. Collections are matched by
+// slug, or by name when their slug changed, and fields by name. Deletes come
+// first in the plan, then updates, then creates, so names freed by one
+// change can be taken by the next.
+func planSchema(db *Database, schema *SchemaFile) ([]SchemaChange, error) {
+	existing, err := db.GetCollections()
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]Collection)
+	byName := make(map[string]Collection)
+	for _, c := range existing {
+		bySlug[c.Slug] = c
+		byName[c.Name] = c
+	}
+	schemaSlugs := make(map[string]bool)
+	for _, sc := range schema.Collections {
+		schemaSlugs[sc.Slug] = true
+	}
+
+	var changes []SchemaChange
+	kept := make(map[int]bool)
+	// Names and slugs the schema gives to a collection that doesn't have them yet
+	var claims []schemaClaim
+
+	for _, sc := range schema.Collections {
+		sc := sc
+
+		current, exists := bySlug[sc.Slug]
+		if !exists {
+			// A collection whose slug changed keeps its name
+			if c, ok := byName[sc.Name]; ok && !schemaSlugs[c.Slug] {
+				current, exists = c, true
+			}
+		}
+		if exists {
+			kept[current.ID] = true
+			if current.Name != sc.Name {
+				claims = append(claims, schemaClaim{sc.Slug, "name", sc.Name})
+			}
+			if current.Slug != sc.Slug {
+				claims = append(claims, schemaClaim{sc.Slug, "slug", sc.Slug})
+			}
+		} else {
+			claims = append(claims, schemaClaim{sc.Slug, "name", sc.Name}, schemaClaim{sc.Slug, "slug", sc.Slug})
+		}
+
+		if !exists {
+			changes = append(changes, SchemaChange{
+				Action:     "create",
+				Collection: sc.Slug,
+				Details:    []string{fmt.Sprintf("%q with %d field(s)", sc.Name, len(sc.Fields))},
+				apply: func(tx *sql.Tx, _ bool) error {
+					collectionID, err := createCollection(tx, sc.Name, sc.Slug, sc.Description, sc.Singleton, "")
+					if err != nil {
+						return err
+					}
+					for i, f := range sc.Fields {
+						if _, err := createCollectionField(tx, collectionID, f.Name, f.Label, f.Type, f.Required, f.Indexed, f.Localizable, f.Placeholder, f.DefaultValue, i); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			})
+			continue
+		}
+
+		var details []string
+		if current.Slug != sc.Slug {
+			details = append(details, fmt.Sprintf("slug %s -> %s", current.Slug, sc.Slug))
+		}
+		if current.Name != sc.Name {
+			details = append(details, fmt.Sprintf("name %q -> %q", current.Name, sc.Name))
+		}
+		if current.Description.String != sc.Description {
+			details = append(details, "description changed")
+		}
+		if current.Singleton != sc.Singleton {
+			details = append(details, fmt.Sprintf("singleton %t -> %t", current.Singleton, sc.Singleton))
+		}
+		if len(details) > 0 {
+			id := current.ID
+			previewURL := current.PreviewURL.String
+			changes = append(changes, SchemaChange{
+				Action:     "update",
+				Collection: sc.Slug,
+				Details:    details,
+				apply: func(tx *sql.Tx, _ bool) error {
+					return updateCollection(tx, id, sc.Name, sc.Slug, sc.Description, sc.Singleton, previewURL)
+				},
+			})
+		}
+
+		fieldChanges, err := planSchemaFields(db, current, sc)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, fieldChanges...)
+	}
+
+	// Deleted collections stay in the trash and keep their name and slug
+	_, trashed, err := db.GetTrash()
+	if err != nil {
+		return nil, err
+	}
+	holders := make(map[string]string)
+	for _, c := range trashed {
+		holders["name "+c.Name] = fmt.Sprintf("collection '%s' in the trash", c.Slug)
+		holders["slug "+c.Slug] = fmt.Sprintf("collection '%s' in the trash", c.Slug)
+	}
+	for _, c := range existing {
+		if !kept[c.ID] {
+			holders["name "+c.Name] = fmt.Sprintf("collection '%s', which this plan moves to the trash,", c.Slug)
+			holders["slug "+c.Slug] = fmt.Sprintf("collection '%s', which this plan moves to the trash,", c.Slug)
+		}
+	}
+	for _, claim := range claims {
+		if holder, ok := holders[claim.kind+" "+claim.value]; ok {
+			return nil, fmt.Errorf("collection '%s' can't use the %s %q: %s still has it. Restore or rename that collection, or empty the trash, first", claim.collection, claim.kind, claim.value, holder)
+		}
+	}
+
+	for _, c := range existing {
+		if kept[c.ID] {
+			continue
+		}
+		items, fields, err := db.CountCollectionContents(c.ID)
+		if err != nil {
+			return nil, err
+		}
+		id := c.ID
+		changes = append(changes, SchemaChange{
+			Action:      "delete",
+			Collection:  c.Slug,
+			Details:     []string{fmt.Sprintf("moves %d item(s) and %d field(s) to the trash", items, fields)},
+			Destructive: true,
+			apply: func(tx *sql.Tx, _ bool) error {
+				return deleteCollection(tx, id)
+			},
+		})
+	}
+
+	order := map[string]int{"delete": 0, "update": 1, "create": 2}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return order[changes[i].Action] < order[changes[j].Action]
+	})
+
+	return changes, nil
+}
+
+// schemaClaim is a name or slug a schema collection takes on
+type schemaClaim struct {
+	collection string // Slug of the collection in the schema file
+	kind       string // "name" or "slug"
+	value      string
+}
+
+// planSchemaFields compares the fields of an existing collection
+func planSchemaFields(db *Database, collection Collection, sc SchemaCollection) ([]SchemaChange, error) {
+	fields, err := db.GetCollectionFields(collection.ID)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]CollectionField)
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+
+	var changes []SchemaChange
+	kept := make(map[string]bool)
+
+	for i, sf := range sc.Fields {
+		sf := sf
+		sortOrder := i
+
+		current, exists := byName[sf.Name]
+		if !exists && sf.RenamedFrom != "" {
+			current, exists = byName[sf.RenamedFrom]
+		}
+		if !exists {
+			collectionID := collection.ID
+			details := []string{sf.Type}
+			if sf.Indexed {
+				details = append(details, "indexed")
+			}
+			if sf.Localizable {
+				details = append(details, "localizable")
+			}
+			changes = append(changes, SchemaChange{
+				Action:     "create",
+				Collection: sc.Slug,
+				Field:      sf.Name,
+				Details:    details,
+				apply: func(tx *sql.Tx, _ bool) error {
+					if _, err := createCollectionField(tx, collectionID, sf.Name, sf.Label, sf.Type, sf.Required, sf.Indexed, sf.Localizable, sf.Placeholder, sf.DefaultValue, sortOrder); err != nil {
+						return err
+					}
+					// Items may already hold a value under the new field's name
+					return reindexCollection(tx, collectionID)
+				},
+			})
+			continue
+		}
+		kept[current.Name] = true
+
+		var details []string
+		destructive := false
+		if current.Name != sf.Name {
+			details = append(details, fmt.Sprintf("rename from %s", current.Name))
+		}
+		if current.Type != sf.Type {
+			details = append(details, fmt.Sprintf("type %s -> %s", current.Type, sf.Type))
+		}
+		if current.Name != sf.Name || current.Type != sf.Type {
+			// Values that can't be migrated are dropped, so check first
+			report, err := db.UpdateCollectionField(current.ID, sf.Name, sf.Label, sf.Type, sf.Required, sf.Indexed, sf.Localizable, sf.Placeholder, sf.DefaultValue, sortOrder, FieldMigrationOptions{DryRun: true})
+			if err != nil && !errors.Is(err, errFieldConversionFailed) {
+				return nil, err
+			}
+			if report != nil && len(report.Failures) > 0 {
+				details[len(details)-1] += fmt.Sprintf(" (%d item value(s) cannot be migrated and will be dropped)", len(report.Failures))
+				destructive = true
+			}
+		}
+		if current.Label != sf.Label {
+			details = append(details, fmt.Sprintf("label %q -> %q", current.Label, sf.Label))
+		}
+		if current.Required != sf.Required {
+			details = append(details, fmt.Sprintf("required %t -> %t", current.Required, sf.Required))
+		}
+		if current.Indexed != sf.Indexed {
+			details = append(details, fmt.Sprintf("indexed %t -> %t", current.Indexed, sf.Indexed))
+		}
+		if current.Localizable != sf.Localizable {
+			details = append(details, fmt.Sprintf("localizable %t -> %t", current.Localizable, sf.Localizable))
+		}
+		if current.Placeholder.String != sf.Placeholder {
+			details = append(details, "placeholder changed")
+		}
+		if current.DefaultValue.String != sf.DefaultValue {
+			details = append(details, "default value changed")
+		}
+		if current.SortOrder != sortOrder {
+			details = append(details, fmt.Sprintf("position %d -> %d", current.SortOrder, sortOrder))
+		}
+
+		if len(details) > 0 {
+			id := current.ID
+			changes = append(changes, SchemaChange{
+				Action:      "update",
+				Collection:  sc.Slug,
+				Field:       sf.Name,
+				Details:     details,
+				Destructive: destructive,
+				apply: func(tx *sql.Tx, allowDestructive bool) error {
+					_, err := updateCollectionField(tx, id, sf.Name, sf.Label, sf.Type, sf.Required, sf.Indexed, sf.Localizable, sf.Placeholder, sf.DefaultValue, sortOrder, allowDestructive)
+					return err
+				},
+			})
+		}
+	}
+
+	for _, f := range fields {
+		if kept[f.Name] {
+			continue
+		}
+		id := f.ID
+		changes = append(changes, SchemaChange{
+			Action:      "delete",
+			Collection:  sc.Slug,
+			Field:       f.Name,
+			Details:     []string{"existing values are no longer editable"},
+			Destructive: true,
+			apply: func(tx *sql.Tx, _ bool) error {
+				return deleteCollectionField(tx, id)
+			},
+		})
+	}
+
+	return changes, nil
+}
+
+// applySchema makes the changes of a plan in a single transaction, so either
+// all of them are applied or none are. It returns the change that failed
+// along with the error.
+func applySchema(db *Database, changes []SchemaChange, allowDestructive bool) (*SchemaChange, error) {
+	tx, err := db.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range changes {
+		if err := changes[i].apply(tx, allowDestructive); err != nil {
+			return &changes[i], err
+		}
+	}
+
+	// New collections' fields are inserted without syncing indexes one by one
+	if err := syncFieldIndexes(tx); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit schema changes: %w", err)
+	}
+	return nil, nil
+}
+
+// runSchemaCommand implements the `lodge schema` subcommands
+func runSchemaCommand(args []string) int {
+	usage := func() {
+		fmt.Println("Usage:")
+		fmt.Println("  lodge schema apply -f <file> [--dry-run] [--allow-destructive]")
+		fmt.Println("  lodge schema dump [-o <file>] [--format yaml|json]")
+		fmt.Println("  lodge schema typescript [--collection <slug>] [-o <file>]")
+		fmt.Println("  lodge schema json-schema [--collection <slug>] [-o <file>]")
+	}
+
+	if len(args) == 0 {
+		usage()
+		return 1
+	}
+
+	command := args[0]
+	flags := flag.NewFlagSet("schema "+command, flag.ContinueOnError)
+	dataDir := flags.StringP("data-dir", "d", ".", "Directory where database is stored")
+
+	switch command {
+	case "apply":
+		file := flags.StringP("file", "f", "lodge.schema.yaml", "Schema file to apply (.yaml, .yml or .json)")
+		dryRun := flags.Bool("dry-run", false, "Show the plan without changing the database")
+		allowDestructive := flags.Bool("allow-destructive", false, "Allow deleting collections and fields, and dropping values that can't be converted")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 1
+		}
+
+		schema, err := loadSchemaFile(*file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+
+		db, err := NewDatabase(filepath.Join(*dataDir, "lodge.db"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: failed to open database:", err)
+			return 1
+		}
+		defer db.Close()
+
+		changes, err := planSchema(db, schema)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: failed to plan schema changes:", err)
+			return 1
+		}
+
+		if len(changes) == 0 {
+			fmt.Println("No changes. The database matches the schema.")
+			return 0
+		}
+
+		counts := make(map[string]int)
+		destructive := 0
+		fmt.Println("Planned changes:")
+		for _, c := range changes {
+			counts[c.Action]++
+			if c.Destructive {
+				destructive++
+				fmt.Println(c.String() + "  [destructive]")
+			} else {
+				fmt.Println(c.String())
+			}
+		}
+		fmt.Printf("\nPlan: %d to create, %d to update, %d to delete.\n", counts["create"], counts["update"], counts["delete"])
+
+		if *dryRun {
+			return 0
+		}
+
+		if destructive > 0 && !*allowDestructive {
+			fmt.Fprintf(os.Stderr, "\nRefusing to apply %d destructive change(s). Re-run with --allow-destructive to apply them.\n", destructive)
+			return 1
+		}
+
+		if failed, err := applySchema(db, changes, *allowDestructive); err != nil {
+			if failed != nil {
+				fmt.Fprintf(os.Stderr, "Error applying %s: %v\n", strings.TrimSpace(failed.String()), err)
+			} else {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+			}
+			fmt.Fprintln(os.Stderr, "No changes were applied.")
+			return 1
+		}
+
+		applied := make([]string, len(changes))
+		for i, c := range changes {
+			applied[i] = strings.TrimSpace(c.String())
+		}
+		recordSchemaApply(db, *file, applied)
+		fmt.Println("Schema applied.")
+		return 0
+
+	case "dump":
+		output := flags.StringP("output", "o", "", "Write to a file instead of standard output")
+		format := flags.String("format", "", "Output format: yaml or json (default: from the output file extension, otherwise yaml)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 1
+		}
+
+		if *format == "" {
+			*format = "yaml"
+			if strings.EqualFold(filepath.Ext(*output), ".json") {
+				*format = "json"
+			}
+		}
+
+		db, err := NewDatabase(filepath.Join(*dataDir, "lodge.db"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: failed to open database:", err)
+			return 1
+		}
+		defer db.Close()
+
+		schema, err := dumpSchema(db)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: failed to read schema:", err)
+			return 1
+		}
+
+		var data []byte
+		switch *format {
+		case "yaml", "yml":
+			var buf bytes.Buffer
+			encoder := yaml.NewEncoder(&buf)
+			encoder.SetIndent(2)
+			err = encoder.Encode(schema)
+			encoder.Close()
+			data = buf.Bytes()
+		case "json":
+			data, err = json.MarshalIndent(schema, "", "  ")
+			data = append(data, '\n')
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown format '%s'. Use 'yaml' or 'json'.\n", *format)
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: failed to encode schema:", err)
+			return 1
+		}
+
+		if *output == "" {
+			os.Stdout.Write(data)
+			return 0
+		}
+		if err := os.WriteFile(*output, data, 0644); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		fmt.Printf("Wrote schema for %d collection(s) to %s\n", len(schema.Collections), *output)
+		return 0
+
+	case "typescript", "json-schema":
+		collection := flags.String("collection", "", "Only generate definitions for this collection slug")
+		output := flags.StringP("output", "o", "", "Write to a file instead of standard output")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 1
+		}
+
+		db, err := NewDatabase(filepath.Join(*dataDir, "lodge.db"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: failed to open database:", err)
+			return 1
+		}
+		defer db.Close()
+
+		defs, err := db.loadCollectionDefinitions(*collection)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: failed to read collections:", err)
+			return 1
+		}
+		if *collection != "" && len(defs) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: collection '%s' not found\n", *collection)
+			return 1
+		}
+
+		var data []byte
+		if command == "typescript" {
+			data = []byte(generateTypeScript(defs))
+		} else {
+			data, _ = json.MarshalIndent(generateJSONSchema(defs, *collection != ""), "", "  ")
+			data = append(data, '\n')
+		}
+
+		if *output == "" {
+			os.Stdout.Write(data)
+			return 0
+		}
+		if err := os.WriteFile(*output, data, 0644); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		fmt.Printf("Wrote %s definitions for %d collection(s) to %s\n", command, len(defs), *output)
+		return 0
+
+	default:
+		usage()
+		return 1
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPlanSchema(t *testing.T) {
+	db := newTestDatabase(t)
+
+	posts, err := db.CreateCollection("Posts", "posts", "", false, "")
+	if err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+	for i, f := range []struct{ name, fieldType string }{{"title", "text"}, {"views", "text"}} {
+		if _, err := db.CreateCollectionField(posts.ID, f.name, f.name, f.fieldType, false, false, false, "", "", i); err != nil {
+			t.Fatalf("CreateCollectionField: %v", err)
+		}
+	}
+	if err := db.CreateUser("author", "password", "author@example.com", "admin"); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	author, err := db.GetUserByUsername("author")
+	if err != nil {
+		t.Fatalf("GetUserByUsername: %v", err)
+	}
+	first, err := db.CreateItem(posts.ID, "first", `{"title":"First","views":"many"}`, "draft", author.ID, nil)
+	if err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	if _, err := db.CreateCollection("Pages", "pages", "", false, ""); err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+	trashed, err := db.CreateCollection("Old", "old", "", false, "")
+	if err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+	if _, _, err := db.DeleteCollection(trashed.ID); err != nil {
+		t.Fatalf("DeleteCollection: %v", err)
+	}
+
+	postFields := []SchemaField{{Name: "title", Label: "title", Type: "text"}, {Name: "views", Label: "views", Type: "text"}}
+	pages := SchemaCollection{Name: "Pages", Slug: "pages"}
+
+	tests := []struct {
+		name        string
+		collections []SchemaCollection
+		want        []string // One line per change, in plan order
+		destructive []bool
+		wantErr     string
+	}{
+		{
+			name:        "unchanged",
+			collections: []SchemaCollection{{Name: "Posts", Slug: "posts", Fields: postFields}, pages},
+		},
+		{
+			name: "new collection and field",
+			collections: []SchemaCollection{
+				{Name: "Posts", Slug: "posts", Fields: append(postFields[:2:2], SchemaField{Name: "body", Label: "Body", Type: "markdown"})},
+				pages,
+				{Name: "Authors", Slug: "authors"},
+			},
+			want:        []string{"create posts.body", "create authors"},
+			destructive: []bool{false, false},
+		},
+		{
+			name:        "deleted collection and field come first",
+			collections: []SchemaCollection{{Name: "Posts", Slug: "posts", Description: "Blog", Fields: postFields[:1]}},
+			want:        []string{"delete posts.views", "delete pages", "update posts"},
+			destructive: []bool{true, true, false},
+		},
+		{
+			name:        "slug change is matched by name",
+			collections: []SchemaCollection{{Name: "Posts", Slug: "articles", Fields: postFields}, pages},
+			want:        []string{"update articles"},
+			destructive: []bool{false},
+		},
+		{
+			name: "renamed field keeps its data",
+			collections: []SchemaCollection{
+				{Name: "Posts", Slug: "posts", Fields: []SchemaField{postFields[0], {Name: "hits", Label: "views", Type: "text", RenamedFrom: "views"}}},
+				pages,
+			},
+			want:        []string{"update posts.hits"},
+			destructive: []bool{false},
+		},
+		{
+			name: "type change that drops values",
+			collections: []SchemaCollection{
+				{Name: "Posts", Slug: "posts", Fields: []SchemaField{postFields[0], {Name: "views", Label: "views", Type: "number"}}},
+				pages,
+			},
+			want:        []string{"update posts.views"},
+			destructive: []bool{true},
+		},
+		{
+			name:        "slug held by a trashed collection",
+			collections: []SchemaCollection{{Name: "Posts", Slug: "posts", Fields: postFields}, pages, {Name: "Older", Slug: "old"}},
+			wantErr:     "collection 'old' in the trash",
+		},
+		{
+			name:        "name held by a collection being deleted",
+			collections: []SchemaCollection{{Name: "Pages", Slug: "posts", Fields: postFields}},
+			wantErr:     "which this plan moves to the trash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := planSchema(db, &SchemaFile{Collections: tt.collections})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("planSchema() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("planSchema: %v", err)
+			}
+
+			var got []string
+			for _, c := range changes {
+				target := c.Collection
+				if c.Field != "" {
+					target += "." + c.Field
+				}
+				got = append(got, c.Action+" "+target)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("plan is\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			for i, c := range changes {
+				if c.Destructive != tt.destructive[i] {
+					t.Errorf("%s destructive = %t, want %t", got[i], c.Destructive, tt.destructive[i])
+				}
+			}
+		})
+	}
+
+	// Planning is read-only; the dry runs behind it leave the data alone
+	items, fields, err := db.CountCollectionContents(posts.ID)
+	if err != nil {
+		t.Fatalf("CountCollectionContents: %v", err)
+	}
+	if items != 1 || fields != 2 {
+		t.Errorf("posts has %d item(s) and %d field(s) after planning, want 1 and 2", items, fields)
+	}
+	item, err := db.GetItem(first.ID)
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if item.Data != first.Data || item.Version != first.Version {
+		t.Errorf("item is version %d with %s after planning, want version %d with %s", item.Version, item.Data, first.Version, first.Data)
+	}
+}
+
+func TestApplySchema(t *testing.T) {
+	db := newTestDatabase(t)
+	if _, err := db.CreateCollection("Pages", "pages", "", false, ""); err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+
+	schema := &SchemaFile{Collections: []SchemaCollection{
+		{Name: "Posts", Slug: "posts", Fields: []SchemaField{
+			{Name: "title", Label: "Title", Type: "text", Required: true},
+			{Name: "rank", Label: "Rank", Type: "number", Indexed: true},
+		}},
+	}}
+	changes, err := planSchema(db, schema)
+	if err != nil {
+		t.Fatalf("planSchema: %v", err)
+	}
+
+	// A failing change rolls back the ones before it
+	failing := append(changes[:len(changes):len(changes)], SchemaChange{
+		Action:     "update",
+		Collection: "posts",
+		apply: func(tx *sql.Tx, _ bool) error {
+			return errors.New("failed on purpose")
+		},
+	})
+	failed, err := applySchema(db, failing, true)
+	if err == nil || failed != &failing[len(failing)-1] {
+		t.Fatalf("applySchema = %v, %v; want the last change to fail", failed, err)
+	}
+	collections, err := db.GetCollections()
+	if err != nil {
+		t.Fatalf("GetCollections: %v", err)
+	}
+	if len(collections) != 1 || collections[0].Slug != "pages" {
+		t.Fatalf("collections after a failed apply = %v, want only pages", collections)
+	}
+
+	if failed, err := applySchema(db, changes, true); err != nil {
+		t.Fatalf("applySchema failed on %v: %v", failed, err)
+	}
+	remaining, err := planSchema(db, schema)
+	if err != nil {
+		t.Fatalf("planSchema: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("plan after applying has %d change(s), want none", len(remaining))
+	}
+
+	posts, err := db.GetCollectionBySlug("posts")
+	if err != nil || posts == nil {
+		t.Fatalf("GetCollectionBySlug: %v, %v", posts, err)
+	}
+	fields, err := db.GetCollectionFields(posts.ID)
+	if err != nil {
+		t.Fatalf("GetCollectionFields: %v", err)
+	}
+	var index string
+	err = db.db.QueryRow(`SELECT name FROM sqlite_master WHERE type = 'index' AND name = ?`, fmt.Sprintf("%s%d", fieldIndexPrefix, fields[1].ID)).Scan(&index)
+	if err != nil {
+		t.Errorf("index of the rank field wasn't created: %v", err)
+	}
+}
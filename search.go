@@ -0,0 +1,397 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// Snippets are marked with control characters, which never appear in
+	// indexed text, so they can be escaped before the highlights are added
+	snippetOpen  = "\x02"
+	snippetClose = "\x03"
+)
+
+// searchableFieldTypes are the field types whose values are indexed for
+// full-text search
+var searchableFieldTypes = []string{"text", "textarea", "markdown"}
+
+// SearchResult is an item matching a search, with an extract of the matching
+// text
+type SearchResult struct {
+	Item           Item
+	CollectionSlug string
+	Snippet        string // HTML, with matches wrapped in <mark>
+	Score          float64
+}
+
+// SearchResultResponse represents a search result for JSON API responses
+type SearchResultResponse struct {
+	ItemResponse
+	Collection string  `json:"collection"`
+	Score      float64 `json:"score"`
+}
+
+// indexItem updates the search index entry of an item from its current data
+// and its translations, inside the caller's transaction. Every locale shares
+// one entry, so a search matches an item in any of its languages.
+func indexItem(tx *sql.Tx, itemID int) error {
+	var collectionID int
+	var data string
+	err := tx.QueryRow(`SELECT collection_id, data FROM items WHERE id = ?`, itemID).Scan(&collectionID, &data)
+	if err != nil {
+		return fmt.Errorf("failed to read item for search index: %w", err)
+	}
+
+	query := `
+		SELECT name, localizable FROM collection_fields
+		WHERE collection_id = ? AND type IN (?, ?, ?)
+		ORDER BY sort_order ASC, created_at ASC
+	`
+	rows, err := tx.Query(query, collectionID, searchableFieldTypes[0], searchableFieldTypes[1], searchableFieldTypes[2])
+	if err != nil {
+		return fmt.Errorf("failed to get searchable fields: %w", err)
+	}
+	var names, localizable []string
+	for rows.Next() {
+		var name string
+		var isLocalizable bool
+		if err := rows.Scan(&name, &isLocalizable); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan searchable field: %w", err)
+		}
+		names = append(names, name)
+		if isLocalizable {
+			localizable = append(localizable, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("error iterating searchable fields: %w", err)
+	}
+	rows.Close()
+
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return fmt.Errorf("failed to parse item %d for search index: %w", itemID, err)
+	}
+	content := searchableText(values, names)
+
+	if len(localizable) > 0 {
+		rows, err := tx.Query(`SELECT data FROM item_translations WHERE item_id = ? ORDER BY locale`, itemID)
+		if err != nil {
+			return fmt.Errorf("failed to get translations for search index: %w", err)
+		}
+		for rows.Next() {
+			var translation string
+			if err := rows.Scan(&translation); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan translation: %w", err)
+			}
+			var translated map[string]interface{}
+			if err := json.Unmarshal([]byte(translation), &translated); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to parse translation of item %d for search index: %w", itemID, err)
+			}
+			content = append(content, searchableText(translated, localizable)...)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return fmt.Errorf("error iterating translations: %w", err)
+		}
+		rows.Close()
+	}
+
+	if _, err := tx.Exec(`DELETE FROM item_search WHERE rowid = ?`, itemID); err != nil {
+		return fmt.Errorf("failed to clear search index entry: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO item_search (rowid, content) VALUES (?, ?)`, itemID, strings.Join(content, "\n")); err != nil {
+		return fmt.Errorf("failed to update search index: %w", err)
+	}
+	return nil
+}
+
+// searchableText returns the text values of the named fields in values
+func searchableText(values map[string]interface{}, names []string) []string {
+	var content []string
+	for _, name := range names {
+		value, _ := values[name].(string)
+		// Markdown is stored as {md, html}; index the source
+		if m, ok := values[name].(map[string]interface{}); ok {
+			value, _ = m["md"].(string)
+		}
+		if value != "" {
+			content = append(content, value)
+		}
+	}
+	return content
+}
+
+// indexItems updates the search index entries of every item returned by
+// query, which must select item IDs
+func indexItems(tx *sql.Tx, query string, args ...interface{}) error {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get items to index: %w", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan item ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("error iterating items: %w", err)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		if err := indexItem(tx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reindexCollection rebuilds the search index entries of a collection's
+// items, for when its fields change
+func reindexCollection(tx *sql.Tx, collectionID int) error {
+	return indexItems(tx, `SELECT id FROM items WHERE collection_id = ?`, collectionID)
+}
+
+// backfillSearchIndex indexes items saved before the search index existed,
+// and reindexes translated items, whose translations weren't always indexed
+func (d *Database) backfillSearchIndex() error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id FROM items WHERE id NOT IN (SELECT rowid FROM item_search) OR id IN (SELECT item_id FROM item_translations)`
+	if err := indexItems(tx, query); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit search index: %w", err)
+	}
+	return nil
+}
+
+// searchMatchQuery turns what a user typed into an FTS5 query matching items
+// that contain every word. Words are quoted so punctuation in them is never
+// read as query syntax.
+func searchMatchQuery(q string) string {
+	var terms []string
+	for _, word := range strings.Fields(q) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// highlightSnippet escapes a snippet returned by the search index and wraps
+// the matched words in <mark>
+func highlightSnippet(snippet string) string {
+	return strings.NewReplacer(
+		snippetOpen, "<mark>",
+		snippetClose, "</mark>",
+	).Replace(html.EscapeString(snippet))
+}
+
+// SearchItems returns the items matching filter.Search, best matches first,
+// with the same visibility rules as listing a collection. Items in the trash
+// or in a trashed collection are never returned.
+func (d *Database) SearchItems(filter ItemListFilter, limit, offset int) ([]SearchResult, error) {
+	if searchMatchQuery(filter.Search) == "" {
+		return []SearchResult{}, nil
+	}
+
+	where, args := filter.where()
+	query := `
+		SELECT i.id, i.collection_id, i.slug, i.data, i.status, i.version, i.publish_at, i.unpublish_at, i.created_by, i.created_at, i.updated_at,
+			c.slug, snippet(item_search, 0, ?, ?, '…', 12), bm25(item_search)
+		FROM ` + filter.from() + `
+		JOIN collections c ON c.id = i.collection_id
+		WHERE ` + where + ` AND c.deleted_at IS NULL
+		ORDER BY bm25(item_search) ASC, i.id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	args = append([]interface{}{snippetOpen, snippetClose}, args...)
+	rows, err := d.db.Query(query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search items: %w", err)
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		var result SearchResult
+		var rank float64
+		err := rows.Scan(
+			&result.Item.ID,
+			&result.Item.CollectionID,
+			&result.Item.Slug,
+			&result.Item.Data,
+			&result.Item.Status,
+			&result.Item.Version,
+			&result.Item.PublishAt,
+			&result.Item.UnpublishAt,
+			&result.Item.CreatedBy,
+			&result.Item.CreatedAt,
+			&result.Item.UpdatedAt,
+			&result.CollectionSlug,
+			&result.Snippet,
+			&rank,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		result.Snippet = highlightSnippet(result.Snippet)
+		// bm25 scores are negative, lower being better
+		result.Score = -rank
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w", err)
+	}
+
+	return results, nil
+}
+
+// handleAPISearch searches published items across collections:
+//
+//	GET /api/search?q=hello+world&collection=posts&limit=20&offset=0&locale=de
+//
+// Results are ranked best first and include a highlighted snippet of the
+// matching text. Items match on their text in any locale; locale only
+// chooses the language results are returned in.
+func (s *Server) handleAPISearch(w http.ResponseWriter, r *http.Request) {
+	preview, ok := s.authorizeContentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		s.sendJSONError(w, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	settings, locale, ok := s.requestLocale(w, r)
+	if !ok {
+		return
+	}
+
+	collectionID := 0
+	if slug := query.Get("collection"); slug != "" {
+		collection, err := s.db.GetCollectionBySlug(slug)
+		if err != nil {
+			log.Printf("Error getting collection by slug '%s': %v", slug, err)
+			s.sendJSONError(w, "Failed to get collection", http.StatusInternalServerError)
+			return
+		}
+		if collection == nil {
+			s.sendJSONError(w, "Collection not found", http.StatusNotFound)
+			return
+		}
+		collectionID = collection.ID
+	}
+
+	// Same visibility rules as listing a collection. Preview tokens see
+	// every item in their scope, whatever its status or schedule.
+	filter := ItemListFilter{
+		CollectionID: collectionID,
+		Search:       q,
+		IncludeAll:   preview != nil,
+		Now:          time.Now(),
+	}
+	if preview != nil {
+		if collectionID != 0 && collectionID != preview.CollectionID {
+			s.sendJSONError(w, "Preview token does not cover this collection", http.StatusForbidden)
+			return
+		}
+		filter.CollectionID = preview.CollectionID
+		filter.ItemID = preview.ItemID
+	}
+
+	limit := defaultSearchLimit
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxSearchLimit {
+			s.sendJSONError(w, fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+	offset := 0
+	if value := query.Get("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			s.sendJSONError(w, "offset must be a non-negative number", http.StatusBadRequest)
+			return
+		}
+		offset = parsed
+	}
+
+	results, err := s.db.SearchItems(filter, limit, offset)
+	if err != nil {
+		log.Printf("Error searching for %q: %v", q, err)
+		s.sendJSONError(w, "Failed to search items", http.StatusInternalServerError)
+		return
+	}
+
+	response := []SearchResultResponse{}
+	byCollection := map[int][]int{} // Indexes into response
+	for i := range results {
+		result := SearchResultResponse{
+			ItemResponse: convertItemToResponse(&results[i].Item),
+			Collection:   results[i].CollectionSlug,
+			Score:        results[i].Score,
+		}
+		result.Snippet = results[i].Snippet
+		response = append(response, result)
+		byCollection[result.CollectionID] = append(byCollection[result.CollectionID], i)
+	}
+
+	// Localize each collection's results together
+	for collectionID, indexes := range byCollection {
+		items := make([]ItemResponse, len(indexes))
+		for j, i := range indexes {
+			items[j] = response[i].ItemResponse
+		}
+		if err := s.db.localizeItems(items, collectionID, settings, locale); err != nil {
+			log.Printf("Error localizing search results to %s: %v", locale, err)
+			s.sendJSONError(w, "Failed to localize items", http.StatusInternalServerError)
+			return
+		}
+		for j, i := range indexes {
+			response[i].ItemResponse = items[j]
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSearchMatchQuery(t *testing.T) {
+	tests := []struct {
+		q    string
+		want string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"hello", `"hello"`},
+		{"  hello   world ", `"hello" "world"`},
+		{`say "hi"`, `"say" """hi"""`},
+		{"title:foo OR bar*", `"title:foo" "OR" "bar*"`},
+		{"NEAR(a b)", `"NEAR(a" "b)"`},
+	}
+
+	for _, tt := range tests {
+		if got := searchMatchQuery(tt.q); got != tt.want {
+			t.Errorf("searchMatchQuery(%q) = %q, want %q", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		snippet string
+		want    string
+	}{
+		{"plain text", "plain text"},
+		{"a " + snippetOpen + "match" + snippetClose + " here", "a <mark>match</mark> here"},
+		{snippetOpen + "one" + snippetClose + " and " + snippetOpen + "two" + snippetClose, "<mark>one</mark> and <mark>two</mark>"},
+		{"<script>" + snippetOpen + "x" + snippetClose + "</script>", "&lt;script&gt;<mark>x</mark>&lt;/script&gt;"},
+		{`"quotes" & 'apostrophes'`, "&#34;quotes&#34; &amp; &#39;apostrophes&#39;"},
+	}
+
+	for _, tt := range tests {
+		if got := highlightSnippet(tt.snippet); got != tt.want {
+			t.Errorf("highlightSnippet(%q) = %q, want %q", tt.snippet, got, tt.want)
+		}
+	}
+}
+
+func TestSearchTranslations(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.CreateUser("author", "password", "author@example.com", "admin"); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	author, err := db.GetUserByUsername("author")
+	if err != nil {
+		t.Fatalf("GetUserByUsername: %v", err)
+	}
+	collection, err := db.CreateCollection("Posts", "posts", "", false, "")
+	if err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+	title, err := db.CreateCollectionField(collection.ID, "title", "Title", "text", false, false, true, "", "", 0)
+	if err != nil {
+		t.Fatalf("CreateCollectionField: %v", err)
+	}
+	item, err := db.CreateItem(collection.ID, "coffee", `{"title":"Coffee"}`, "published", author.ID, nil)
+	if err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	search := func(q string) []int {
+		t.Helper()
+		results, err := db.SearchItems(ItemListFilter{Search: q, IncludeAll: true, Now: time.Now()}, 10, 0)
+		if err != nil {
+			t.Fatalf("SearchItems: %v", err)
+		}
+		ids := []int{}
+		for _, result := range results {
+			ids = append(ids, result.Item.ID)
+		}
+		return ids
+	}
+	setLocalizable := func(localizable bool) {
+		t.Helper()
+		if _, err := db.UpdateCollectionField(title.ID, "title", "Title", "text", false, false, localizable, "", "", 0, FieldMigrationOptions{}); err != nil {
+			t.Fatalf("UpdateCollectionField: %v", err)
+		}
+	}
+
+	if err := db.SetItemTranslation(item.ID, "de", map[string]interface{}{"title": "Kaffee"}, 0); err != nil {
+		t.Fatalf("SetItemTranslation: %v", err)
+	}
+	if got := search("kaffee"); !reflect.DeepEqual(got, []int{item.ID}) {
+		t.Errorf("after translating, search = %v, want [%d]", got, item.ID)
+	}
+
+	// Copies take their translations with them
+	copied, err := db.CloneItem(item, "", nil, author.ID)
+	if err != nil {
+		t.Fatalf("CloneItem: %v", err)
+	}
+	both := []int{item.ID, copied.ID}
+	if got := search("kaffee"); !reflect.DeepEqual(got, both) {
+		t.Errorf("after copying, search = %v, want %v", got, both)
+	}
+
+	setLocalizable(false)
+	if got := search("kaffee"); len(got) != 0 {
+		t.Errorf("once the field isn't localizable, search = %v, want nothing", got)
+	}
+	setLocalizable(true)
+	if got := search("kaffee"); !reflect.DeepEqual(got, both) {
+		t.Errorf("once the field is localizable again, search = %v, want %v", got, both)
+	}
+
+	if err := db.DeleteItemTranslation(item.ID, "de", 0); err != nil {
+		t.Fatalf("DeleteItemTranslation: %v", err)
+	}
+	if got := search("kaffee"); !reflect.DeepEqual(got, []int{copied.ID}) {
+		t.Errorf("after removing the translation, search = %v, want [%d]", got, copied.ID)
+	}
+}
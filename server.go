@@ -29,22 +29,50 @@ type Server struct {
 	port          int
 	db            *Database
 	jwtSecret     []byte
+	assetKey      []byte // Signs links to private assets, see assetSignature
+	previewKey    []byte // Signs preview tokens, see signPreviewToken
+	dataDir       string
+	storage       AssetStorage
+	maxUploadSize int64
+
+	// trashRetention is how long deleted content is kept; 0 keeps it until purged by hand
+	trashRetention time.Duration
+
+	// auditRetention is how long audit log entries are kept; 0 keeps them forever
+	auditRetention time.Duration
 }
 
-func NewServer(adminUser, adminPassword string, db *Database) *Server {
+func NewServer(adminUser, adminPassword string, db *Database, dataDir string, storage AssetStorage, maxUploadSize int64) *Server {
 	// Generate a random JWT secret (in production, use a persistent secret)
 	jwtSecret := []byte("lodge-cms-secret-key-change-in-production")
 
 	return &Server{
-		adminUser:     adminUser,
-		adminPassword: adminPassword,
-		port:          1717,
-		db:            db,
-		jwtSecret:     jwtSecret,
+		adminUser:      adminUser,
+		adminPassword:  adminPassword,
+		port:           1717,
+		db:             db,
+		jwtSecret:      jwtSecret,
+		dataDir:        dataDir,
+		storage:        storage,
+		maxUploadSize:  maxUploadSize,
+		trashRetention: defaultTrashRetention,
+		auditRetention: defaultAuditRetention,
 	}
 }
 
 func (s *Server) Start() error {
+	assetKey, err := s.db.AssetSigningKey()
+	if err != nil {
+		return err
+	}
+	s.assetKey = assetKey
+
+	previewKey, err := s.db.PreviewSigningKey()
+	if err != nil {
+		return err
+	}
+	s.previewKey = previewKey
+
 	mux := http.NewServeMux()
 
 	// Admin API routes
@@ -55,14 +83,29 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/admin-api/users", s.handleAdminUsers)
 	mux.HandleFunc("/admin-api/users/", s.handleAdminUsers)
 	mux.HandleFunc("/admin-api/collections", s.handleAdminCollections)
-	mux.HandleFunc("/admin-api/collections/", s.handleAdminCollectionFields)
+	mux.HandleFunc("/admin-api/collections/", s.routeAdminCollection)
 	mux.HandleFunc("/admin-api/items/", s.handleAdminItems)
+	mux.HandleFunc("/admin-api/singletons/", s.handleAdminSingletons)
 	mux.HandleFunc("/admin-api/api-keys", s.handleAdminAPIKeys)
+	mux.HandleFunc("/admin-api/preview-tokens", s.handleAdminPreviewTokens)
+	mux.HandleFunc("/admin-api/trash", s.handleAdminTrash)
+	mux.HandleFunc("/admin-api/trash/", s.handleAdminTrash)
+	mux.HandleFunc("/admin-api/audit", s.handleAdminAudit)
+	mux.HandleFunc("/admin-api/settings/locales", s.handleAdminLocales)
 	mux.HandleFunc("/admin-api/export/", s.handleAdminExportCSV)
 	mux.HandleFunc("/admin-api/import/", s.handleAdminImportCSV)
+	mux.HandleFunc("/admin-api/assets", s.handleAdminAssets)
+	mux.HandleFunc("/admin-api/assets/", s.handleAdminAssets)
 
 	// Public API routes (for CMS content access)
 	mux.HandleFunc("/api/collections/", s.handleAPICollections)
+	mux.HandleFunc("/api/schema/", s.handleAPISchema)
+	mux.HandleFunc("/api/openapi.json", s.handleAPIOpenAPI)
+	mux.HandleFunc("/api/singletons/", s.handleAPISingletons)
+	mux.HandleFunc("/api/search", s.handleAPISearch)
+
+	// Uploaded files
+	mux.HandleFunc("/assets/", s.handleAssets)
 
 	// Static files - serve from disk in dev mode, embedded files in production
 	if isDevelopmentMode() {
@@ -90,6 +133,19 @@ func (s *Server) Start() error {
 		}
 	})
 
+	// Publish and unpublish items at their scheduled times
+	go s.runScheduler(schedulerInterval)
+
+	// Permanently remove trash older than the retention period
+	if s.trashRetention > 0 {
+		go s.runTrashPurge(trashPurgeInterval)
+	}
+
+	// Drop audit log entries older than the retention period
+	if s.auditRetention > 0 {
+		go s.runAuditPurge(auditPurgeInterval)
+	}
+
 	addr := fmt.Sprintf(":%d", s.port)
 	log.Printf("Lodge CMS starting on http://localhost%s", addr)
 	return http.ListenAndServe(addr, mux)
@@ -209,6 +265,10 @@ func (s *Server) handleAdminUsers(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			if created, err := s.db.GetUserByUsername(req.Username); err == nil && created != nil {
+				s.audit(r, user, "user.create", "user", created.ID, nil, auditUser(created))
+			}
+
 			w.WriteHeader(http.StatusCreated)
 
 		default:
@@ -222,11 +282,22 @@ func (s *Server) handleAdminUsers(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			var deleted *User
+			if users, err := s.db.GetUsers(); err == nil {
+				for i := range users {
+					if users[i].ID == id {
+						deleted = &users[i]
+					}
+				}
+			}
+
 			if err := s.db.DeleteUser(id); err != nil {
 				s.sendJSONError(w, "Failed to delete user", http.StatusInternalServerError)
 				return
 			}
 
+			s.audit(r, user, "user.delete", "user", id, auditUser(deleted), nil)
+
 			w.WriteHeader(http.StatusNoContent)
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -315,6 +386,8 @@ func (s *Server) handleAdminCollections(w http.ResponseWriter, r *http.Request)
 				Name        string `json:"name"`
 				Slug        string `json:"slug"`
 				Description string `json:"description"`
+				Singleton   bool   `json:"singleton"`
+				PreviewURL  string `json:"previewUrl"`
 				CreatedAt   string `json:"createdAt"`
 				UpdatedAt   string `json:"updatedAt"`
 			}
@@ -322,11 +395,13 @@ func (s *Server) handleAdminCollections(w http.ResponseWriter, r *http.Request)
 			var response []CollectionResponse
 			for _, collection := range collections {
 				resp := CollectionResponse{
-					ID:        collection.ID,
-					Name:      collection.Name,
-					Slug:      collection.Slug,
-					CreatedAt: collection.CreatedAt.Format("2006-01-02 15:04:05"),
-					UpdatedAt: collection.UpdatedAt.Format("2006-01-02 15:04:05"),
+					ID:         collection.ID,
+					Name:       collection.Name,
+					Slug:       collection.Slug,
+					Singleton:  collection.Singleton,
+					PreviewURL: collection.PreviewURL.String,
+					CreatedAt:  collection.CreatedAt.Format("2006-01-02 15:04:05"),
+					UpdatedAt:  collection.UpdatedAt.Format("2006-01-02 15:04:05"),
 				}
 				if collection.Description.Valid {
 					resp.Description = collection.Description.String
@@ -346,6 +421,8 @@ func (s *Server) handleAdminCollections(w http.ResponseWriter, r *http.Request)
 				Name        string `json:"name"`
 				Slug        string `json:"slug"`
 				Description string `json:"description"`
+				Singleton   bool   `json:"singleton"`
+				PreviewURL  string `json:"previewUrl"`
 			}
 
 			var req CreateCollectionRequest
@@ -359,24 +436,24 @@ func (s *Server) handleAdminCollections(w http.ResponseWriter, r *http.Request)
 				return
 			}
 
-			collection, err := s.db.CreateCollection(req.Name, req.Slug, req.Description)
+			if err := validatePreviewURL(req.PreviewURL); err != nil {
+				s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			collection, err := s.db.CreateCollection(req.Name, req.Slug, req.Description, req.Singleton, req.PreviewURL)
+			if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+				s.sendJSONError(w, "A collection with this name or slug already exists (it may be in the trash)", http.StatusConflict)
+				return
+			}
 			if err != nil {
 				log.Printf("Error creating collection: %v", err)
 				s.sendJSONError(w, "Failed to create collection", http.StatusInternalServerError)
 				return
 			}
 
-			response := map[string]interface{}{
-				"id":          collection.ID,
-				"name":        collection.Name,
-				"slug":        collection.Slug,
-				"description": "",
-				"createdAt":   collection.CreatedAt.Format("2006-01-02 15:04:05"),
-				"updatedAt":   collection.UpdatedAt.Format("2006-01-02 15:04:05"),
-			}
-			if collection.Description.Valid {
-				response["description"] = collection.Description.String
-			}
+			response := collectionResponse(collection)
+			s.audit(r, user, "collection.create", "collection", collection.ID, nil, response)
 
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusCreated)
@@ -386,11 +463,203 @@ func (s *Server) handleAdminCollections(w http.ResponseWriter, r *http.Request)
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	} else {
-		// Handle individual collection operations (future: GET /admin-api/collections/123)
-		s.sendJSONError(w, "Individual collection operations not yet implemented", http.StatusNotImplemented)
+		// Handle individual collection operations: /admin-api/collections/{id}
+		collectionID, err := strconv.Atoi(strings.Trim(path, "/"))
+		if err != nil {
+			s.sendJSONError(w, "Invalid collection ID", http.StatusBadRequest)
+			return
+		}
+		s.handleAdminCollection(w, r, user, collectionID)
+	}
+}
+
+// collectionResponse converts a collection to its JSON API representation
+func collectionResponse(collection *Collection) map[string]interface{} {
+	response := map[string]interface{}{
+		"id":          collection.ID,
+		"name":        collection.Name,
+		"slug":        collection.Slug,
+		"description": "",
+		"singleton":   collection.Singleton,
+		"previewUrl":  collection.PreviewURL.String,
+		"createdAt":   collection.CreatedAt.Format("2006-01-02 15:04:05"),
+		"updatedAt":   collection.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if collection.Description.Valid {
+		response["description"] = collection.Description.String
+	}
+	return response
+}
+
+func (s *Server) handleAdminCollection(w http.ResponseWriter, r *http.Request, user *User, collectionID int) {
+	collection, err := s.db.GetCollectionByID(collectionID)
+	if err != nil {
+		log.Printf("Error getting collection %d: %v", collectionID, err)
+		s.sendJSONError(w, "Failed to get collection", http.StatusInternalServerError)
+		return
+	}
+	if collection == nil {
+		s.sendJSONError(w, "Collection not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		itemCount, fieldCount, err := s.db.CountCollectionContents(collectionID)
+		if err != nil {
+			log.Printf("Error counting collection %d contents: %v", collectionID, err)
+			s.sendJSONError(w, "Failed to get collection", http.StatusInternalServerError)
+			return
+		}
+
+		response := collectionResponse(collection)
+		response["itemCount"] = itemCount
+		response["fieldCount"] = fieldCount
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+
+	case http.MethodPut:
+		// Omitted properties keep their current values
+		var req struct {
+			Name        *string `json:"name"`
+			Slug        *string `json:"slug"`
+			Description *string `json:"description"`
+			Singleton   *bool   `json:"singleton"`
+			PreviewURL  *string `json:"previewUrl"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.sendJSONError(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		name := collection.Name
+		slug := collection.Slug
+		description := collection.Description.String
+		singleton := collection.Singleton
+		previewURL := collection.PreviewURL.String
+		if req.Name != nil {
+			name = *req.Name
+		}
+		if req.Slug != nil {
+			slug = *req.Slug
+		}
+		if req.Description != nil {
+			description = *req.Description
+		}
+		if req.Singleton != nil {
+			singleton = *req.Singleton
+		}
+		if req.PreviewURL != nil {
+			previewURL = *req.PreviewURL
+		}
+
+		if name == "" || slug == "" {
+			s.sendJSONError(w, "Name and slug are required", http.StatusBadRequest)
+			return
+		}
+
+		if err := validatePreviewURL(previewURL); err != nil {
+			s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Refuse to take a slug or name that another collection is using
+		if slug != collection.Slug {
+			existing, err := s.db.GetCollectionBySlug(slug)
+			if err != nil {
+				log.Printf("Error checking collection slug '%s': %v", slug, err)
+				s.sendJSONError(w, "Failed to update collection", http.StatusInternalServerError)
+				return
+			}
+			if existing != nil {
+				s.sendJSONError(w, fmt.Sprintf("Slug '%s' is already used by collection '%s'", slug, existing.Name), http.StatusConflict)
+				return
+			}
+		}
+
+		if err := s.db.UpdateCollection(collectionID, name, slug, description, singleton, previewURL); err != nil {
+			if err == errSingletonHasItems {
+				s.sendJSONError(w, "This collection has more than one item and cannot be made a singleton", http.StatusConflict)
+				return
+			}
+			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+				s.sendJSONError(w, "A collection with this name already exists (it may be in the trash)", http.StatusConflict)
+				return
+			}
+			log.Printf("Error updating collection %d: %v", collectionID, err)
+			s.sendJSONError(w, "Failed to update collection", http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := s.db.GetCollectionByID(collectionID)
+		if err != nil || updated == nil {
+			log.Printf("Error getting updated collection %d: %v", collectionID, err)
+			s.sendJSONError(w, "Failed to get updated collection", http.StatusInternalServerError)
+			return
+		}
+
+		response := collectionResponse(updated)
+		s.audit(r, user, "collection.update", "collection", collectionID, collectionResponse(collection), response)
+		if slug != collection.Slug {
+			response["previousSlug"] = collection.Slug
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+
+	case http.MethodDelete:
+		// ?dryRun=true reports what would be deleted without deleting anything
+		if r.URL.Query().Get("dryRun") == "true" {
+			itemCount, fieldCount, err := s.db.CountCollectionContents(collectionID)
+			if err != nil {
+				log.Printf("Error counting collection %d contents: %v", collectionID, err)
+				s.sendJSONError(w, "Failed to count collection contents", http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"dryRun":        true,
+				"deletedItems":  itemCount,
+				"deletedFields": fieldCount,
+			})
+			return
+		}
+
+		itemCount, fieldCount, err := s.db.DeleteCollection(collectionID)
+		if err != nil {
+			log.Printf("Error deleting collection %d: %v", collectionID, err)
+			s.sendJSONError(w, "Failed to delete collection", http.StatusInternalServerError)
+			return
+		}
+
+		s.audit(r, user, "collection.delete", "collection", collectionID, collectionResponse(collection), nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message":       "Collection moved to the trash",
+			"deletedItems":  itemCount,
+			"deletedFields": fieldCount,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
+// routeAdminCollection sends /admin-api/collections/{id} to the collection
+// handler and deeper paths, such as /admin-api/collections/{id}/fields, to
+// the handler for a collection's fields, workflow and clones
+func (s *Server) routeAdminCollection(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin-api/collections/"), "/")
+	if strings.Contains(path, "/") {
+		s.handleAdminCollectionFields(w, r)
+		return
+	}
+	s.handleAdminCollections(w, r)
+}
+
 func (s *Server) handleAdminCollectionFields(w http.ResponseWriter, r *http.Request) {
 	// Validate JWT token
 	username, err := s.validateJWTToken(r)
@@ -409,6 +678,36 @@ func (s *Server) handleAdminCollectionFields(w http.ResponseWriter, r *http.Requ
 	path := strings.TrimPrefix(r.URL.Path, "/admin-api/collections/")
 	parts := strings.Split(path, "/")
 
+	if len(parts) == 2 && parts[1] == "workflow" {
+		collectionID, err := strconv.Atoi(parts[0])
+		if err != nil {
+			s.sendJSONError(w, "Invalid collection ID", http.StatusBadRequest)
+			return
+		}
+		collection, err := s.db.GetCollectionByID(collectionID)
+		if err != nil || collection == nil {
+			s.sendJSONError(w, "Collection not found", http.StatusNotFound)
+			return
+		}
+		s.handleAdminCollectionWorkflow(w, r, user, collectionID)
+		return
+	}
+
+	if len(parts) == 2 && parts[1] == "clone" {
+		collectionID, err := strconv.Atoi(parts[0])
+		if err != nil {
+			s.sendJSONError(w, "Invalid collection ID", http.StatusBadRequest)
+			return
+		}
+		collection, err := s.db.GetCollectionByID(collectionID)
+		if err != nil || collection == nil {
+			s.sendJSONError(w, "Collection not found", http.StatusNotFound)
+			return
+		}
+		s.handleAdminCollectionClone(w, r, user, collection)
+		return
+	}
+
 	if len(parts) < 2 || parts[1] != "fields" {
 		s.sendJSONError(w, "Invalid endpoint", http.StatusBadRequest)
 		return
@@ -432,6 +731,22 @@ func (s *Server) handleAdminCollectionFields(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Individual field and ordering endpoints: /admin-api/collections/123/fields/{fieldId|order}
+	if len(parts) > 2 && parts[2] != "" {
+		if parts[2] == "order" {
+			s.handleAdminCollectionFieldOrder(w, r, user, collectionID)
+			return
+		}
+
+		fieldID, err := strconv.Atoi(parts[2])
+		if err != nil {
+			s.sendJSONError(w, "Invalid field ID", http.StatusBadRequest)
+			return
+		}
+		s.handleAdminCollectionField(w, r, user, collectionID, fieldID)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		fields, err := s.db.GetCollectionFields(collectionID)
@@ -447,6 +762,8 @@ func (s *Server) handleAdminCollectionFields(w http.ResponseWriter, r *http.Requ
 			Label        string `json:"label"`
 			Type         string `json:"type"`
 			Required     bool   `json:"required"`
+			Indexed      bool   `json:"indexed"`
+			Localizable  bool   `json:"localizable"`
 			Placeholder  string `json:"placeholder"`
 			DefaultValue string `json:"defaultValue"`
 			SortOrder    int    `json:"sortOrder"`
@@ -455,12 +772,14 @@ func (s *Server) handleAdminCollectionFields(w http.ResponseWriter, r *http.Requ
 		var response []FieldResponse
 		for _, field := range fields {
 			resp := FieldResponse{
-				ID:        field.ID,
-				Name:      field.Name,
-				Label:     field.Label,
-				Type:      field.Type,
-				Required:  field.Required,
-				SortOrder: field.SortOrder,
+				ID:          field.ID,
+				Name:        field.Name,
+				Label:       field.Label,
+				Type:        field.Type,
+				Required:    field.Required,
+				Indexed:     field.Indexed,
+				Localizable: field.Localizable,
+				SortOrder:   field.SortOrder,
 			}
 			if field.Placeholder.Valid {
 				resp.Placeholder = field.Placeholder.String
@@ -484,6 +803,8 @@ func (s *Server) handleAdminCollectionFields(w http.ResponseWriter, r *http.Requ
 			Label        string `json:"label"`
 			Type         string `json:"type"`
 			Required     bool   `json:"required"`
+			Indexed      bool   `json:"indexed"`
+			Localizable  bool   `json:"localizable"`
 			Placeholder  string `json:"placeholder"`
 			DefaultValue string `json:"defaultValue"`
 			SortOrder    int    `json:"sortOrder"`
@@ -500,39 +821,246 @@ func (s *Server) handleAdminCollectionFields(w http.ResponseWriter, r *http.Requ
 			return
 		}
 
-		field, err := s.db.CreateCollectionField(collectionID, req.Name, req.Label, req.Type, req.Required, req.Placeholder, req.DefaultValue, req.SortOrder)
+		if req.Indexed && !fieldIndexable(req.Type) {
+			s.sendJSONError(w, fmt.Sprintf("Fields of type %s can't be indexed", req.Type), http.StatusBadRequest)
+			return
+		}
+
+		field, err := s.db.CreateCollectionField(collectionID, req.Name, req.Label, req.Type, req.Required, req.Indexed, req.Localizable, req.Placeholder, req.DefaultValue, req.SortOrder)
 		if err != nil {
 			log.Printf("Error creating collection field: %v", err)
 			s.sendJSONError(w, "Failed to create field", http.StatusInternalServerError)
 			return
 		}
 
-		response := map[string]interface{}{
-			"id":           field.ID,
-			"name":         field.Name,
-			"label":        field.Label,
-			"type":         field.Type,
-			"required":     field.Required,
-			"placeholder":  "",
-			"defaultValue": "",
-			"sortOrder":    field.SortOrder,
+		s.audit(r, user, "field.create", "field", field.ID, nil, fieldResponse(field))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(fieldResponse(field))
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// fieldResponse converts a collection field to its JSON API representation
+func fieldResponse(field *CollectionField) map[string]interface{} {
+	response := map[string]interface{}{
+		"id":           field.ID,
+		"name":         field.Name,
+		"label":        field.Label,
+		"type":         field.Type,
+		"required":     field.Required,
+		"indexed":      field.Indexed,
+		"localizable":  field.Localizable,
+		"placeholder":  "",
+		"defaultValue": "",
+		"sortOrder":    field.SortOrder,
+	}
+	if field.Placeholder.Valid {
+		response["placeholder"] = field.Placeholder.String
+	}
+	if field.DefaultValue.Valid {
+		response["defaultValue"] = field.DefaultValue.String
+	}
+	return response
+}
+
+func (s *Server) handleAdminCollectionField(w http.ResponseWriter, r *http.Request, user *User, collectionID, fieldID int) {
+	field, err := s.db.GetCollectionFieldByID(fieldID)
+	if err != nil {
+		log.Printf("Error getting collection field %d: %v", fieldID, err)
+		s.sendJSONError(w, "Failed to get field", http.StatusInternalServerError)
+		return
+	}
+	if field == nil || field.CollectionID != collectionID {
+		s.sendJSONError(w, "Field not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(fieldResponse(field))
+
+	case http.MethodPut:
+		// Omitted properties keep their current values
+		var req struct {
+			Name         *string `json:"name"`
+			Label        *string `json:"label"`
+			Type         *string `json:"type"`
+			Required     *bool   `json:"required"`
+			Indexed      *bool   `json:"indexed"`
+			Localizable  *bool   `json:"localizable"`
+			Placeholder  *string `json:"placeholder"`
+			DefaultValue *string `json:"defaultValue"`
+			SortOrder    *int    `json:"sortOrder"`
 		}
-		if field.Placeholder.Valid {
-			response["placeholder"] = field.Placeholder.String
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.sendJSONError(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		name := field.Name
+		label := field.Label
+		fieldType := field.Type
+		required := field.Required
+		indexed := field.Indexed
+		localizable := field.Localizable
+		placeholder := field.Placeholder.String
+		defaultValue := field.DefaultValue.String
+		sortOrder := field.SortOrder
+		if req.Name != nil {
+			name = *req.Name
+		}
+		if req.Label != nil {
+			label = *req.Label
+		}
+		if req.Type != nil {
+			fieldType = *req.Type
+		}
+		if req.Required != nil {
+			required = *req.Required
+		}
+		if req.Indexed != nil {
+			indexed = *req.Indexed
+		}
+		if req.Localizable != nil {
+			localizable = *req.Localizable
+		}
+		if req.Placeholder != nil {
+			placeholder = *req.Placeholder
+		}
+		if req.DefaultValue != nil {
+			defaultValue = *req.DefaultValue
+		}
+		if req.SortOrder != nil {
+			sortOrder = *req.SortOrder
+		}
+
+		if name == "" || label == "" || fieldType == "" {
+			s.sendJSONError(w, "Name, label, and type are required", http.StatusBadRequest)
+			return
+		}
+
+		if indexed && !fieldIndexable(fieldType) {
+			s.sendJSONError(w, fmt.Sprintf("Fields of type %s can't be indexed", fieldType), http.StatusBadRequest)
+			return
 		}
-		if field.DefaultValue.Valid {
-			response["defaultValue"] = field.DefaultValue.String
+
+		// Renames and type changes migrate item data. ?dryRun=true reports the
+		// effect without saving; ?dropInvalid=true discards unconvertible values.
+		opts := FieldMigrationOptions{
+			DryRun:      r.URL.Query().Get("dryRun") == "true",
+			DropInvalid: r.URL.Query().Get("dropInvalid") == "true",
 		}
 
+		report, err := s.db.UpdateCollectionField(fieldID, name, label, fieldType, required, indexed, localizable, placeholder, defaultValue, sortOrder, opts)
+		if err == errFieldConversionFailed && !opts.DryRun {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":     fmt.Sprintf("%d item value(s) cannot be migrated from %s (%s) to %s (%s); fix them or retry with dropInvalid=true", len(report.Failures), report.OldName, report.OldType, report.NewName, report.NewType),
+				"migration": report,
+			})
+			return
+		}
+		if err != nil && err != errFieldConversionFailed {
+			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+				s.sendJSONError(w, fmt.Sprintf("A field named '%s' already exists in this collection", name), http.StatusConflict)
+				return
+			}
+			log.Printf("Error updating collection field %d: %v", fieldID, err)
+			s.sendJSONError(w, "Failed to update field", http.StatusInternalServerError)
+			return
+		}
+
+		if opts.DryRun {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"dryRun":    true,
+				"migration": report,
+			})
+			return
+		}
+
+		updated, err := s.db.GetCollectionFieldByID(fieldID)
+		if err != nil || updated == nil {
+			log.Printf("Error getting updated collection field %d: %v", fieldID, err)
+			s.sendJSONError(w, "Failed to get updated field", http.StatusInternalServerError)
+			return
+		}
+
+		response := fieldResponse(updated)
+		response["migration"] = report
+		s.audit(r, user, "field.update", "field", fieldID, fieldResponse(field), response)
+
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
 		json.NewEncoder(w).Encode(response)
 
+	case http.MethodDelete:
+		if err := s.db.DeleteCollectionField(fieldID); err != nil {
+			log.Printf("Error deleting collection field %d: %v", fieldID, err)
+			s.sendJSONError(w, "Failed to delete field", http.StatusInternalServerError)
+			return
+		}
+
+		s.audit(r, user, "field.delete", "field", fieldID, fieldResponse(field), nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Field deleted successfully"})
+
 	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
+// handleAdminCollectionFieldOrder rewrites the order of all fields at once.
+// PUT /admin-api/collections/{id}/fields/order with {"fieldIds": [3, 1, 2]}
+func (s *Server) handleAdminCollectionFieldOrder(w http.ResponseWriter, r *http.Request, user *User, collectionID int) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		FieldIDs []int `json:"fieldIds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.sendJSONError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	problem, err := s.db.ReorderCollectionFields(collectionID, req.FieldIDs)
+	if err != nil {
+		log.Printf("Error reordering fields for collection %d: %v", collectionID, err)
+		s.sendJSONError(w, "Failed to reorder fields", http.StatusInternalServerError)
+		return
+	}
+	if problem != "" {
+		s.sendJSONError(w, problem, http.StatusBadRequest)
+		return
+	}
+
+	s.audit(r, user, "field.reorder", "collection", collectionID, nil, map[string]interface{}{"fieldIds": req.FieldIDs})
+
+	fields, err := s.db.GetCollectionFields(collectionID)
+	if err != nil {
+		log.Printf("Error getting collection fields: %v", err)
+		s.sendJSONError(w, "Failed to fetch fields", http.StatusInternalServerError)
+		return
+	}
+
+	response := []map[string]interface{}{}
+	for i := range fields {
+		response = append(response, fieldResponse(&fields[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
 // Helper functions
 func (s *Server) validateJWTToken(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
@@ -572,9 +1100,16 @@ type ItemResponse struct {
 	Slug         string                 `json:"slug,omitempty"`
 	Data         map[string]interface{} `json:"data"`
 	Status       string                 `json:"status"`
+	Version      int                    `json:"version"`
+	PublishAt    string                 `json:"publishAt,omitempty"`
+	UnpublishAt  string                 `json:"unpublishAt,omitempty"`
 	CreatedBy    *int                   `json:"createdBy,omitempty"`
 	CreatedAt    string                 `json:"createdAt"`
 	UpdatedAt    string                 `json:"updatedAt"`
+	Lock         *ItemLockResponse      `json:"lock,omitempty"`               // Admin API only
+	Unresolved   *int                   `json:"unresolvedComments,omitempty"` // Admin API only
+	Snippet      string                 `json:"snippet,omitempty"`            // Search results only
+	Locale       string                 `json:"locale,omitempty"`             // Public API with locale= only
 }
 
 // convertItemToResponse converts a database Item to an API response
@@ -583,6 +1118,7 @@ func convertItemToResponse(item *Item) ItemResponse {
 		ID:           item.ID,
 		CollectionID: item.CollectionID,
 		Status:       item.Status,
+		Version:      item.Version,
 		CreatedAt:    item.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:    item.UpdatedAt.Format(time.RFC3339),
 	}
@@ -600,6 +1136,14 @@ func convertItemToResponse(item *Item) ItemResponse {
 		response.Slug = item.Slug.String
 	}
 
+	if item.PublishAt.Valid {
+		response.PublishAt = item.PublishAt.Time.UTC().Format(time.RFC3339)
+	}
+
+	if item.UnpublishAt.Valid {
+		response.UnpublishAt = item.UnpublishAt.Time.UTC().Format(time.RFC3339)
+	}
+
 	if item.CreatedBy.Valid {
 		createdBy := int(item.CreatedBy.Int64)
 		response.CreatedBy = &createdBy
@@ -608,6 +1152,49 @@ func convertItemToResponse(item *Item) ItemResponse {
 	return response
 }
 
+// itemETag is the entity tag for the current version of an item
+func itemETag(item *Item) string {
+	return fmt.Sprintf(`"%d"`, item.Version)
+}
+
+// requestedItemVersion returns the item version a client expects to overwrite,
+// taken from the If-Match header or else the version in the request body.
+// 0 means no version was given, or "If-Match: *".
+func requestedItemVersion(r *http.Request, bodyVersion int) (int, error) {
+	match := strings.TrimSpace(r.Header.Get("If-Match"))
+	if match == "" {
+		return bodyVersion, nil
+	}
+	if match == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(match, "W/"), `"`))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("Invalid If-Match header %q", match)
+	}
+	return version, nil
+}
+
+// sendItemConflict responds 409 with the item as it is currently stored, so
+// the client can reload or merge before saving again
+func (s *Server) sendItemConflict(w http.ResponseWriter, itemID int) {
+	item, err := s.db.GetItem(itemID)
+	if err != nil || item == nil {
+		log.Printf("Error getting item %d after version conflict: %v", itemID, err)
+		s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", itemETag(item))
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   "Item has been modified by someone else since it was loaded",
+		"current": convertItemToResponse(item),
+	})
+}
+
 func (s *Server) handleAdminItems(w http.ResponseWriter, r *http.Request) {
 	// Validate JWT token
 	username, err := s.validateJWTToken(r)
@@ -626,7 +1213,11 @@ func (s *Server) handleAdminItems(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/admin-api/items/")
 	parts := strings.Split(path, "/")
 
-	if len(parts) >= 2 && parts[0] == "collection" {
+	if path == "bulk" {
+		// Bulk operations: /admin-api/items/bulk
+		s.handleAdminItemsBulk(w, r, user)
+
+	} else if len(parts) >= 2 && parts[0] == "collection" {
 		// Collection items endpoints: /admin-api/items/collection/{collectionId}
 		collectionID, err := strconv.Atoi(parts[1])
 		if err != nil {
@@ -644,10 +1235,20 @@ func (s *Server) handleAdminItems(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			commentCounts, err := s.db.GetUnresolvedCommentCounts(collectionID)
+			if err != nil {
+				log.Printf("Error counting comments for collection %d: %v", collectionID, err)
+				s.sendJSONError(w, "Failed to get items", http.StatusInternalServerError)
+				return
+			}
+
 			// Convert to response format
 			var responseItems []ItemResponse
 			for _, item := range items {
-				responseItems = append(responseItems, convertItemToResponse(&item))
+				response := convertItemToResponse(&item)
+				unresolved := commentCounts[item.ID]
+				response.Unresolved = &unresolved
+				responseItems = append(responseItems, response)
 			}
 
 			// Ensure we return an empty array instead of null
@@ -661,9 +1262,11 @@ func (s *Server) handleAdminItems(w http.ResponseWriter, r *http.Request) {
 		case http.MethodPost:
 			// Create new item
 			var request struct {
-				Slug   string                 `json:"slug"`
-				Data   map[string]interface{} `json:"data"`
-				Status string                 `json:"status"`
+				Slug        string                 `json:"slug"`
+				Data        map[string]interface{} `json:"data"`
+				Status      string                 `json:"status"`
+				PublishAt   *string                `json:"publishAt"`
+				UnpublishAt *string                `json:"unpublishAt"`
 			}
 
 			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -671,6 +1274,12 @@ func (s *Server) handleAdminItems(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			schedule, err := parseItemSchedule(request.PublishAt, request.UnpublishAt)
+			if err != nil {
+				s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
 			// Convert data to JSON string
 			dataJSON, err := json.Marshal(request.Data)
 			if err != nil {
@@ -678,18 +1287,36 @@ func (s *Server) handleAdminItems(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			// Collections with a workflow start items in its initial state
+			request.Status, err = s.db.resolveItemStatus(collectionID, nil, request.Status)
+			if err == errStatusBypassesWorkflow {
+				s.sendJSONError(w, "This collection has a workflow; new items start in its initial state", http.StatusConflict)
+				return
+			}
+			if err != nil {
+				log.Printf("Error checking workflow for collection %d: %v", collectionID, err)
+				s.sendJSONError(w, "Failed to create item", http.StatusInternalServerError)
+				return
+			}
+
 			// Set default status if not provided
 			if request.Status == "" {
 				request.Status = "draft"
 			}
 
-			item, err := s.db.CreateItem(collectionID, request.Slug, string(dataJSON), request.Status, user.ID)
+			item, err := s.db.CreateItem(collectionID, request.Slug, string(dataJSON), request.Status, user.ID, schedule)
+			if err == errSingletonItemExists {
+				s.sendJSONError(w, "This is a singleton collection and already has an item", http.StatusConflict)
+				return
+			}
 			if err != nil {
 				log.Printf("Error creating item: %v", err)
 				s.sendJSONError(w, "Failed to create item", http.StatusInternalServerError)
 				return
 			}
 
+			s.audit(r, user, "item.create", "item", item.ID, nil, auditItem(item))
+
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(convertItemToResponse(item))
 
@@ -720,15 +1347,29 @@ func (s *Server) handleAdminItems(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			lock, err := s.db.GetItemLock(itemID)
+			if err != nil {
+				log.Printf("Error getting lock for item %d: %v", itemID, err)
+				s.sendJSONError(w, "Failed to get item lock", http.StatusInternalServerError)
+				return
+			}
+
+			response := convertItemToResponse(item)
+			response.Lock = convertItemLockToResponse(lock)
+
 			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(convertItemToResponse(item))
+			w.Header().Set("ETag", itemETag(item))
+			json.NewEncoder(w).Encode(response)
 
 		case http.MethodPut:
 			// Update item
 			var request struct {
-				Slug   string                 `json:"slug"`
-				Data   map[string]interface{} `json:"data"`
-				Status string                 `json:"status"`
+				Slug        string                 `json:"slug"`
+				Data        map[string]interface{} `json:"data"`
+				Status      string                 `json:"status"`
+				Version     int                    `json:"version"`
+				PublishAt   *string                `json:"publishAt"`
+				UnpublishAt *string                `json:"unpublishAt"`
 			}
 
 			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -736,6 +1377,23 @@ func (s *Server) handleAdminItems(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			schedule, err := parseItemSchedule(request.PublishAt, request.UnpublishAt)
+			if err != nil {
+				s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			// Require the version being edited so concurrent saves can't overwrite each other
+			expectedVersion, err := requestedItemVersion(r, request.Version)
+			if err != nil {
+				s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if expectedVersion == 0 && r.Header.Get("If-Match") == "" {
+				s.sendJSONError(w, "An If-Match header or version is required to update an item", http.StatusPreconditionRequired)
+				return
+			}
+
 			// Convert data to JSON string
 			dataJSON, err := json.Marshal(request.Data)
 			if err != nil {
@@ -743,7 +1401,34 @@ func (s *Server) handleAdminItems(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			err = s.db.UpdateItem(itemID, request.Slug, string(dataJSON), request.Status)
+			current, err := s.db.GetItem(itemID)
+			if err != nil {
+				log.Printf("Error getting item %d: %v", itemID, err)
+				s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+				return
+			}
+			if current == nil {
+				s.sendJSONError(w, "Item not found", http.StatusNotFound)
+				return
+			}
+
+			// Collections with a workflow only change status through transitions
+			request.Status, err = s.db.resolveItemStatus(current.CollectionID, current, request.Status)
+			if err == errStatusBypassesWorkflow {
+				s.sendJSONError(w, fmt.Sprintf("This collection has a workflow; change the status with POST /admin-api/items/%d/transitions", itemID), http.StatusConflict)
+				return
+			}
+			if err != nil {
+				log.Printf("Error checking workflow for item %d: %v", itemID, err)
+				s.sendJSONError(w, "Failed to update item", http.StatusInternalServerError)
+				return
+			}
+
+			err = s.db.UpdateItem(itemID, request.Slug, string(dataJSON), request.Status, user.ID, expectedVersion, schedule)
+			if err == errItemVersionConflict {
+				s.sendItemConflict(w, itemID)
+				return
+			}
 			if err != nil {
 				log.Printf("Error updating item %d: %v", itemID, err)
 				s.sendJSONError(w, "Failed to update item", http.StatusInternalServerError)
@@ -758,26 +1443,92 @@ func (s *Server) handleAdminItems(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			s.audit(r, user, "item.update", "item", itemID, auditItem(current), auditItem(item))
+
 			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", itemETag(item))
 			json.NewEncoder(w).Encode(convertItemToResponse(item))
 
 		case http.MethodDelete:
-			// Delete item
-			err := s.db.DeleteItem(itemID)
+			// Delete item, keeping its last state for the audit log
+			deleted, err := s.db.GetItem(itemID)
+			if err != nil {
+				log.Printf("Error getting item %d: %v", itemID, err)
+				s.sendJSONError(w, "Failed to delete item", http.StatusInternalServerError)
+				return
+			}
+
+			err = s.db.DeleteItem(itemID)
 			if err != nil {
 				log.Printf("Error deleting item %d: %v", itemID, err)
 				s.sendJSONError(w, "Failed to delete item", http.StatusInternalServerError)
 				return
 			}
 
+			s.audit(r, user, "item.delete", "item", itemID, auditItem(deleted), nil)
+
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
-			json.NewEncoder(w).Encode(map[string]string{"message": "Item deleted successfully"})
+			json.NewEncoder(w).Encode(map[string]string{"message": "Item moved to the trash"})
 
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 
+	} else if len(parts) >= 2 && parts[1] == "revisions" {
+		// Revision history: /admin-api/items/{itemId}/revisions/...
+		itemID, err := strconv.Atoi(parts[0])
+		if err != nil {
+			s.sendJSONError(w, "Invalid item ID", http.StatusBadRequest)
+			return
+		}
+		s.handleAdminItemRevisions(w, r, user, itemID, parts[2:])
+
+	} else if len(parts) == 2 && parts[1] == "transitions" {
+		// Workflow: /admin-api/items/{itemId}/transitions
+		itemID, err := strconv.Atoi(parts[0])
+		if err != nil {
+			s.sendJSONError(w, "Invalid item ID", http.StatusBadRequest)
+			return
+		}
+		s.handleAdminItemTransitions(w, r, user, itemID)
+
+	} else if len(parts) == 2 && parts[1] == "clone" {
+		// Duplicate: /admin-api/items/{itemId}/clone
+		itemID, err := strconv.Atoi(parts[0])
+		if err != nil {
+			s.sendJSONError(w, "Invalid item ID", http.StatusBadRequest)
+			return
+		}
+		s.handleAdminItemClone(w, r, user, itemID)
+
+	} else if len(parts) >= 2 && parts[1] == "comments" {
+		// Comments: /admin-api/items/{itemId}/comments/...
+		itemID, err := strconv.Atoi(parts[0])
+		if err != nil {
+			s.sendJSONError(w, "Invalid item ID", http.StatusBadRequest)
+			return
+		}
+		s.handleAdminItemComments(w, r, user, itemID, parts[2:])
+
+	} else if len(parts) >= 2 && parts[1] == "translations" {
+		// Translations: /admin-api/items/{itemId}/translations/{locale}
+		itemID, err := strconv.Atoi(parts[0])
+		if err != nil {
+			s.sendJSONError(w, "Invalid item ID", http.StatusBadRequest)
+			return
+		}
+		s.handleAdminItemTranslations(w, r, user, itemID, parts[2:])
+
+	} else if len(parts) == 2 && parts[1] == "lock" {
+		// Edit lock: /admin-api/items/{itemId}/lock
+		itemID, err := strconv.Atoi(parts[0])
+		if err != nil {
+			s.sendJSONError(w, "Invalid item ID", http.StatusBadRequest)
+			return
+		}
+		s.handleAdminItemLock(w, r, user, itemID)
+
 	} else {
 		s.sendJSONError(w, "Invalid items endpoint", http.StatusBadRequest)
 	}
@@ -859,12 +1610,15 @@ func (s *Server) handleAdminAPIKeys(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		fullKey, err := s.db.CreateAPIKey(req.Name, user.ID)
+		created, fullKey, err := s.db.CreateAPIKey(req.Name, user.ID)
 		if err != nil {
+			log.Printf("Error creating API key: %v", err)
 			s.sendJSONError(w, "Failed to create API key", http.StatusInternalServerError)
 			return
 		}
 
+		s.audit(r, user, "api_key.create", "api_key", created.ID, nil, auditAPIKey(created))
+
 		response := map[string]string{
 			"key":     fullKey,
 			"message": "API key created successfully. Store this key securely - it won't be shown again.",
@@ -888,11 +1642,24 @@ func (s *Server) handleAdminAPIKeys(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		deleted, err := s.db.GetAPIKey(id)
+		if err != nil {
+			log.Printf("Error getting API key %d: %v", id, err)
+			s.sendJSONError(w, "Failed to delete API key", http.StatusInternalServerError)
+			return
+		}
+		if deleted == nil {
+			s.sendJSONError(w, "API key not found", http.StatusNotFound)
+			return
+		}
+
 		if err := s.db.DeleteAPIKey(id); err != nil {
 			s.sendJSONError(w, "Failed to delete API key", http.StatusInternalServerError)
 			return
 		}
 
+		s.audit(r, user, "api_key.delete", "api_key", id, auditAPIKey(deleted), nil)
+
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{"message": "API key deleted successfully"})
 
@@ -902,10 +1669,9 @@ func (s *Server) handleAdminAPIKeys(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleAPICollections(w http.ResponseWriter, r *http.Request) {
-	// Validate API key for all public API requests
-	_, err := s.validateAPIKey(r)
-	if err != nil {
-		s.sendJSONError(w, "Invalid or missing API key", http.StatusUnauthorized)
+	// Validate API key, or a preview token for unpublished content
+	preview, ok := s.authorizeContentRequest(w, r)
+	if !ok {
 		return
 	}
 
@@ -939,10 +1705,18 @@ func (s *Server) handleAPICollections(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if collection == nil {
+			if s.redirectRenamedCollection(w, r, collectionName) {
+				return
+			}
 			s.sendJSONError(w, "Collection not found", http.StatusNotFound)
 			return
 		}
 
+		if preview != nil && !preview.allows(collection.ID, 0) {
+			s.sendJSONError(w, "Preview token does not cover this collection", http.StatusForbidden)
+			return
+		}
+
 		// Parse pagination query parameters
 		query := r.URL.Query()
 		limit := 50 // Default limit
@@ -964,25 +1738,80 @@ func (s *Server) handleAPICollections(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		// Get items for this collection with pagination
-		items, err := s.db.GetItemsByCollectionWithPagination(collection.ID, limit, offset)
+		settings, locale, ok := s.requestLocale(w, r)
+		if !ok {
+			return
+		}
+
+		// Field filters, sorting and facets are checked before any items are
+		// fetched
+		fields, err := s.db.GetCollectionFields(collection.ID)
+		if err != nil {
+			log.Printf("Error getting fields for collection '%s': %v", collectionName, err)
+			s.sendJSONError(w, "Failed to get collection fields", http.StatusInternalServerError)
+			return
+		}
+
+		filter := ItemListFilter{
+			CollectionID: collection.ID,
+			Search:       strings.TrimSpace(query.Get("q")),
+			IncludeAll:   preview != nil,
+			Now:          time.Now(),
+		}
+		fieldFilters, listSort, problem := parseItemListQuery(fields, query)
+		if problem != "" {
+			s.sendJSONError(w, problem, http.StatusBadRequest)
+			return
+		}
+		filter.Fields = fieldFilters
+
+		var facetFieldList []CollectionField
+		if names := query.Get("facets"); names != "" {
+			facetFieldList, problem = facetFields(fields, names)
+			if problem != "" {
+				s.sendJSONError(w, problem, http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Get a page of items. Which items are visible is decided in SQL,
+		// with filtering and sorting, so pages are never short.
+		items, snippets, err := s.db.ListItems(filter, listSort, limit, offset)
 		if err != nil {
 			log.Printf("Error getting items for collection '%s': %v", collectionName, err)
 			s.sendJSONError(w, "Failed to get items", http.StatusInternalServerError)
 			return
 		}
 
-		// Convert to response format and filter for published items only
-		var responseItems []ItemResponse
+		responseItems := []ItemResponse{}
 		for _, item := range items {
-			if item.Status == "published" || item.Status == "draft" { // For now, include both - can be made configurable
-				responseItems = append(responseItems, convertItemToResponse(&item))
-			}
+			response := convertItemToResponse(&item)
+			response.Snippet = snippets[item.ID]
+			responseItems = append(responseItems, response)
 		}
 
-		// Ensure we return an empty array instead of null
-		if responseItems == nil {
-			responseItems = []ItemResponse{}
+		// Filters, sorts and facets always use the default locale's values
+		if err := s.db.localizeItems(responseItems, collection.ID, settings, locale); err != nil {
+			log.Printf("Error localizing items of collection '%s' to %s: %v", collectionName, locale, err)
+			s.sendJSONError(w, "Failed to localize items", http.StatusInternalServerError)
+			return
+		}
+
+		// With facets the items are wrapped in an object alongside the counts
+		if facetFieldList != nil {
+			facets, err := s.db.GetItemFacets(filter, facetFieldList)
+			if err != nil {
+				log.Printf("Error getting facets for collection '%s': %v", collectionName, err)
+				s.sendJSONError(w, "Failed to get facets", http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"items":  responseItems,
+				"facets": facets,
+			})
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -1014,10 +1843,18 @@ func (s *Server) handleAPICollections(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if collection == nil {
+			if s.redirectRenamedCollection(w, r, collectionName) {
+				return
+			}
 			s.sendJSONError(w, "Collection not found", http.StatusNotFound)
 			return
 		}
 
+		settings, locale, ok := s.requestLocale(w, r)
+		if !ok {
+			return
+		}
+
 		// Get the specific item
 		item, err := s.db.GetItem(itemID)
 		if err != nil {
@@ -1037,20 +1874,53 @@ func (s *Server) handleAPICollections(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		// Check if item is published (for public API)
-		if item.Status != "published" && item.Status != "draft" { // For now, include both - can be made configurable
+		// Previews show the item whatever its status or schedule
+		if preview != nil {
+			if !preview.allows(collection.ID, item.ID) {
+				s.sendJSONError(w, "Preview token does not cover this item", http.StatusForbidden)
+				return
+			}
+			s.sendLocalizedItem(w, item, settings, locale)
+			return
+		}
+
+		// Only published items inside their publishing window are public
+		if !item.isPublic(time.Now()) {
 			s.sendJSONError(w, "Item not available", http.StatusNotFound)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(convertItemToResponse(item))
+		s.sendLocalizedItem(w, item, settings, locale)
 
 	} else {
 		s.sendJSONError(w, "Invalid API endpoint", http.StatusBadRequest)
 	}
 }
 
+// redirectRenamedCollection permanently redirects requests that use the old
+// slug of a renamed collection. It reports whether a redirect was sent.
+func (s *Server) redirectRenamedCollection(w http.ResponseWriter, r *http.Request, slug string) bool {
+	collection, err := s.db.GetCollectionByPreviousSlug(slug)
+	if err != nil {
+		log.Printf("Error looking up previous slug '%s': %v", slug, err)
+		return false
+	}
+	if collection == nil {
+		return false
+	}
+
+	prefix := "/api/collections/"
+	if strings.HasPrefix(r.URL.Path, "/api/singletons/") {
+		prefix = "/api/singletons/"
+	}
+	target := prefix + collection.Slug + strings.TrimPrefix(r.URL.Path, prefix+slug)
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	http.Redirect(w, r, target, http.StatusMovedPermanently)
+	return true
+}
+
 // Validate API key from X-API-Key header
 func (s *Server) validateAPIKey(r *http.Request) (*APIKey, error) {
 	apiKey := r.Header.Get("X-API-Key")
@@ -1146,6 +2016,29 @@ func (s *Server) handleAdminExportCSV(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// With ?locale= only localizable fields are exported, with their values
+	// in that locale and no fallbacks, ready to be translated and imported
+	locale := r.URL.Query().Get("locale")
+	var settings *LocaleSettings
+	if locale != "" {
+		settings, err = s.db.GetLocaleSettings()
+		if err != nil {
+			s.sendJSONError(w, "Failed to get locales", http.StatusInternalServerError)
+			return
+		}
+		if !settings.has(locale) {
+			s.sendJSONError(w, fmt.Sprintf("Unknown locale '%s'", locale), http.StatusBadRequest)
+			return
+		}
+		var localizable []CollectionField
+		for _, field := range fields {
+			if field.Localizable {
+				localizable = append(localizable, field)
+			}
+		}
+		fields = localizable
+	}
+
 	// Get items for the collection
 	statusFilter := r.URL.Query().Get("status")
 	items, err := s.db.GetItemsByCollection(collectionID)
@@ -1154,9 +2047,27 @@ func (s *Server) handleAdminExportCSV(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var translations map[int]map[string]map[string]interface{}
+	if locale != "" && locale != settings.defaultLocale() {
+		var ids []int
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		translations, err = s.db.getTranslations(ids, []string{locale})
+		if err != nil {
+			log.Printf("Error getting translations for export: %v", err)
+			s.sendJSONError(w, "Failed to get translations", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	// Set CSV headers
+	filename := collection.Slug + "-export.csv"
+	if locale != "" {
+		filename = collection.Slug + "-" + locale + "-export.csv"
+	}
 	w.Header().Set("Content-Type", "text/csv")
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-export.csv\"", collection.Slug))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
 
 	// Create CSV writer
 	csvWriter := csv.NewWriter(w)
@@ -1188,9 +2099,15 @@ func (s *Server) handleAdminExportCSV(w http.ResponseWriter, r *http.Request) {
 			item.UpdatedAt.Format(time.RFC3339),
 		}
 
-		// Parse JSON data
+		// Parse JSON data, or take the values of the exported locale
 		var data map[string]interface{}
-		if err := json.Unmarshal([]byte(item.Data), &data); err != nil {
+		var err error
+		if translations != nil {
+			data = translations[item.ID][locale]
+		} else {
+			err = json.Unmarshal([]byte(item.Data), &data)
+		}
+		if err != nil {
 			log.Printf("Error parsing item data for ID %d: %v", item.ID, err)
 			// Add empty values for fields if we can't parse data
 			for range fields {
@@ -1207,7 +2124,7 @@ func (s *Server) handleAdminExportCSV(w http.ResponseWriter, r *http.Request) {
 						if boolVal, ok := fieldValue.(bool); ok {
 							value = strconv.FormatBool(boolVal)
 						}
-					case "number":
+					case "number", "asset":
 						switch v := fieldValue.(type) {
 						case float64:
 							value = strconv.FormatFloat(v, 'f', -1, 64)
@@ -1218,8 +2135,15 @@ func (s *Server) handleAdminExportCSV(w http.ResponseWriter, r *http.Request) {
 						if dateStr, ok := fieldValue.(string); ok {
 							value = dateStr
 						}
+					case "markdown":
+						// Export the source of {md, html} values
+						if m, ok := fieldValue.(map[string]interface{}); ok {
+							value, _ = m["md"].(string)
+						} else if strVal, ok := fieldValue.(string); ok {
+							value = strVal
+						}
 					default:
-						// For text, markdown, email, url, textarea - treat as string
+						// For text, email, url, textarea - treat as string
 						if strVal, ok := fieldValue.(string); ok {
 							value = strVal
 						}
@@ -1314,6 +2238,12 @@ func (s *Server) handleAdminImportCSV(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A locale imports translations of existing items instead
+	if locale := r.FormValue("locale"); locale != "" {
+		s.importCSVTranslations(w, r, user, collectionID, fields, csvReader, headers, locale)
+		return
+	}
+
 	// Create field map for quick lookup
 	fieldMap := make(map[string]*CollectionField)
 	for i := range fields {
@@ -1387,6 +2317,15 @@ func (s *Server) handleAdminImportCSV(w http.ResponseWriter, r *http.Request) {
 								continue
 							}
 						}
+					case "asset":
+						if value != "" {
+							if assetID, err := strconv.Atoi(value); err == nil {
+								convertedValue = assetID
+							} else {
+								errors = append(errors, fmt.Sprintf("Row %d: Invalid asset ID for field '%s'", rowNumber, header))
+								continue
+							}
+						}
 					case "date":
 						// Keep as string, frontend handles date parsing
 						convertedValue = value
@@ -1408,13 +2347,30 @@ func (s *Server) handleAdminImportCSV(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Check if we should skip or update existing items
-		if importMode == "create_only" && itemID > 0 {
-			// Check if item exists
-			existingItem, _ := s.db.GetItem(itemID)
-			if existingItem != nil {
-				skippedCount++
-				continue
-			}
+		var existingItem *Item
+		if itemID > 0 {
+			existingItem, _ = s.db.GetItem(itemID)
+		}
+		if importMode == "create_only" && existingItem != nil {
+			skippedCount++
+			continue
+		}
+
+		// Collections with a workflow only change status through transitions
+		var current *Item
+		if importMode == "upsert" {
+			current = existingItem
+		}
+		itemStatus, err = s.db.resolveItemStatus(collectionID, current, itemStatus)
+		if err == errStatusBypassesWorkflow {
+			errors = append(errors, fmt.Sprintf("Row %d: Status can't be changed by import in a collection with a workflow", rowNumber))
+			errorCount++
+			continue
+		}
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Row %d: Failed to check workflow - %v", rowNumber, err))
+			errorCount++
+			continue
 		}
 
 		// Convert data to JSON
@@ -1428,7 +2384,7 @@ func (s *Server) handleAdminImportCSV(w http.ResponseWriter, r *http.Request) {
 		// Create or update item
 		if importMode == "upsert" && itemID > 0 {
 			// Update existing item
-			err = s.db.UpdateItem(itemID, itemSlug, string(dataJSON), itemStatus)
+			err = s.db.UpdateItem(itemID, itemSlug, string(dataJSON), itemStatus, user.ID, 0, nil)
 			if err != nil {
 				errors = append(errors, fmt.Sprintf("Row %d: Failed to update item - %v", rowNumber, err))
 				errorCount++
@@ -1437,7 +2393,7 @@ func (s *Server) handleAdminImportCSV(w http.ResponseWriter, r *http.Request) {
 			}
 		} else {
 			// Create new item
-			_, err = s.db.CreateItem(collectionID, itemSlug, string(dataJSON), itemStatus, user.ID)
+			_, err = s.db.CreateItem(collectionID, itemSlug, string(dataJSON), itemStatus, user.ID, nil)
 			if err != nil {
 				errors = append(errors, fmt.Sprintf("Row %d: Failed to create item - %v", rowNumber, err))
 				errorCount++
@@ -1455,6 +2411,7 @@ func (s *Server) handleAdminImportCSV(w http.ResponseWriter, r *http.Request) {
 		"totalRows":     rowNumber - 1,
 		"errorMessages": errors,
 	}
+	s.audit(r, user, "collection.import", "collection", collectionID, nil, response)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
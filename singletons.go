@@ -0,0 +1,269 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GetSingletonItem returns the only item of a singleton collection, or nil if
+// it hasn't been created yet
+func (d *Database) GetSingletonItem(collectionID int) (*Item, error) {
+	query := `
+		SELECT id, collection_id, slug, data, status, version, publish_at, unpublish_at, created_by, created_at, updated_at
+		FROM items
+		WHERE collection_id = ? AND deleted_at IS NULL
+		ORDER BY id
+		LIMIT 1
+	`
+
+	var item Item
+	err := d.db.QueryRow(query, collectionID).Scan(
+		&item.ID,
+		&item.CollectionID,
+		&item.Slug,
+		&item.Data,
+		&item.Status,
+		&item.Version,
+		&item.PublishAt,
+		&item.UnpublishAt,
+		&item.CreatedBy,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get singleton item: %w", err)
+	}
+
+	return &item, nil
+}
+
+// getSingletonCollection looks up a collection by slug and checks that it is
+// a singleton, writing an error response if not
+func (s *Server) getSingletonCollection(w http.ResponseWriter, slug string) *Collection {
+	if slug == "" || strings.Contains(slug, "/") {
+		s.sendJSONError(w, "Invalid singleton path", http.StatusBadRequest)
+		return nil
+	}
+
+	collection, err := s.db.GetCollectionBySlug(slug)
+	if err != nil {
+		log.Printf("Error getting collection by slug '%s': %v", slug, err)
+		s.sendJSONError(w, "Failed to get collection", http.StatusInternalServerError)
+		return nil
+	}
+	if collection == nil {
+		s.sendJSONError(w, "Singleton not found", http.StatusNotFound)
+		return nil
+	}
+	if !collection.Singleton {
+		s.sendJSONError(w, fmt.Sprintf("Collection '%s' is not a singleton; use /api/collections/%s", slug, slug), http.StatusNotFound)
+		return nil
+	}
+
+	return collection
+}
+
+// handleAPISingletons serves GET /api/singletons/{slug}
+func (s *Server) handleAPISingletons(w http.ResponseWriter, r *http.Request) {
+	// Validate API key, or a preview token for unpublished content
+	preview, ok := s.authorizeContentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	slug := strings.TrimPrefix(r.URL.Path, "/api/singletons/")
+	if collection, err := s.db.GetCollectionBySlug(slug); err == nil && collection == nil {
+		if s.redirectRenamedCollection(w, r, slug) {
+			return
+		}
+	}
+
+	collection := s.getSingletonCollection(w, slug)
+	if collection == nil {
+		return
+	}
+
+	settings, locale, ok := s.requestLocale(w, r)
+	if !ok {
+		return
+	}
+
+	item, err := s.db.GetSingletonItem(collection.ID)
+	if err != nil {
+		log.Printf("Error getting singleton '%s': %v", slug, err)
+		s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+		return
+	}
+
+	if preview != nil {
+		itemID := 0
+		if item != nil {
+			itemID = item.ID
+		}
+		if !preview.allows(collection.ID, itemID) {
+			s.sendJSONError(w, "Preview token does not cover this singleton", http.StatusForbidden)
+			return
+		}
+	}
+
+	// Only published items inside their publishing window are public, as in
+	// the collections API. Previews show the item whatever its status or schedule.
+	if item == nil || (preview == nil && !item.isPublic(time.Now())) {
+		s.sendJSONError(w, "Singleton has no content yet", http.StatusNotFound)
+		return
+	}
+
+	s.sendLocalizedItem(w, item, settings, locale)
+}
+
+// handleAdminSingletons reads and writes the item of a singleton collection:
+//
+//	GET /admin-api/singletons/{slug}
+//	PUT /admin-api/singletons/{slug}  (creates the item if it doesn't exist)
+func (s *Server) handleAdminSingletons(w http.ResponseWriter, r *http.Request) {
+	// Validate JWT token
+	username, err := s.validateJWTToken(r)
+	if err != nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := s.db.GetUserByUsername(username)
+	if err != nil || user == nil {
+		s.sendJSONError(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	collection := s.getSingletonCollection(w, strings.TrimPrefix(r.URL.Path, "/admin-api/singletons/"))
+	if collection == nil {
+		return
+	}
+
+	item, err := s.db.GetSingletonItem(collection.ID)
+	if err != nil {
+		log.Printf("Error getting singleton '%s': %v", collection.Slug, err)
+		s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if item == nil {
+			s.sendJSONError(w, "Singleton has no content yet", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", itemETag(item))
+		json.NewEncoder(w).Encode(convertItemToResponse(item))
+
+	case http.MethodPut:
+		var request struct {
+			Slug        string                 `json:"slug"`
+			Data        map[string]interface{} `json:"data"`
+			Status      string                 `json:"status"`
+			Version     int                    `json:"version"`
+			PublishAt   *string                `json:"publishAt"`
+			UnpublishAt *string                `json:"unpublishAt"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			s.sendJSONError(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		schedule, err := parseItemSchedule(request.PublishAt, request.UnpublishAt)
+		if err != nil {
+			s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The first PUT creates the item, so only updates need the version
+		expectedVersion, err := requestedItemVersion(r, request.Version)
+		if err != nil {
+			s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if item != nil && expectedVersion == 0 && r.Header.Get("If-Match") == "" {
+			s.sendJSONError(w, "An If-Match header or version is required to update an item", http.StatusPreconditionRequired)
+			return
+		}
+
+		dataJSON, err := json.Marshal(request.Data)
+		if err != nil {
+			s.sendJSONError(w, "Failed to encode data", http.StatusInternalServerError)
+			return
+		}
+
+		// Collections with a workflow only change status through transitions
+		request.Status, err = s.db.resolveItemStatus(collection.ID, item, request.Status)
+		if err == errStatusBypassesWorkflow {
+			s.sendJSONError(w, "This singleton has a workflow; change its status with the item's transitions endpoint", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			log.Printf("Error checking workflow for singleton '%s': %v", collection.Slug, err)
+			s.sendJSONError(w, "Failed to save item", http.StatusInternalServerError)
+			return
+		}
+
+		before := item
+		status := http.StatusOK
+		if item == nil {
+			if request.Status == "" {
+				request.Status = "draft"
+			}
+			item, err = s.db.CreateItem(collection.ID, request.Slug, string(dataJSON), request.Status, user.ID, schedule)
+			status = http.StatusCreated
+		} else {
+			if request.Status == "" {
+				request.Status = item.Status
+			}
+			err = s.db.UpdateItem(item.ID, request.Slug, string(dataJSON), request.Status, user.ID, expectedVersion, schedule)
+			if err == nil {
+				item, err = s.db.GetItem(item.ID)
+			}
+		}
+		if err == errSingletonItemExists {
+			s.sendJSONError(w, "Singleton was created by another request; retry", http.StatusConflict)
+			return
+		}
+		if err == errItemVersionConflict {
+			s.sendItemConflict(w, item.ID)
+			return
+		}
+		if err != nil || item == nil {
+			log.Printf("Error saving singleton '%s': %v", collection.Slug, err)
+			s.sendJSONError(w, "Failed to save item", http.StatusInternalServerError)
+			return
+		}
+
+		action := "item.update"
+		if before == nil {
+			action = "item.create"
+		}
+		s.audit(r, user, action, "item", item.ID, auditItem(before), auditItem(item))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", itemETag(item))
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(convertItemToResponse(item))
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,493 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultTrashRetention is how long deleted items and collections are kept
+	// before they are purged automatically
+	defaultTrashRetention = 30 * 24 * time.Hour
+
+	// trashPurgeInterval is how often expired trash is purged
+	trashPurgeInterval = time.Hour
+)
+
+var (
+	errNotInTrash        = errors.New("not in trash")
+	errCollectionInTrash = errors.New("collection is in the trash")
+)
+
+// TrashedItem is an item that was deleted on its own
+type TrashedItem struct {
+	ID             int            `json:"id"`
+	CollectionID   int            `json:"collectionId"`
+	CollectionName string         `json:"collectionName"`
+	CollectionSlug string         `json:"collectionSlug"`
+	Slug           sql.NullString `json:"slug"`
+	Data           string         `json:"data"`
+	Status         string         `json:"status"`
+	DeletedAt      time.Time      `json:"deletedAt"`
+	// CollectionDeleted is set when the item's collection is in the trash
+	// too, in which case the collection must be restored first
+	CollectionDeleted bool `json:"collectionDeleted"`
+}
+
+// TrashedCollection is a deleted collection. Its items and fields stay with
+// it and come back when it is restored.
+type TrashedCollection struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	ItemCount  int       `json:"itemCount"`
+	FieldCount int       `json:"fieldCount"`
+	DeletedAt  time.Time `json:"deletedAt"`
+}
+
+// GetTrash returns everything in the trash, most recently deleted first
+func (d *Database) GetTrash() ([]TrashedItem, []TrashedCollection, error) {
+	itemRows, err := d.db.Query(`
+		SELECT i.id, i.collection_id, c.name, c.slug, i.slug, i.data, i.status, i.deleted_at, c.deleted_at IS NOT NULL
+		FROM items i
+		JOIN collections c ON c.id = i.collection_id
+		WHERE i.deleted_at IS NOT NULL
+		ORDER BY i.deleted_at DESC, i.id DESC
+	`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get trashed items: %w", err)
+	}
+	defer itemRows.Close()
+
+	items := []TrashedItem{}
+	for itemRows.Next() {
+		var item TrashedItem
+		err := itemRows.Scan(&item.ID, &item.CollectionID, &item.CollectionName, &item.CollectionSlug, &item.Slug, &item.Data, &item.Status, &item.DeletedAt, &item.CollectionDeleted)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan trashed item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating trashed items: %w", err)
+	}
+
+	collectionRows, err := d.db.Query(`
+		SELECT c.id, c.name, c.slug, c.deleted_at,
+			(SELECT COUNT(*) FROM items WHERE collection_id = c.id AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM collection_fields WHERE collection_id = c.id)
+		FROM collections c
+		WHERE c.deleted_at IS NOT NULL
+		ORDER BY c.deleted_at DESC, c.id DESC
+	`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get trashed collections: %w", err)
+	}
+	defer collectionRows.Close()
+
+	collections := []TrashedCollection{}
+	for collectionRows.Next() {
+		var c TrashedCollection
+		if err := collectionRows.Scan(&c.ID, &c.Name, &c.Slug, &c.DeletedAt, &c.ItemCount, &c.FieldCount); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan trashed collection: %w", err)
+		}
+		collections = append(collections, c)
+	}
+	if err := collectionRows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating trashed collections: %w", err)
+	}
+
+	return items, collections, nil
+}
+
+// RestoreItem takes an item out of the trash. It returns errNotInTrash if
+// the item isn't there, errCollectionInTrash if its collection was deleted
+// too, and errSingletonItemExists if it belongs to a singleton that has
+// since been given a new item.
+func (d *Database) RestoreItem(id int) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var collectionDeleted, singleton bool
+	var collectionID int
+	err = tx.QueryRow(`
+		SELECT c.id, c.deleted_at IS NOT NULL, c.singleton
+		FROM items i JOIN collections c ON c.id = i.collection_id
+		WHERE i.id = ? AND i.deleted_at IS NOT NULL
+	`, id).Scan(&collectionID, &collectionDeleted, &singleton)
+	if err == sql.ErrNoRows {
+		return errNotInTrash
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get trashed item: %w", err)
+	}
+	if collectionDeleted {
+		return errCollectionInTrash
+	}
+	if singleton {
+		var occupied bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM items WHERE collection_id = ? AND deleted_at IS NULL)`, collectionID).Scan(&occupied); err != nil {
+			return fmt.Errorf("failed to check singleton: %w", err)
+		}
+		if occupied {
+			return errSingletonItemExists
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE items SET deleted_at = NULL WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to restore item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit item restore: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreCollection takes a collection out of the trash, along with the items
+// it had when it was deleted
+func (d *Database) RestoreCollection(id int) error {
+	result, err := d.db.Exec(`UPDATE collections SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore collection: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errNotInTrash
+	}
+	return nil
+}
+
+// itemInTrash reports whether an item was deleted on its own
+func (d *Database) itemInTrash(id int) (bool, error) {
+	var trashed bool
+	if err := d.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM items WHERE id = ? AND deleted_at IS NOT NULL)`, id).Scan(&trashed); err != nil {
+		return false, fmt.Errorf("failed to check trash: %w", err)
+	}
+	return trashed, nil
+}
+
+// collectionInTrash reports whether a collection is in the trash
+func (d *Database) collectionInTrash(id int) (bool, error) {
+	var trashed bool
+	if err := d.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM collections WHERE id = ? AND deleted_at IS NOT NULL)`, id).Scan(&trashed); err != nil {
+		return false, fmt.Errorf("failed to check trash: %w", err)
+	}
+	return trashed, nil
+}
+
+// PurgeTrash permanently removes every item and collection that was moved to
+// the trash at or before the given time, and returns how many of each were
+// removed
+func (d *Database) PurgeTrash(before time.Time) (items, collections int, err error) {
+	beforeParam := before.UTC().Format(scheduleTimeLayout)
+
+	collectionIDs, err := d.trashedIDs(`SELECT id FROM collections WHERE deleted_at <= ?`, beforeParam)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, id := range collectionIDs {
+		if _, _, err := d.PurgeCollection(id); err != nil {
+			return items, collections, err
+		}
+		collections++
+	}
+
+	// Items of purged collections are already gone
+	itemIDs, err := d.trashedIDs(`SELECT id FROM items WHERE deleted_at <= ?`, beforeParam)
+	if err != nil {
+		return items, collections, err
+	}
+	for _, id := range itemIDs {
+		if err := d.PurgeItem(id); err != nil {
+			return items, collections, err
+		}
+		items++
+	}
+
+	return items, collections, nil
+}
+
+// trashedIDs runs a query selecting ids of trashed rows
+func (d *Database) trashedIDs(query string, args ...interface{}) ([]int, error) {
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trash: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan trash: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trash: %w", err)
+	}
+	return ids, nil
+}
+
+// runTrashPurge permanently removes trash older than the server's retention
+// period at startup and then every interval
+func (s *Server) runTrashPurge(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		items, collections, err := s.db.PurgeTrash(time.Now().Add(-s.trashRetention))
+		if err != nil {
+			log.Printf("Error purging trash: %v", err)
+		} else if items > 0 || collections > 0 {
+			log.Printf("Purged %d items and %d collections from the trash", items, collections)
+			s.audit(nil, nil, "trash.expire", "trash", nil, nil, map[string]int{"deletedItems": items, "deletedCollections": collections})
+		}
+
+		<-ticker.C
+	}
+}
+
+// trashExpiry returns when something deleted at deletedAt will be purged, or
+// nil if the trash is never purged automatically
+func (s *Server) trashExpiry(deletedAt time.Time) interface{} {
+	if s.trashRetention <= 0 {
+		return nil
+	}
+	return deletedAt.Add(s.trashRetention).UTC().Format(time.RFC3339)
+}
+
+// handleAdminTrash lists, restores and purges deleted items and collections:
+//
+//	GET    /admin-api/trash
+//	DELETE /admin-api/trash                                (empties the trash; admins only)
+//	POST   /admin-api/trash/items/{id}/restore
+//	DELETE /admin-api/trash/items/{id}                     (admins only)
+//	POST   /admin-api/trash/collections/{id}/restore
+//	DELETE /admin-api/trash/collections/{id}               (admins only)
+func (s *Server) handleAdminTrash(w http.ResponseWriter, r *http.Request) {
+	// Validate JWT token
+	username, err := s.validateJWTToken(r)
+	if err != nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := s.db.GetUserByUsername(username)
+	if err != nil || user == nil {
+		s.sendJSONError(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodDelete && user.Role != "admin" {
+		s.sendJSONError(w, "Only admins can permanently delete content", http.StatusForbidden)
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin-api/trash"), "/")
+	if path == "" {
+		s.handleAdminTrashList(w, r, user)
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || len(parts) > 3 || (parts[0] != "items" && parts[0] != "collections") {
+		s.sendJSONError(w, "Not found", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		s.sendJSONError(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	isItem := parts[0] == "items"
+
+	switch {
+	case len(parts) == 3 && parts[2] == "restore":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if isItem {
+			err = s.db.RestoreItem(id)
+		} else {
+			err = s.db.RestoreCollection(id)
+		}
+		switch {
+		case err == errNotInTrash:
+			s.sendJSONError(w, "Not found in the trash", http.StatusNotFound)
+			return
+		case err == errCollectionInTrash:
+			s.sendJSONError(w, "The item's collection is in the trash; restore the collection first", http.StatusConflict)
+			return
+		case err == errSingletonItemExists:
+			s.sendJSONError(w, "The singleton already has an item; delete it before restoring this one", http.StatusConflict)
+			return
+		case err != nil:
+			log.Printf("Error restoring %s %d: %v", parts[0], id, err)
+			s.sendJSONError(w, "Failed to restore", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if isItem {
+			item, err := s.db.GetItem(id)
+			if err != nil || item == nil {
+				log.Printf("Error getting restored item %d: %v", id, err)
+				s.sendJSONError(w, "Failed to get restored item", http.StatusInternalServerError)
+				return
+			}
+			s.audit(r, user, "item.restore", "item", id, nil, auditItem(item))
+			json.NewEncoder(w).Encode(convertItemToResponse(item))
+		} else {
+			collection, err := s.db.GetCollectionByID(id)
+			if err != nil || collection == nil {
+				log.Printf("Error getting restored collection %d: %v", id, err)
+				s.sendJSONError(w, "Failed to get restored collection", http.StatusInternalServerError)
+				return
+			}
+			s.audit(r, user, "collection.restore", "collection", id, nil, collectionResponse(collection))
+			json.NewEncoder(w).Encode(collectionResponse(collection))
+		}
+
+	case len(parts) == 2:
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var trashed bool
+		if isItem {
+			trashed, err = s.db.itemInTrash(id)
+		} else {
+			trashed, err = s.db.collectionInTrash(id)
+		}
+		if err != nil {
+			log.Printf("Error checking trash for %s %d: %v", parts[0], id, err)
+			s.sendJSONError(w, "Failed to purge", http.StatusInternalServerError)
+			return
+		}
+		if !trashed {
+			s.sendJSONError(w, "Not found in the trash", http.StatusNotFound)
+			return
+		}
+
+		response := map[string]interface{}{"message": "Permanently deleted"}
+		if isItem {
+			err = s.db.PurgeItem(id)
+		} else {
+			var itemCount, fieldCount int
+			itemCount, fieldCount, err = s.db.PurgeCollection(id)
+			response["deletedItems"] = itemCount
+			response["deletedFields"] = fieldCount
+		}
+		if err != nil {
+			log.Printf("Error purging %s %d: %v", parts[0], id, err)
+			s.sendJSONError(w, "Failed to purge", http.StatusInternalServerError)
+			return
+		}
+
+		if isItem {
+			s.audit(r, user, "item.purge", "item", id, nil, nil)
+		} else {
+			s.audit(r, user, "collection.purge", "collection", id, nil, response)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+
+	default:
+		s.sendJSONError(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// handleAdminTrashList serves GET and DELETE /admin-api/trash
+func (s *Server) handleAdminTrashList(w http.ResponseWriter, r *http.Request, user *User) {
+	switch r.Method {
+	case http.MethodGet:
+		items, collections, err := s.db.GetTrash()
+		if err != nil {
+			log.Printf("Error getting trash: %v", err)
+			s.sendJSONError(w, "Failed to get trash", http.StatusInternalServerError)
+			return
+		}
+
+		itemResponses := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			var data map[string]interface{}
+			json.Unmarshal([]byte(item.Data), &data)
+			response := map[string]interface{}{
+				"id":                item.ID,
+				"collectionId":      item.CollectionID,
+				"collectionName":    item.CollectionName,
+				"collectionSlug":    item.CollectionSlug,
+				"slug":              nil,
+				"data":              data,
+				"status":            item.Status,
+				"collectionDeleted": item.CollectionDeleted,
+				"deletedAt":         item.DeletedAt.UTC().Format(time.RFC3339),
+				"purgeAt":           s.trashExpiry(item.DeletedAt),
+			}
+			if item.Slug.Valid {
+				response["slug"] = item.Slug.String
+			}
+			itemResponses = append(itemResponses, response)
+		}
+
+		collectionResponses := make([]map[string]interface{}, 0, len(collections))
+		for _, c := range collections {
+			collectionResponses = append(collectionResponses, map[string]interface{}{
+				"id":         c.ID,
+				"name":       c.Name,
+				"slug":       c.Slug,
+				"itemCount":  c.ItemCount,
+				"fieldCount": c.FieldCount,
+				"deletedAt":  c.DeletedAt.UTC().Format(time.RFC3339),
+				"purgeAt":    s.trashExpiry(c.DeletedAt),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"items":         itemResponses,
+			"collections":   collectionResponses,
+			"retentionDays": s.trashRetention.Hours() / 24,
+		})
+
+	case http.MethodDelete:
+		items, collections, err := s.db.PurgeTrash(time.Now())
+		if err != nil {
+			log.Printf("Error emptying trash: %v", err)
+			s.sendJSONError(w, "Failed to empty trash", http.StatusInternalServerError)
+			return
+		}
+
+		s.audit(r, user, "trash.empty", "trash", nil, nil, map[string]int{"deletedItems": items, "deletedCollections": collections})
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message":            "Trash emptied",
+			"deletedItems":       items,
+			"deletedCollections": collections,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
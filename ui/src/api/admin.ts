@@ -1,3 +1,70 @@
+export type ItemLock = { userId: number; username: string; acquiredAt: string; expiresAt: string };
+
+export type ItemComment = { id: number; parentId?: number; field: string | null; body: string; createdBy: number | null; author: string | null; createdAt: string };
+
+export type ItemCommentThread = ItemComment & { resolved: boolean; resolvedBy: string | null; resolvedAt: string | null; replies: ItemComment[] };
+
+export type AuditEntry = { id: number; userId: number | null; username: string | null; action: string; targetType: string; targetId: string | null; before: any; after: any; ip: string | null; createdAt: string };
+
+type ItemRecord = { id: number; collectionId: number; slug?: string; data: Record<string, any>; status: string; version: number; publishAt?: string; unpublishAt?: string; createdAt: string; updatedAt: string; lock?: ItemLock };
+
+export type TrashedItem = { id: number; collectionId: number; collectionName: string; collectionSlug: string; slug: string | null; data: Record<string, any>; status: string; collectionDeleted: boolean; deletedAt: string; purgeAt: string | null };
+
+export type TrashedCollection = { id: number; name: string; slug: string; itemCount: number; fieldCount: number; deletedAt: string; purgeAt: string | null };
+
+export type BulkOperation = {
+  ids?: number[];
+  filter?: { collectionId: number; status?: string };
+  action: 'status' | 'delete' | 'move' | 'set';
+  status?: string;
+  comment?: string;
+  collectionId?: number;
+  field?: string;
+  value?: any;
+};
+
+export type BulkReport = {
+  action: string;
+  matched: number;
+  succeeded: number;
+  failed: number;
+  results: Array<{ id: number; ok: boolean; error?: string }>;
+  dryRun: boolean;
+  applied: boolean;
+};
+
+// The first locale is the default, whose values are the item data itself
+export type LocaleSettings = {
+  locales: string[];
+  fallbacks: Record<string, string[]>;
+};
+
+export type Workflow = {
+  states: string[];
+  initial?: string;
+  transitions: Array<{ from: string; to: string; roles?: string[] }>;
+};
+
+// Thrown when an item was saved by someone else since it was loaded
+export class ItemConflictError extends Error {
+  current: ItemRecord;
+
+  constructor(message: string, current: ItemRecord) {
+    super(message);
+    this.current = current;
+  }
+}
+
+// Thrown when a revision holds values that don't fit the collection's current fields
+export class RevisionMismatchError extends Error {
+  mismatches: { field: string; problem: string }[];
+
+  constructor(message: string, mismatches: { field: string; problem: string }[]) {
+    super(message);
+    this.mismatches = mismatches;
+  }
+}
+
 class AdminAPI {
   private baseURL = '/admin-api';
 
@@ -114,7 +181,7 @@ class AdminAPI {
   }
 
   // Collections Management
-  async getCollections(): Promise<Array<{ id: number; name: string; slug: string; description: string; createdAt: string; updatedAt: string }>> {
+  async getCollections(): Promise<Array<{ id: number; name: string; slug: string; description: string; singleton: boolean; previewUrl: string; createdAt: string; updatedAt: string }>> {
     const response = await fetch(`${this.baseURL}/collections`, {
       headers: this.getAuthHeaders(),
     });
@@ -126,7 +193,7 @@ class AdminAPI {
     return await response.json();
   }
 
-  async createCollection(collection: { name: string; slug: string; description?: string }): Promise<{ id: number; name: string; slug: string; description: string; createdAt: string; updatedAt: string }> {
+  async createCollection(collection: { name: string; slug: string; description?: string; singleton?: boolean }): Promise<{ id: number; name: string; slug: string; description: string; singleton: boolean; createdAt: string; updatedAt: string }> {
     const response = await fetch(`${this.baseURL}/collections`, {
       method: 'POST',
       headers: {
@@ -144,7 +211,7 @@ class AdminAPI {
     return await response.json();
   }
 
-  async updateCollection(id: number, collection: { name?: string; slug?: string; description?: string }): Promise<void> {
+  async updateCollection(id: number, collection: { name?: string; slug?: string; description?: string; singleton?: boolean; previewUrl?: string }): Promise<void> {
     const response = await fetch(`${this.baseURL}/collections/${id}`, {
       method: 'PUT',
       headers: {
@@ -160,8 +227,9 @@ class AdminAPI {
     }
   }
 
-  async deleteCollection(id: number): Promise<void> {
-    const response = await fetch(`${this.baseURL}/collections/${id}`, {
+  async deleteCollection(id: number, options: { dryRun?: boolean } = {}): Promise<{ deletedItems: number; deletedFields: number }> {
+    const query = options.dryRun ? '?dryRun=true' : '';
+    const response = await fetch(`${this.baseURL}/collections/${id}${query}`, {
       method: 'DELETE',
       headers: this.getAuthHeaders(),
     });
@@ -170,10 +238,30 @@ class AdminAPI {
       const error = await response.json();
       throw new Error(error.error || 'Failed to delete collection');
     }
+
+    return await response.json();
+  }
+
+  async cloneCollection(id: number, options: { name?: string; slug?: string; description?: string; includeItems?: boolean } = {}): Promise<{ id: number; name: string; slug: string; clonedFields: number; clonedItems: number }> {
+    const response = await fetch(`${this.baseURL}/collections/${id}/clone`, {
+      method: 'POST',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(options),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to duplicate collection');
+    }
+
+    return await response.json();
   }
 
   // Collection Fields Management
-  async getCollectionFields(collectionId: number): Promise<Array<{ id: number; name: string; label: string; type: string; required: boolean; placeholder: string; defaultValue: string; sortOrder: number }>> {
+  async getCollectionFields(collectionId: number): Promise<Array<{ id: number; name: string; label: string; type: string; required: boolean; indexed: boolean; localizable: boolean; placeholder: string; defaultValue: string; sortOrder: number }>> {
     const response = await fetch(`${this.baseURL}/collections/${collectionId}/fields`, {
       headers: this.getAuthHeaders(),
     });
@@ -185,7 +273,7 @@ class AdminAPI {
     return await response.json();
   }
 
-  async createCollectionField(collectionId: number, field: { name: string; label: string; type: string; required?: boolean; placeholder?: string; defaultValue?: string; sortOrder?: number }): Promise<{ id: number; name: string; label: string; type: string; required: boolean; placeholder: string; defaultValue: string; sortOrder: number }> {
+  async createCollectionField(collectionId: number, field: { name: string; label: string; type: string; required?: boolean; indexed?: boolean; localizable?: boolean; placeholder?: string; defaultValue?: string; sortOrder?: number }): Promise<{ id: number; name: string; label: string; type: string; required: boolean; indexed: boolean; localizable: boolean; placeholder: string; defaultValue: string; sortOrder: number }> {
     const response = await fetch(`${this.baseURL}/collections/${collectionId}/fields`, {
       method: 'POST',
       headers: {
@@ -203,6 +291,59 @@ class AdminAPI {
     return await response.json();
   }
 
+  async updateCollectionField(collectionId: number, fieldId: number, field: { name?: string; label?: string; type?: string; required?: boolean; indexed?: boolean; localizable?: boolean; placeholder?: string; defaultValue?: string; sortOrder?: number }, options: { dryRun?: boolean; dropInvalid?: boolean } = {}): Promise<any> {
+    const params = new URLSearchParams();
+    if (options.dryRun) params.set('dryRun', 'true');
+    if (options.dropInvalid) params.set('dropInvalid', 'true');
+    const query = params.toString() ? `?${params}` : '';
+
+    const response = await fetch(`${this.baseURL}/collections/${collectionId}/fields/${fieldId}${query}`, {
+      method: 'PUT',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(field),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to update field');
+    }
+
+    return await response.json();
+  }
+
+  async deleteCollectionField(collectionId: number, fieldId: number): Promise<void> {
+    const response = await fetch(`${this.baseURL}/collections/${collectionId}/fields/${fieldId}`, {
+      method: 'DELETE',
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to delete field');
+    }
+  }
+
+  async reorderCollectionFields(collectionId: number, fieldIds: number[]): Promise<Array<{ id: number; name: string; label: string; type: string; required: boolean; indexed: boolean; localizable: boolean; placeholder: string; defaultValue: string; sortOrder: number }>> {
+    const response = await fetch(`${this.baseURL}/collections/${collectionId}/fields/order`, {
+      method: 'PUT',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({ fieldIds }),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to reorder fields');
+    }
+
+    return await response.json();
+  }
+
   // API Keys Management
   async getAPIKeys(): Promise<Array<{ id: number; name: string; keyPrefix: string; createdAt: string; lastUsedAt?: string; isActive: boolean }>> {
     const response = await fetch(`${this.baseURL}/api-keys`, {
@@ -247,7 +388,7 @@ class AdminAPI {
   }
 
   // Items Management
-  async getCollectionItems(collectionId: number): Promise<Array<{ id: number; collectionId: number; slug?: string; data: Record<string, any>; status: string; createdAt: string; updatedAt: string }>> {
+  async getCollectionItems(collectionId: number): Promise<Array<{ id: number; collectionId: number; slug?: string; data: Record<string, any>; status: string; unresolvedComments?: number; createdAt: string; updatedAt: string }>> {
     const response = await fetch(`${this.baseURL}/items/collection/${collectionId}`, {
       headers: this.getAuthHeaders(),
     });
@@ -259,7 +400,7 @@ class AdminAPI {
     return await response.json();
   }
 
-  async getItem(itemId: number): Promise<{ id: number; collectionId: number; slug?: string; data: Record<string, any>; status: string; createdAt: string; updatedAt: string }> {
+  async getItem(itemId: number): Promise<ItemRecord> {
     const response = await fetch(`${this.baseURL}/items/${itemId}`, {
       headers: this.getAuthHeaders(),
     });
@@ -293,20 +434,28 @@ class AdminAPI {
     return await response.json();
   }
 
-  async updateItem(itemId: number, item: { slug?: string; data: Record<string, any>; status?: string }): Promise<{ id: number; collectionId: number; slug?: string; data: Record<string, any>; status: string; createdAt: string; updatedAt: string }> {
+  async updateItem(itemId: number, item: { slug?: string; data: Record<string, any>; status?: string; version: number; publishAt?: string; unpublishAt?: string }): Promise<ItemRecord> {
     const response = await fetch(`${this.baseURL}/items/${itemId}`, {
       method: 'PUT',
       headers: {
         ...this.getAuthHeaders(),
         'Content-Type': 'application/json',
+        'If-Match': `"${item.version}"`,
       },
       body: JSON.stringify({
         slug: item.slug || '',
         data: item.data,
         status: item.status || 'draft',
+        publishAt: item.publishAt || null,
+        unpublishAt: item.unpublishAt || null,
       }),
     });
 
+    if (response.status === 409) {
+      const conflict = await response.json();
+      throw new ItemConflictError(conflict.error, conflict.current);
+    }
+
     if (!response.ok) {
       const error = await response.json();
       throw new Error(error.error || 'Failed to update item');
@@ -327,6 +476,450 @@ class AdminAPI {
     }
   }
 
+  async cloneItem(itemId: number, options: { slug?: string; data?: Record<string, any> } = {}): Promise<ItemRecord> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/clone`, {
+      method: 'POST',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(options),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to duplicate item');
+    }
+
+    return await response.json();
+  }
+
+  async getItemComments(itemId: number): Promise<ItemCommentThread[]> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/comments`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      throw new Error('Failed to fetch comments');
+    }
+
+    return await response.json();
+  }
+
+  async addItemComment(itemId: number, comment: { body: string; field?: string; parentId?: number }): Promise<ItemComment> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/comments`, {
+      method: 'POST',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(comment),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to add comment');
+    }
+
+    return await response.json();
+  }
+
+  async resolveItemComment(itemId: number, commentId: number, resolved = true): Promise<void> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/comments/${commentId}/${resolved ? 'resolve' : 'reopen'}`, {
+      method: 'POST',
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to update comment');
+    }
+  }
+
+  // Acquires or renews the current user's edit lock. If someone else holds
+  // it, their lock is returned with acquired set to false.
+  async lockItem(itemId: number, force = false): Promise<{ acquired: boolean; lock: ItemLock }> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/lock${force ? '?force=true' : ''}`, {
+      method: 'POST',
+      headers: this.getAuthHeaders(),
+    });
+
+    const result = await response.json();
+    if (response.status === 409) {
+      return { acquired: false, lock: result.lock };
+    }
+    if (!response.ok) {
+      throw new Error(result.error || 'Failed to lock item');
+    }
+
+    return { acquired: true, lock: result.lock };
+  }
+
+  async unlockItem(itemId: number): Promise<void> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/lock`, {
+      method: 'DELETE',
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to unlock item');
+    }
+  }
+
+  async bulkUpdateItems(operation: BulkOperation, options: { dryRun?: boolean; skipFailed?: boolean } = {}): Promise<BulkReport> {
+    const params = new URLSearchParams();
+    if (options.dryRun) params.set('dryRun', 'true');
+    if (options.skipFailed) params.set('skipFailed', 'true');
+    const query = params.toString() ? `?${params}` : '';
+
+    const response = await fetch(`${this.baseURL}/items/bulk${query}`, {
+      method: 'POST',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(operation),
+    });
+
+    const result = await response.json();
+    // Rejected operations still carry a report of which items failed
+    if (response.status === 422) {
+      return result.report;
+    }
+    if (!response.ok) {
+      throw new Error(result.error || 'Failed to update items');
+    }
+
+    return result;
+  }
+
+  // Item Revisions
+  async getItemRevisions(itemId: number): Promise<Array<{ revision: number; itemId: number; slug: string; status: string; createdBy: number | null; author: string | null; createdAt: string }>> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/revisions`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      throw new Error('Failed to fetch revisions');
+    }
+
+    return await response.json();
+  }
+
+  async getItemRevision(itemId: number, revision: number): Promise<{ revision: number; itemId: number; slug: string; status: string; createdBy: number | null; author: string | null; createdAt: string; data: Record<string, any> }> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/revisions/${revision}`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      throw new Error('Failed to fetch revision');
+    }
+
+    return await response.json();
+  }
+
+  async diffItemRevisions(itemId: number, from: number, to: number): Promise<{ from: number; to: number; slug?: { from: string; to: string }; status?: { from: string; to: string }; fields: Array<{ field: string; change: 'added' | 'removed' | 'changed'; from: any; to: any }> }> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/revisions/diff?from=${from}&to=${to}`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to compare revisions');
+    }
+
+    return await response.json();
+  }
+
+  async restoreItemRevision(itemId: number, revision: number, version: number, dropInvalid = false): Promise<ItemRecord> {
+    const query = dropInvalid ? '?dropInvalid=true' : '';
+    const response = await fetch(`${this.baseURL}/items/${itemId}/revisions/${revision}/restore${query}`, {
+      method: 'POST',
+      headers: {
+        ...this.getAuthHeaders(),
+        'If-Match': `"${version}"`,
+      },
+    });
+
+    if (response.status === 409) {
+      const conflict = await response.json();
+      throw new ItemConflictError(conflict.error, conflict.current);
+    }
+
+    if (response.status === 422) {
+      const mismatch = await response.json();
+      throw new RevisionMismatchError(mismatch.error, mismatch.mismatches);
+    }
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to restore revision');
+    }
+
+    return await response.json();
+  }
+
+  // Trash
+  async getAuditLog(filter: { user?: string; action?: string; targetType?: string; targetId?: string; limit?: number; offset?: number } = {}): Promise<{ entries: AuditEntry[]; total: number }> {
+    const params = new URLSearchParams();
+    Object.entries(filter).forEach(([key, value]) => {
+      if (value !== undefined && value !== '') params.set(key, String(value));
+    });
+    const query = params.toString() ? `?${params}` : '';
+
+    const response = await fetch(`${this.baseURL}/audit${query}`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to fetch audit log');
+    }
+
+    return await response.json();
+  }
+
+  async getTrash(): Promise<{ items: TrashedItem[]; collections: TrashedCollection[]; retentionDays: number }> {
+    const response = await fetch(`${this.baseURL}/trash`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      throw new Error('Failed to fetch trash');
+    }
+
+    return await response.json();
+  }
+
+  async restoreFromTrash(kind: 'items' | 'collections', id: number): Promise<void> {
+    const response = await fetch(`${this.baseURL}/trash/${kind}/${id}/restore`, {
+      method: 'POST',
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to restore');
+    }
+  }
+
+  async purgeFromTrash(kind: 'items' | 'collections', id: number): Promise<void> {
+    const response = await fetch(`${this.baseURL}/trash/${kind}/${id}`, {
+      method: 'DELETE',
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to delete permanently');
+    }
+  }
+
+  async emptyTrash(): Promise<{ deletedItems: number; deletedCollections: number }> {
+    const response = await fetch(`${this.baseURL}/trash`, {
+      method: 'DELETE',
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to empty trash');
+    }
+
+    return await response.json();
+  }
+
+  // Previews
+  async createPreviewToken(scope: { itemId?: number; collectionId?: number; ttl?: number }): Promise<{ token: string; expiresAt: string; collectionId: number; itemId?: number; previewUrl: string | null }> {
+    const response = await fetch(`${this.baseURL}/preview-tokens`, {
+      method: 'POST',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(scope),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to create preview token');
+    }
+
+    return await response.json();
+  }
+
+  // Workflows
+  async getCollectionWorkflow(collectionId: number): Promise<Workflow | null> {
+    const response = await fetch(`${this.baseURL}/collections/${collectionId}/workflow`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (response.status === 404) {
+      return null;
+    }
+
+    if (!response.ok) {
+      throw new Error('Failed to fetch workflow');
+    }
+
+    return await response.json();
+  }
+
+  async setCollectionWorkflow(collectionId: number, workflow: Workflow): Promise<Workflow> {
+    const response = await fetch(`${this.baseURL}/collections/${collectionId}/workflow`, {
+      method: 'PUT',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(workflow),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to save workflow');
+    }
+
+    return await response.json();
+  }
+
+  async deleteCollectionWorkflow(collectionId: number): Promise<void> {
+    const response = await fetch(`${this.baseURL}/collections/${collectionId}/workflow`, {
+      method: 'DELETE',
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to delete workflow');
+    }
+  }
+
+  async getItemTransitions(itemId: number): Promise<{ status: string; available: string[]; history: Array<{ id: number; from: string; to: string; comment: string; createdBy: number | null; author: string | null; createdAt: string }> } | null> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/transitions`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    // The collection has no workflow
+    if (response.status === 404) {
+      return null;
+    }
+
+    if (!response.ok) {
+      throw new Error('Failed to fetch transitions');
+    }
+
+    return await response.json();
+  }
+
+  async transitionItem(itemId: number, to: string, comment?: string): Promise<ItemRecord> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/transitions`, {
+      method: 'POST',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({ to, comment: comment || '' }),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to change status');
+    }
+
+    return await response.json();
+  }
+
+  // Locales
+  async getLocales(): Promise<LocaleSettings> {
+    const response = await fetch(`${this.baseURL}/settings/locales`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      throw new Error('Failed to fetch locales');
+    }
+
+    return await response.json();
+  }
+
+  async setLocales(settings: LocaleSettings): Promise<LocaleSettings> {
+    const response = await fetch(`${this.baseURL}/settings/locales`, {
+      method: 'PUT',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(settings),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to save locales');
+    }
+
+    return await response.json();
+  }
+
+  async getItemTranslations(itemId: number): Promise<Record<string, Record<string, any>>> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/translations`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      throw new Error('Failed to fetch translations');
+    }
+
+    return await response.json();
+  }
+
+  async setItemTranslation(itemId: number, locale: string, data: Record<string, any>, version: number): Promise<{ locale: string; data: Record<string, any>; version: number }> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/translations/${encodeURIComponent(locale)}`, {
+      method: 'PUT',
+      headers: {
+        ...this.getAuthHeaders(),
+        'Content-Type': 'application/json',
+        'If-Match': `"${version}"`,
+      },
+      body: JSON.stringify({ data }),
+    });
+
+    if (response.status === 409) {
+      const conflict = await response.json();
+      throw new ItemConflictError(conflict.error, conflict.current);
+    }
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to save translation');
+    }
+
+    return await response.json();
+  }
+
+  async deleteItemTranslation(itemId: number, locale: string, version: number): Promise<{ version: number }> {
+    const response = await fetch(`${this.baseURL}/items/${itemId}/translations/${encodeURIComponent(locale)}`, {
+      method: 'DELETE',
+      headers: {
+        ...this.getAuthHeaders(),
+        'If-Match': `"${version}"`,
+      },
+    });
+
+    if (response.status === 409) {
+      const conflict = await response.json();
+      throw new ItemConflictError(conflict.error, conflict.current);
+    }
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to delete translation');
+    }
+
+    return await response.json();
+  }
+
   private getAuthHeaders(): HeadersInit {
     const token = localStorage.getItem('lodge_token');
     if (token) {
@@ -341,11 +934,14 @@ class AdminAPI {
     return !!localStorage.getItem('lodge_token');
   }
 
-  async exportCollectionCSV(collectionId: number, statusFilter?: string): Promise<Blob> {
+  async exportCollectionCSV(collectionId: number, statusFilter?: string, locale?: string): Promise<Blob> {
     const url = new URL(`${window.location.origin}${this.baseURL}/export/${collectionId}`);
     if (statusFilter) {
       url.searchParams.append('status', statusFilter);
     }
+    if (locale) {
+      url.searchParams.append('locale', locale);
+    }
 
     const response = await fetch(url, {
       headers: this.getAuthHeaders(),
@@ -359,7 +955,7 @@ class AdminAPI {
     return await response.blob();
   }
 
-  async importCollectionCSV(collectionId: number, file: File, mode: 'create_only' | 'upsert' = 'create_only'): Promise<{
+  async importCollectionCSV(collectionId: number, file: File, mode: 'create_only' | 'upsert' = 'create_only', locale?: string): Promise<{
     success: number;
     errors: number;
     skipped: number;
@@ -369,6 +965,9 @@ class AdminAPI {
     const formData = new FormData();
     formData.append('file', file);
     formData.append('mode', mode);
+    if (locale) {
+      formData.append('locale', locale);
+    }
 
     const response = await fetch(`${this.baseURL}/import/${collectionId}`, {
       method: 'POST',
@@ -383,6 +982,54 @@ class AdminAPI {
 
     return await response.json();
   }
+
+  // Assets
+  async uploadAsset(file: File, options: { private?: boolean } = {}): Promise<{ id: number; filename: string; mimeType: string; size: number; private: boolean; url: string; createdAt: string }> {
+    const formData = new FormData();
+    formData.append('file', file);
+    if (options.private) {
+      formData.append('private', 'true');
+    }
+
+    const response = await fetch(`${this.baseURL}/assets`, {
+      method: 'POST',
+      headers: this.getAuthHeaders(),
+      body: formData,
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to upload file');
+    }
+
+    return await response.json();
+  }
+
+  async getAsset(id: number): Promise<{ id: number; filename: string; mimeType: string; size: number; private: boolean; url: string; createdAt: string }> {
+    const response = await fetch(`${this.baseURL}/assets/${id}`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      throw new Error('Failed to fetch asset');
+    }
+
+    return await response.json();
+  }
+
+  async getAssetSignedURL(id: number, expiresInSeconds?: number): Promise<{ url: string; expiresAt: string }> {
+    const query = expiresInSeconds ? `?expires=${expiresInSeconds}` : '';
+    const response = await fetch(`${this.baseURL}/assets/${id}/url${query}`, {
+      headers: this.getAuthHeaders(),
+    });
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.error || 'Failed to sign asset URL');
+    }
+
+    return await response.json();
+  }
 }
 
 export const adminAPI = new AdminAPI();
\ No newline at end of file
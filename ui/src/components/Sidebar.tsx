@@ -41,6 +41,8 @@ export function Sidebar({ currentPage, onNavigate, isOpen, onClose }: SidebarPro
     { name: 'Dashboard', id: 'dashboard', icon: 'dashboard' },
     { name: 'Collections', id: 'collections', icon: 'folder' },
     { name: 'Users', id: 'users', icon: 'user' },
+    { name: 'Trash', id: 'trash', icon: 'trash' },
+    { name: 'Audit Log', id: 'audit', icon: 'audit' },
     { name: 'Settings', id: 'settings', icon: 'settings' },
   ];
 
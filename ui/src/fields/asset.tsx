@@ -0,0 +1,99 @@
+import { useState, useEffect } from 'preact/hooks';
+import { adminAPI } from '../api/admin';
+
+interface Asset {
+  id: number;
+  filename: string;
+  mimeType: string;
+  size: number;
+  private: boolean;
+  url: string;
+}
+
+interface AssetFieldProps {
+  name: string;
+  label: string;
+  value: number | null;
+  placeholder?: string;
+  required?: boolean;
+  onChange: (value: number | null) => void;
+}
+
+export function AssetField({ name, label, value, required, onChange }: AssetFieldProps) {
+  const [asset, setAsset] = useState<Asset | null>(null);
+  const [previewUrl, setPreviewUrl] = useState('');
+  const [uploading, setUploading] = useState(false);
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    if (value == null) {
+      setAsset(null);
+      return;
+    }
+    if (asset?.id === value) return;
+
+    adminAPI.getAsset(value)
+      .then(setAsset)
+      .catch(() => setAsset(null));
+  }, [value]);
+
+  // Private assets can only be viewed through a signed link
+  useEffect(() => {
+    if (!asset) {
+      setPreviewUrl('');
+    } else if (asset.private) {
+      adminAPI.getAssetSignedURL(asset.id)
+        .then(({ url }) => setPreviewUrl(url))
+        .catch(() => setPreviewUrl(''));
+    } else {
+      setPreviewUrl(asset.url);
+    }
+  }, [asset]);
+
+  const handleFileChange = async (e: Event) => {
+    const file = (e.target as HTMLInputElement).files?.[0];
+    if (!file) return;
+
+    setUploading(true);
+    setError('');
+    try {
+      const uploaded = await adminAPI.uploadAsset(file);
+      setAsset(uploaded);
+      onChange(uploaded.id);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to upload file');
+    } finally {
+      setUploading(false);
+    }
+  };
+
+  return (
+    <div className="space-y-2">
+      {asset && (
+        <div className="flex items-center gap-3">
+          {asset.mimeType.startsWith('image/') ? (
+            <img src={previewUrl} alt={asset.filename} className="h-16 w-16 object-cover border border-gray-300" />
+          ) : (
+            <a href={previewUrl} target="_blank" rel="noopener noreferrer" className="underline">
+              {asset.filename}
+            </a>
+          )}
+          <button type="button" className="btn-secondary" onClick={() => onChange(null)}>
+            Remove
+          </button>
+        </div>
+      )}
+      <input
+        type="file"
+        id={name}
+        name={name}
+        required={required && value == null}
+        disabled={uploading}
+        onChange={handleFileChange}
+        className="input-flat"
+      />
+      {uploading && <p className="text-sm">Uploading...</p>}
+      {error && <p className="text-sm text-red-600">{error}</p>}
+    </div>
+  );
+}
@@ -6,6 +6,7 @@ export { UrlField } from './url';
 export { NumberField } from './number';
 export { DateField } from './date';
 export { BooleanField } from './boolean';
+export { AssetField } from './asset';
 
 import { TextField } from './text';
 import { TextareaField } from './textarea';
@@ -15,6 +16,7 @@ import { UrlField } from './url';
 import { NumberField } from './number';
 import { DateField } from './date';
 import { BooleanField } from './boolean';
+import { AssetField } from './asset';
 
 interface Field {
   id: number;
@@ -62,6 +64,8 @@ export function FieldComponent({ field, value, onChange }: FieldComponentProps)
       return <DateField {...baseProps} value={value || ''} onChange={onChange} />;
     case 'boolean':
       return <BooleanField {...baseProps} value={value} onChange={onChange} />;
+    case 'asset':
+      return <AssetField {...baseProps} value={value ?? null} onChange={onChange} />;
     default:
       return <TextField {...baseProps} value={value || ''} onChange={onChange} />;
   }
@@ -0,0 +1,144 @@
+import { Icon } from '../components/Icon';
+import { adminAPI, AuditEntry } from '../api/admin';
+import { useState, useEffect } from 'preact/hooks';
+
+const PAGE_SIZE = 50;
+
+function describeTarget(entry: AuditEntry): string {
+  return entry.targetId ? `${entry.targetType} #${entry.targetId}` : entry.targetType;
+}
+
+export function AuditLog() {
+  const [entries, setEntries] = useState<AuditEntry[]>([]);
+  const [total, setTotal] = useState(0);
+  const [offset, setOffset] = useState(0);
+  const [filter, setFilter] = useState({ user: '', action: '' });
+  const [expanded, setExpanded] = useState<number | null>(null);
+  const [isLoading, setIsLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  const fetchEntries = async () => {
+    try {
+      setIsLoading(true);
+      const result = await adminAPI.getAuditLog({ ...filter, limit: PAGE_SIZE, offset });
+      setEntries(result.entries);
+      setTotal(result.total);
+      setError(null);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to fetch audit log');
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    fetchEntries();
+  }, [offset]);
+
+  const handleSearch = (e: Event) => {
+    e.preventDefault();
+    if (offset === 0) {
+      fetchEntries();
+    } else {
+      setOffset(0);
+    }
+  };
+
+  return (
+    <div>
+      <div className="mb-8 border-b-4 border-gray-300 pb-6">
+        <h2 className="title-flat">Audit Log</h2>
+        <p className="mt-2 text-sm font-medium text-gray-600 uppercase tracking-wide">
+          Who changed what, and when
+        </p>
+      </div>
+
+      <form onSubmit={handleSearch} className="mb-6 flex flex-wrap gap-2">
+        <input
+          type="text"
+          value={filter.user}
+          onInput={(e) => setFilter({ ...filter, user: (e.target as HTMLInputElement).value })}
+          className="input-flat w-auto"
+          placeholder="Username"
+        />
+        <input
+          type="text"
+          value={filter.action}
+          onInput={(e) => setFilter({ ...filter, action: (e.target as HTMLInputElement).value })}
+          className="input-flat w-auto"
+          placeholder="Action, e.g. item. or item.delete"
+        />
+        <button type="submit" className="btn-secondary">
+          Filter
+        </button>
+      </form>
+
+      {error && <div className="text-red-600 mb-4">{error}</div>}
+
+      {isLoading ? (
+        <div className="text-center py-12">Loading...</div>
+      ) : entries.length === 0 ? (
+        <div className="card-flat text-center py-12">
+          <Icon name="audit" className="w-16 h-16 text-gray-400 mx-auto mb-4" />
+          <h3 className="text-xl font-black text-gray-900 mb-2 uppercase">No entries</h3>
+          <p className="text-gray-600 font-medium">Administrative actions are recorded here as they happen</p>
+        </div>
+      ) : (
+        <div className="grid gap-2">
+          {entries.map(entry => (
+            <div key={entry.id} className="card-flat">
+              <button
+                type="button"
+                onClick={() => setExpanded(expanded === entry.id ? null : entry.id)}
+                className="w-full text-left flex justify-between items-center"
+              >
+                <span>
+                  <span className="font-black uppercase">{entry.action}</span>
+                  {' · '}{describeTarget(entry)}
+                  {' · '}{entry.username || 'system'}
+                </span>
+                <span className="text-xs text-gray-500 font-medium uppercase">
+                  {new Date(entry.createdAt).toLocaleString()}{entry.ip && ` · ${entry.ip}`}
+                </span>
+              </button>
+              {expanded === entry.id && (
+                <div className="mt-4 grid gap-4 md:grid-cols-2">
+                  <div>
+                    <h4 className="text-sm font-black uppercase mb-2">Before</h4>
+                    <pre className="text-xs bg-gray-100 p-2 overflow-auto">{entry.before ? JSON.stringify(entry.before, null, 2) : '—'}</pre>
+                  </div>
+                  <div>
+                    <h4 className="text-sm font-black uppercase mb-2">After</h4>
+                    <pre className="text-xs bg-gray-100 p-2 overflow-auto">{entry.after ? JSON.stringify(entry.after, null, 2) : '—'}</pre>
+                  </div>
+                </div>
+              )}
+            </div>
+          ))}
+        </div>
+      )}
+
+      {total > PAGE_SIZE && (
+        <div className="mt-6 flex justify-between items-center">
+          <button
+            onClick={() => setOffset(Math.max(0, offset - PAGE_SIZE))}
+            disabled={offset === 0}
+            className="btn-secondary disabled:opacity-50"
+          >
+            Newer
+          </button>
+          <span className="text-sm font-medium text-gray-600">
+            {offset + 1}–{Math.min(offset + PAGE_SIZE, total)} of {total}
+          </span>
+          <button
+            onClick={() => setOffset(offset + PAGE_SIZE)}
+            disabled={offset + PAGE_SIZE >= total}
+            className="btn-secondary disabled:opacity-50"
+          >
+            Older
+          </button>
+        </div>
+      )}
+    </div>
+  );
+}
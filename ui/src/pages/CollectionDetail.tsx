@@ -1,5 +1,5 @@
 import { useState, useEffect, useRef } from 'preact/hooks';
-import { adminAPI } from '../api/admin';
+import { adminAPI, BulkOperation } from '../api/admin';
 import { FieldComponent } from '../fields';
 import { Icon } from '../components/Icon';
 import { Dropdown, DropdownItem } from '../components/Dropdown';
@@ -10,6 +10,7 @@ interface Collection {
   name: string;
   slug: string;
   description: string;
+  singleton: boolean;
   createdAt: string;
   updatedAt: string;
 }
@@ -20,6 +21,7 @@ interface CollectionField {
   label: string;
   type: string;
   required: boolean;
+  localizable: boolean;
   placeholder: string;
   defaultValue: string;
   sortOrder: number;
@@ -31,6 +33,7 @@ interface Item {
   slug?: string;
   data: Record<string, any>; // Object from backend
   status: string;
+  unresolvedComments?: number;
   createdAt: string;
   updatedAt: string;
 }
@@ -49,9 +52,14 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
   const [showImportDialog, setShowImportDialog] = useState(false);
   const [importFile, setImportFile] = useState<File | null>(null);
   const [importMode, setImportMode] = useState<'create_only' | 'upsert'>('create_only');
+  const [importLocale, setImportLocale] = useState('');
+  const [locales, setLocales] = useState<string[]>([]);
   const [importResults, setImportResults] = useState<any>(null);
   const [importing, setImporting] = useState(false);
   const importDialogRef = useRef<HTMLDialogElement>(null);
+  const [selected, setSelected] = useState<number[]>([]);
+  const [bulkStatus, setBulkStatus] = useState('published');
+  const [bulkError, setBulkError] = useState<string | null>(null);
 
   useEffect(() => {
     loadCollection();
@@ -91,6 +99,9 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
       const itemsData = await adminAPI.getCollectionItems(foundCollection.id);
       setItems(itemsData);
 
+      const localeSettings = await adminAPI.getLocales();
+      setLocales(localeSettings.locales);
+
     } catch (error) {
       console.error('Failed to load collection:', error);
     } finally {
@@ -162,7 +173,7 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
   };
 
   const handleDeleteItem = async (itemId: number) => {
-    if (!confirm('Are you sure you want to delete this item?')) return;
+    if (!confirm('Move this item to the trash?')) return;
 
     try {
       await adminAPI.deleteItem(itemId);
@@ -172,6 +183,40 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
     }
   };
 
+  const handleCloneItem = async (item: Item) => {
+    try {
+      const clone = await adminAPI.cloneItem(item.id);
+      navigate(`/admin/collections/${slug}/${clone.id}/edit`);
+    } catch (error) {
+      console.error('Failed to duplicate item:', error);
+    }
+  };
+
+  const toggleSelected = (itemId: number) => {
+    setSelected(selected.includes(itemId) ? selected.filter(id => id !== itemId) : [...selected, itemId]);
+  };
+
+  const runBulk = async (operation: Omit<BulkOperation, 'ids'>) => {
+    try {
+      const report = await adminAPI.bulkUpdateItems({ ...operation, ids: selected });
+      if (!report.applied) {
+        const failures = report.results.filter(r => !r.ok).map(r => `#${r.id}: ${r.error}`);
+        setBulkError(`Nothing was changed. ${failures.join('; ')}`);
+        return;
+      }
+      setBulkError(null);
+      setSelected([]);
+      await loadCollection();
+    } catch (error) {
+      setBulkError(error instanceof Error ? error.message : 'Failed to update items');
+    }
+  };
+
+  const handleBulkDelete = () => {
+    if (!confirm(`Move ${selected.length} item(s) to the trash?`)) return;
+    runBulk({ action: 'delete' });
+  };
+
   const isFormValid = () => {
     return fields.every(field => {
       if (field.required) {
@@ -182,14 +227,15 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
     });
   };
 
-  const handleExportCSV = async () => {
+  // With a locale only localizable fields are exported, for translation
+  const handleExportCSV = async (locale?: string) => {
     if (!collection) return;
     try {
-      const blob = await adminAPI.exportCollectionCSV(collection.id);
+      const blob = await adminAPI.exportCollectionCSV(collection.id, undefined, locale);
       const url = URL.createObjectURL(blob);
       const a = document.createElement('a');
       a.href = url;
-      a.download = `${collection.slug}-export.csv`;
+      a.download = locale ? `${collection.slug}-${locale}-export.csv` : `${collection.slug}-export.csv`;
       document.body.appendChild(a);
       a.click();
       document.body.removeChild(a);
@@ -204,7 +250,7 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
     if (!collection || !importFile) return;
     setImporting(true);
     try {
-      const results = await adminAPI.importCollectionCSV(collection.id, importFile, importMode);
+      const results = await adminAPI.importCollectionCSV(collection.id, importFile, importMode, importLocale || undefined);
       setImportResults(results);
       if (results.success > 0) {
         await loadCollection(); // Reload items to show imported data
@@ -220,6 +266,7 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
   const closeImportDialog = () => {
     setShowImportDialog(false);
     setImportFile(null);
+    setImportLocale('');
     setImportResults(null);
     setImporting(false);
   };
@@ -261,12 +308,16 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
       </div>
 
       <div className="mb-6 flex justify-between items-center">
-        <button
-          onClick={handleCreateItem}
-          className="btn-primary"
-        >
-          + New Item
-        </button>
+        {collection.singleton && items.length > 0 ? (
+          <span className="text-sm font-bold text-gray-600 uppercase">Singleton · edit the item below</span>
+        ) : (
+          <button
+            onClick={handleCreateItem}
+            className="btn-primary"
+          >
+            + New Item
+          </button>
+        )}
 
         <Dropdown
           trigger="More"
@@ -276,8 +327,14 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
               id: 'export',
               label: 'Export CSV',
               icon: 'download',
-              onClick: handleExportCSV
+              onClick: () => handleExportCSV()
             },
+            ...(fields.some(field => field.localizable) ? locales.map(locale => ({
+              id: `export-${locale}`,
+              label: `Export CSV (${locale})`,
+              icon: 'download',
+              onClick: () => handleExportCSV(locale)
+            })) : []),
             {
               id: 'import',
               label: 'Import CSV',
@@ -389,6 +446,33 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
           </div>
         ) : (
           <div className="grid gap-6">
+            {selected.length > 0 && (
+              <div className="card-flat flex flex-wrap items-center gap-4">
+                <span className="font-black uppercase">{selected.length} selected</span>
+                <select
+                  value={bulkStatus}
+                  onChange={(e) => setBulkStatus((e.target as HTMLSelectElement).value)}
+                  className="input-flat w-auto"
+                >
+                  <option value="draft">DRAFT</option>
+                  <option value="published">PUBLISHED</option>
+                  <option value="archived">ARCHIVED</option>
+                </select>
+                <button onClick={() => runBulk({ action: 'status', status: bulkStatus })} className="btn-primary">
+                  Set Status
+                </button>
+                <button
+                  onClick={handleBulkDelete}
+                  className="px-4 py-2 border-4 border-red-500 text-red-500 font-bold hover:bg-red-500 hover:text-white transition-colors uppercase text-sm"
+                >
+                  Delete
+                </button>
+                <button onClick={() => { setSelected([]); setBulkError(null); }} className="btn-secondary">
+                  Clear
+                </button>
+                {bulkError && <p className="w-full text-sm text-red-600">{bulkError}</p>}
+              </div>
+            )}
             {items.map(item => {
               const itemData = item.data;
               const firstField = fields[0];
@@ -397,6 +481,13 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
               return (
                 <div key={item.id} className="card-flat">
                   <div className="flex justify-between items-start">
+                    <input
+                      type="checkbox"
+                      checked={selected.includes(item.id)}
+                      onChange={() => toggleSelected(item.id)}
+                      className="mt-2 mr-4 w-5 h-5"
+                      aria-label="Select item"
+                    />
                     <div className="flex-1">
                       <h3 className="text-xl font-black mb-2 uppercase">{title || 'Untitled'}</h3>
                       {item.slug && (
@@ -414,6 +505,11 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
                         }`}>
                           {item.status}
                         </span>
+                        {!!item.unresolvedComments && (
+                          <span className="inline-block ml-2 px-3 py-1 text-xs font-black uppercase border-2 border-blue-600 text-blue-600">
+                            {item.unresolvedComments} open comment{item.unresolvedComments === 1 ? '' : 's'}
+                          </span>
+                        )}
                       </div>
                       <div className="space-y-1 text-sm">
                         {fields.slice(1, 3).map(field => {
@@ -441,6 +537,14 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
                       >
                         Edit
                       </button>
+                      {!collection?.singleton && (
+                        <button
+                          onClick={() => handleCloneItem(item)}
+                          className="px-4 py-2 border-4 border-gray-600 text-gray-600 font-bold hover:bg-gray-600 hover:text-white transition-colors uppercase text-sm"
+                        >
+                          Duplicate
+                        </button>
+                      )}
                       <button
                         onClick={() => handleDeleteItem(item.id)}
                         className="px-4 py-2 border-4 border-red-500 text-red-500 font-bold hover:bg-red-500 hover:text-white transition-colors uppercase text-sm"
@@ -499,6 +603,25 @@ export function CollectionDetail({ slug }: CollectionDetailProps) {
               </p>
             </div>
 
+            {locales.length > 1 && fields.some(field => field.localizable) && (
+              <div>
+                <label className="label-flat">Locale</label>
+                <select
+                  value={importLocale}
+                  onChange={(e) => setImportLocale((e.target as HTMLSelectElement).value)}
+                  className="input-flat"
+                >
+                  <option value="">All fields ({locales[0]})</option>
+                  {locales.slice(1).map(locale => (
+                    <option key={locale} value={locale}>{locale} translations</option>
+                  ))}
+                </select>
+                <p className="text-xs text-gray-600 mt-1">
+                  Translations update the localizable fields of existing items, matched by _id
+                </p>
+              </div>
+            )}
+
             <div className="flex justify-end space-x-4 mt-6">
               <button
                 onClick={closeImportDialog}
@@ -7,6 +7,8 @@ interface Collection {
   name: string;
   slug: string;
   description: string;
+  singleton: boolean;
+  previewUrl: string;
   createdAt: string;
   updatedAt: string;
 }
@@ -17,11 +19,16 @@ interface CollectionField {
   label: string;
   type: string;
   required: boolean;
+  indexed: boolean;
+  localizable: boolean;
   placeholder: string;
   defaultValue: string;
   sortOrder: number;
 }
 
+// Field types too long to be indexed
+const unindexableTypes = ['textarea', 'markdown'];
+
 export function Collections() {
   const [collections, setCollections] = useState<Collection[]>([]);
   const [loading, setLoading] = useState(true);
@@ -33,13 +40,16 @@ export function Collections() {
   const [newCollection, setNewCollection] = useState({
     name: '',
     slug: '',
-    description: ''
+    description: '',
+    singleton: false
   });
   const [newField, setNewField] = useState({
     name: '',
     label: '',
     type: 'text',
     required: false,
+    indexed: false,
+    localizable: false,
     placeholder: '',
     defaultValue: ''
   });
@@ -74,7 +84,7 @@ export function Collections() {
 
     try {
       await adminAPI.createCollection(newCollection);
-      setNewCollection({ name: '', slug: '', description: '' });
+      setNewCollection({ name: '', slug: '', description: '', singleton: false });
       setShowCreateForm(false);
       await loadCollections();
     } catch (error) {
@@ -90,7 +100,9 @@ export function Collections() {
       await adminAPI.updateCollection(editingCollection.id, {
         name: editingCollection.name,
         slug: editingCollection.slug,
-        description: editingCollection.description
+        description: editingCollection.description,
+        singleton: editingCollection.singleton,
+        previewUrl: editingCollection.previewUrl
       });
       setEditingCollection(null);
       await loadCollections();
@@ -100,9 +112,10 @@ export function Collections() {
   };
 
   const handleDeleteCollection = async (id: number) => {
-    if (!confirm('Are you sure you want to delete this collection?')) return;
-
     try {
+      const { deletedItems, deletedFields } = await adminAPI.deleteCollection(id, { dryRun: true });
+      if (!confirm(`Move this collection to the trash? Its ${deletedItems} item(s) and ${deletedFields} field(s) go with it and come back if it is restored.`)) return;
+
       await adminAPI.deleteCollection(id);
       await loadCollections();
     } catch (error) {
@@ -110,6 +123,17 @@ export function Collections() {
     }
   };
 
+  const handleCloneCollection = async (collection: Collection) => {
+    const includeItems = confirm(`Copy the items of "${collection.name}" too? Choose Cancel to copy only its fields.`);
+
+    try {
+      await adminAPI.cloneCollection(collection.id, { includeItems });
+      await loadCollections();
+    } catch (error) {
+      alert(error instanceof Error ? error.message : 'Failed to duplicate collection');
+    }
+  };
+
   const handleManageFields = async (collection: Collection) => {
     setManagingFields(collection);
     await loadFields(collection.id);
@@ -122,6 +146,7 @@ export function Collections() {
     try {
       await adminAPI.createCollectionField(managingFields.id, {
         ...newField,
+        indexed: newField.indexed && !unindexableTypes.includes(newField.type),
         sortOrder: fields.length
       });
       setNewField({
@@ -129,6 +154,8 @@ export function Collections() {
         label: '',
         type: 'text',
         required: false,
+        indexed: false,
+        localizable: false,
         placeholder: '',
         defaultValue: ''
       });
@@ -142,11 +169,11 @@ export function Collections() {
   const handleDeleteField = async (fieldId: number) => {
     if (!confirm('Are you sure you want to delete this field?')) return;
 
+    if (!managingFields) return;
+
     try {
-      await adminAPI.deleteCollectionField(fieldId);
-      if (managingFields) {
-        await loadFields(managingFields.id);
-      }
+      await adminAPI.deleteCollectionField(managingFields.id, fieldId);
+      await loadFields(managingFields.id);
     } catch (error) {
       console.error('Failed to delete field:', error);
     }
@@ -228,6 +255,7 @@ export function Collections() {
                       <option value="number">Number</option>
                       <option value="date">Date</option>
                       <option value="boolean">Boolean</option>
+                      <option value="asset">Asset</option>
                     </select>
                   </div>
                   <div>
@@ -273,6 +301,42 @@ export function Collections() {
                       </label>
                     </div>
                   </div>
+                  {!unindexableTypes.includes(newField.type) && (
+                    <div className="sm:col-span-2">
+                      <div className="flex items-center">
+                        <input
+                          id="indexed"
+                          type="checkbox"
+                          checked={newField.indexed}
+                          onChange={(e) => setNewField({
+                            ...newField,
+                            indexed: (e.target as HTMLInputElement).checked
+                          })}
+                          className="h-6 w-6 border-4 border-gray-400 text-black focus:ring-0"
+                        />
+                        <label htmlFor="indexed" className="ml-3 text-sm font-bold text-gray-900 uppercase">
+                          Indexed (can be filtered and sorted by in the API)
+                        </label>
+                      </div>
+                    </div>
+                  )}
+                  <div className="sm:col-span-2">
+                    <div className="flex items-center">
+                      <input
+                        id="localizable"
+                        type="checkbox"
+                        checked={newField.localizable}
+                        onChange={(e) => setNewField({
+                          ...newField,
+                          localizable: (e.target as HTMLInputElement).checked
+                        })}
+                        className="h-6 w-6 border-4 border-gray-400 text-black focus:ring-0"
+                      />
+                      <label htmlFor="localizable" className="ml-3 text-sm font-bold text-gray-900 uppercase">
+                        Localizable (has a value per locale)
+                      </label>
+                    </div>
+                  </div>
                 </div>
                 <div className="mt-8 flex justify-end space-x-4">
                   <button
@@ -324,6 +388,8 @@ export function Collections() {
                         <span className="font-bold uppercase">Name:</span> {field.name} |{' '}
                         <span className="font-bold uppercase">Type:</span> {field.type}
                         {field.required && <span className="ml-2 text-red-600 font-bold">REQUIRED</span>}
+                        {field.indexed && <span className="ml-2 text-gray-900 font-bold">INDEXED</span>}
+                        {field.localizable && <span className="ml-2 text-gray-900 font-bold">LOCALIZABLE</span>}
                       </p>
                     </div>
                     <button
@@ -413,6 +479,21 @@ export function Collections() {
                     placeholder="Optional description of this collection"
                   />
                 </div>
+                <div className="sm:col-span-2 flex items-center">
+                  <input
+                    id="singleton"
+                    type="checkbox"
+                    checked={newCollection.singleton}
+                    onChange={(e) => setNewCollection({
+                      ...newCollection,
+                      singleton: (e.target as HTMLInputElement).checked
+                    })}
+                    className="h-6 w-6 border-4 border-gray-400 text-black focus:ring-0"
+                  />
+                  <label htmlFor="singleton" className="ml-3 text-sm font-bold text-gray-900 uppercase">
+                    Singleton (holds a single item, e.g. a homepage)
+                  </label>
+                </div>
               </div>
               <div className="mt-8 flex justify-end space-x-4">
                 <button
@@ -475,6 +556,34 @@ export function Collections() {
                     className="input-flat"
                   />
                 </div>
+                <div className="sm:col-span-2 flex items-center">
+                  <input
+                    id="edit-singleton"
+                    type="checkbox"
+                    checked={editingCollection.singleton}
+                    onChange={(e) => setEditingCollection({
+                      ...editingCollection,
+                      singleton: (e.target as HTMLInputElement).checked
+                    })}
+                    className="h-6 w-6 border-4 border-gray-400 text-black focus:ring-0"
+                  />
+                  <label htmlFor="edit-singleton" className="ml-3 text-sm font-bold text-gray-900 uppercase">
+                    Singleton (holds a single item, e.g. a homepage)
+                  </label>
+                </div>
+                <div className="sm:col-span-2">
+                  <label className="label-flat">Preview URL (Optional)</label>
+                  <input
+                    type="text"
+                    value={editingCollection.previewUrl}
+                    onInput={(e) => setEditingCollection({
+                      ...editingCollection,
+                      previewUrl: (e.target as HTMLInputElement).value
+                    })}
+                    className="input-flat"
+                    placeholder="https://example.com/api/preview?slug={slug}&token={token}"
+                  />
+                </div>
               </div>
               <div className="mt-8 flex justify-end space-x-4">
                 <button
@@ -517,6 +626,7 @@ export function Collections() {
                 <h3 className="text-xl font-black mb-2 uppercase">{collection.name}</h3>
                 <p className="text-sm text-gray-600 mb-4 font-medium">
                   <span className="font-bold">SLUG:</span> {collection.slug}
+                  {collection.singleton && <span className="ml-2 font-bold uppercase">· Singleton</span>}
                 </p>
                 {collection.description && (
                   <p className="text-gray-700 mb-4">{collection.description}</p>
@@ -534,6 +644,12 @@ export function Collections() {
                   >
                     Edit
                   </button>
+                  <button
+                    onClick={() => handleCloneCollection(collection)}
+                    className="flex-1 px-4 py-2 border-4 border-gray-600 text-gray-600 font-bold hover:bg-gray-600 hover:text-white transition-colors uppercase text-sm"
+                  >
+                    Copy
+                  </button>
                   <a
                     href={`/admin/collections/${collection.slug}`}
                     className="flex-1 px-4 py-2 border-4 border-green-600 text-green-600 font-bold hover:bg-green-600 hover:text-white transition-colors uppercase text-sm text-center"
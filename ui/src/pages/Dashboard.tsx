@@ -10,12 +10,16 @@ const CollectionDetail = lazy(() => import('./CollectionDetail').then(m => ({ de
 const ItemEdit = lazy(() => import('./ItemEdit').then(m => ({ default: m.ItemEdit })));
 const Users = lazy(() => import('./Users').then(m => ({ default: m.Users })));
 const Settings = lazy(() => import('./Settings').then(m => ({ default: m.Settings })));
+const Trash = lazy(() => import('./Trash').then(m => ({ default: m.Trash })));
+const AuditLog = lazy(() => import('./AuditLog').then(m => ({ default: m.AuditLog })));
 import { Router, navigate, useCurrentPath } from '../router/Router';
 
 function getPageFromPath(path: string): string {
   if (path === '/admin/collections') return 'collections';
   if (path === '/admin/users') return 'users';
   if (path === '/admin/settings') return 'settings';
+  if (path === '/admin/trash') return 'trash';
+  if (path === '/admin/audit') return 'audit';
   return 'dashboard';
 }
 
@@ -93,6 +97,8 @@ export function Dashboard() {
       <ItemEdit collectionSlug={params?.slug || ''} itemId={params?.id || ''} /> },
     { pattern: '/admin/users', component: () => <Users /> },
     { pattern: '/admin/settings', component: () => <Settings /> },
+    { pattern: '/admin/trash', component: () => <Trash /> },
+    { pattern: '/admin/audit', component: () => <AuditLog /> },
   ];
 
   return (
@@ -1,5 +1,5 @@
 import { useState, useEffect } from 'preact/hooks';
-import { adminAPI } from '../api/admin';
+import { adminAPI, ItemConflictError, RevisionMismatchError, ItemLock, ItemCommentThread } from '../api/admin';
 import { FieldComponent } from '../fields';
 import { navigate } from '../router/Router';
 
@@ -8,6 +8,7 @@ interface Collection {
   name: string;
   slug: string;
   description: string;
+  previewUrl: string;
 }
 
 interface CollectionField {
@@ -16,6 +17,7 @@ interface CollectionField {
   label: string;
   type: string;
   required: boolean;
+  localizable: boolean;
   placeholder: string;
   defaultValue: string;
   sortOrder: number;
@@ -27,8 +29,22 @@ interface Item {
   slug?: string;
   data: Record<string, any>;
   status: string;
+  version: number;
+  publishAt?: string;
+  unpublishAt?: string;
 }
 
+// Converts between RFC 3339 timestamps and datetime-local input values
+const toLocalInput = (value?: string) => {
+  if (!value) return '';
+  const date = new Date(value);
+  return new Date(date.getTime() - date.getTimezoneOffset() * 60000).toISOString().slice(0, 16);
+};
+const fromLocalInput = (value: string) => value ? new Date(value).toISOString() : undefined;
+
+// How often the edit lock is renewed; the server expires it after two minutes
+const LOCK_HEARTBEAT_MS = 30000;
+
 interface ItemEditProps {
   collectionSlug: string;
   itemId: string;
@@ -41,11 +57,99 @@ export function ItemEdit({ collectionSlug, itemId }: ItemEditProps) {
   const [formData, setFormData] = useState<Record<string, any>>({});
   const [loading, setLoading] = useState(true);
   const [saving, setSaving] = useState(false);
+  const [schedule, setSchedule] = useState({ publishAt: '', unpublishAt: '' });
+  const [workflow, setWorkflow] = useState<{ status: string; available: string[]; history: Array<{ id: number; from: string; to: string; comment: string; author: string | null; createdAt: string }> } | null>(null);
+  const [revisions, setRevisions] = useState<Array<{ revision: number; status: string; author: string | null; createdAt: string }>>([]);
+  const [revisionChanges, setRevisionChanges] = useState<Record<number, string[]>>({});
+  const [lockHolder, setLockHolder] = useState<ItemLock | null>(null);
+  const [comments, setComments] = useState<ItemCommentThread[]>([]);
+  const [newComment, setNewComment] = useState({ body: '', field: '' });
+  const [replies, setReplies] = useState<Record<number, string>>({});
+  const [showResolved, setShowResolved] = useState(false);
+  const [isAdmin, setIsAdmin] = useState(false);
+  const [locales, setLocales] = useState<string[]>([]);
+  const [translations, setTranslations] = useState<Record<string, Record<string, any>>>({});
+  const [activeLocale, setActiveLocale] = useState('');
+  const [savingTranslation, setSavingTranslation] = useState(false);
 
   useEffect(() => {
     loadData();
   }, [collectionSlug, itemId]);
 
+  useEffect(() => {
+    adminAPI.getCurrentUser().then(user => setIsAdmin(user?.role === 'admin')).catch(() => {});
+  }, []);
+
+  // Hold the edit lock while the item is open. If someone else has it, keep
+  // checking so the warning goes away once they leave.
+  useEffect(() => {
+    const id = parseInt(itemId);
+    let held = false;
+
+    const heartbeat = async () => {
+      try {
+        const { acquired, lock } = await adminAPI.lockItem(id);
+        held = acquired;
+        setLockHolder(acquired ? null : lock);
+      } catch (error) {
+        console.error('Failed to lock item:', error);
+      }
+    };
+
+    heartbeat();
+    const timer = setInterval(heartbeat, LOCK_HEARTBEAT_MS);
+    return () => {
+      clearInterval(timer);
+      if (held) {
+        adminAPI.unlockItem(id).catch(() => {});
+      }
+    };
+  }, [itemId]);
+
+  const handleAddComment = async (parentId?: number) => {
+    if (!item) return;
+    const body = parentId ? replies[parentId] : newComment.body;
+    if (!body?.trim()) return;
+
+    try {
+      await adminAPI.addItemComment(item.id, parentId
+        ? { body, parentId }
+        : { body, field: newComment.field || undefined });
+      if (parentId) {
+        setReplies({ ...replies, [parentId]: '' });
+      } else {
+        setNewComment({ body: '', field: '' });
+      }
+      setComments(await adminAPI.getItemComments(item.id));
+    } catch (error) {
+      alert(error instanceof Error ? error.message : 'Failed to add comment');
+    }
+  };
+
+  const handleResolveComment = async (threadId: number, resolved: boolean) => {
+    if (!item) return;
+
+    try {
+      await adminAPI.resolveItemComment(item.id, threadId, resolved);
+      setComments(await adminAPI.getItemComments(item.id));
+    } catch (error) {
+      alert(error instanceof Error ? error.message : 'Failed to update comment');
+    }
+  };
+
+  const fieldLabel = (name: string | null) => fields.find(f => f.name === name)?.label || name;
+
+  const handleTakeOver = async () => {
+    if (!lockHolder || !confirm(`Take over editing from ${lockHolder.username}? Their unsaved changes may be lost.`)) return;
+
+    try {
+      await adminAPI.lockItem(parseInt(itemId), true);
+      setLockHolder(null);
+    } catch (error) {
+      alert(error instanceof Error ? error.message : 'Failed to take over the edit lock');
+    }
+  };
+
   const loadData = async () => {
     try {
       // Get collection
@@ -74,6 +178,26 @@ export function ItemEdit({ collectionSlug, itemId }: ItemEditProps) {
         slug: itemData.slug || '',
         status: itemData.status || 'draft'
       });
+      setSchedule({
+        publishAt: toLocalInput(itemData.publishAt),
+        unpublishAt: toLocalInput(itemData.unpublishAt)
+      });
+
+      // Load workflow state, if the collection has a workflow
+      setWorkflow(await adminAPI.getItemTransitions(itemData.id));
+
+      // Load revision history
+      setRevisions(await adminAPI.getItemRevisions(itemData.id));
+      setRevisionChanges({});
+
+      // Load comment threads
+      setComments(await adminAPI.getItemComments(itemData.id));
+
+      // Load translations. The default locale is the item data above.
+      const localeSettings = await adminAPI.getLocales();
+      setLocales(localeSettings.locales);
+      setActiveLocale(localeSettings.locales[1] || '');
+      setTranslations(await adminAPI.getItemTranslations(itemData.id));
 
     } catch (error) {
       console.error('Failed to load data:', error);
@@ -94,18 +218,115 @@ export function ItemEdit({ collectionSlug, itemId }: ItemEditProps) {
       await adminAPI.updateItem(item.id, {
         slug: slug,
         data: fieldData,
-        status: status || 'draft'
+        status: status || 'draft',
+        version: item.version,
+        publishAt: fromLocalInput(schedule.publishAt),
+        unpublishAt: fromLocalInput(schedule.unpublishAt)
       });
 
       // Navigate back to collection
       navigate(`/admin/collections/${collectionSlug}`);
     } catch (error) {
+      if (error instanceof ItemConflictError) {
+        if (confirm('Someone else saved this item while you were editing. Load their version? Your unsaved changes will be lost. Choose Cancel to keep your changes and overwrite theirs on the next save.')) {
+          await loadData();
+        } else {
+          setItem(error.current);
+        }
+        return;
+      }
       console.error('Failed to save item:', error);
     } finally {
       setSaving(false);
     }
   };
 
+  const handleSaveTranslation = async () => {
+    if (!item || !activeLocale) return;
+
+    setSavingTranslation(true);
+    try {
+      const saved = await adminAPI.setItemTranslation(item.id, activeLocale, translations[activeLocale] || {}, item.version);
+      setTranslations({ ...translations, [activeLocale]: saved.data });
+      setItem({ ...item, version: saved.version });
+    } catch (error) {
+      if (error instanceof ItemConflictError) {
+        alert('Someone else saved this item since it was loaded. Reload the page to get their changes before saving this translation.');
+        return;
+      }
+      console.error('Failed to save translation:', error);
+      alert(error instanceof Error ? error.message : 'Failed to save translation');
+    } finally {
+      setSavingTranslation(false);
+    }
+  };
+
+  const handleTransition = async (to: string) => {
+    if (!item) return;
+
+    const comment = prompt(`Move to ${to.replace(/_/g, ' ')}. Comment (optional):`);
+    if (comment === null) return;
+
+    try {
+      await adminAPI.transitionItem(item.id, to, comment);
+      await loadData();
+    } catch (error) {
+      console.error('Failed to change status:', error);
+      alert(error instanceof Error ? error.message : 'Failed to change status');
+    }
+  };
+
+  const handlePreview = async () => {
+    if (!item) return;
+
+    try {
+      const { previewUrl } = await adminAPI.createPreviewToken({ itemId: item.id });
+      if (previewUrl) {
+        window.open(previewUrl, '_blank');
+      }
+    } catch (error) {
+      console.error('Failed to create preview:', error);
+    }
+  };
+
+  const handleShowChanges = async (revision: number) => {
+    if (!item || revision <= 1) return;
+
+    try {
+      const diff = await adminAPI.diffItemRevisions(item.id, revision - 1, revision);
+      const changes = diff.fields.map(f => `${f.field} ${f.change}`);
+      if (diff.slug) changes.push('slug changed');
+      if (diff.status) changes.push(`status ${diff.status.from} → ${diff.status.to}`);
+      setRevisionChanges({ ...revisionChanges, [revision]: changes.length ? changes : ['no changes'] });
+    } catch (error) {
+      console.error('Failed to compare revisions:', error);
+    }
+  };
+
+  const handleRestore = async (revision: number, dropInvalid = false) => {
+    if (!item) return;
+    if (!dropInvalid && !confirm(`Restore revision ${revision}? The current content will be kept in the history.`)) return;
+
+    try {
+      await adminAPI.restoreItemRevision(item.id, revision, item.version, dropInvalid);
+      await loadData();
+    } catch (error) {
+      if (error instanceof RevisionMismatchError) {
+        const details = error.mismatches.map(m => `- ${m.field}: ${m.problem}`).join('\n');
+        if (confirm(`Some values in revision ${revision} don't fit the current fields:\n${details}\n\nRestore the revision without them?`)) {
+          await handleRestore(revision, true);
+        }
+        return;
+      }
+      if (error instanceof ItemConflictError) {
+        alert('Someone else saved this item since it was loaded. It has been reloaded; restore the revision again if you still want it.');
+        await loadData();
+        return;
+      }
+      console.error('Failed to restore revision:', error);
+    }
+  };
+
   const isFormValid = () => {
     return fields.every(field => {
       if (field.required) {
@@ -152,6 +373,20 @@ export function ItemEdit({ collectionSlug, itemId }: ItemEditProps) {
         </h2>
       </div>
 
+      {lockHolder && (
+        <div className="mb-6 p-4 border-4 border-yellow-500 bg-yellow-50 flex justify-between items-center">
+          <p className="font-bold">
+            Being edited by {lockHolder.username} since {new Date(lockHolder.acquiredAt).toLocaleTimeString()}.
+            Changes saved by both of you may conflict.
+          </p>
+          {isAdmin && (
+            <button type="button" onClick={handleTakeOver} className="btn-secondary ml-4">
+              Take Over
+            </button>
+          )}
+        </div>
+      )}
+
       <div className="card-flat">
         <form onSubmit={handleSubmit}>
           <div className="space-y-6">
@@ -193,18 +428,67 @@ export function ItemEdit({ collectionSlug, itemId }: ItemEditProps) {
             {/* Status field */}
             <div>
               <label className="label-flat">Status</label>
-              <select
-                value={formData.status || 'draft'}
-                onChange={(e) => setFormData({
-                  ...formData,
-                  status: (e.target as HTMLSelectElement).value
-                })}
-                className="input-flat"
-              >
-                <option value="draft">DRAFT</option>
-                <option value="published">PUBLISHED</option>
-                <option value="archived">ARCHIVED</option>
-              </select>
+              {workflow ? (
+                <div className="flex items-center space-x-2">
+                  <span className="font-bold uppercase mr-2">{workflow.status.replace(/_/g, ' ')}</span>
+                  {workflow.available.map(state => (
+                    <button key={state} type="button" onClick={() => handleTransition(state)} className="btn-secondary text-sm">
+                      → {state.replace(/_/g, ' ')}
+                    </button>
+                  ))}
+                </div>
+              ) : (
+                <select
+                  value={formData.status || 'draft'}
+                  onChange={(e) => setFormData({
+                    ...formData,
+                    status: (e.target as HTMLSelectElement).value
+                  })}
+                  className="input-flat"
+                >
+                  <option value="draft">DRAFT</option>
+                  <option value="published">PUBLISHED</option>
+                  <option value="archived">ARCHIVED</option>
+                </select>
+              )}
+              {workflow && workflow.history.length > 0 && (
+                <ul className="mt-2 text-sm text-gray-600">
+                  {workflow.history.map(t => (
+                    <li key={t.id}>
+                      {new Date(t.createdAt).toLocaleString()} · {t.author || 'scheduler'}: {t.from.replace(/_/g, ' ')} → {t.to.replace(/_/g, ' ')}
+                      {t.comment && <span> — {t.comment}</span>}
+                    </li>
+                  ))}
+                </ul>
+              )}
+            </div>
+
+            {/* Schedule */}
+            <div className="grid grid-cols-1 gap-6 sm:grid-cols-2">
+              <div>
+                <label className="label-flat">Publish At (Optional)</label>
+                <input
+                  type="datetime-local"
+                  value={schedule.publishAt}
+                  onInput={(e) => setSchedule({
+                    ...schedule,
+                    publishAt: (e.target as HTMLInputElement).value
+                  })}
+                  className="input-flat"
+                />
+              </div>
+              <div>
+                <label className="label-flat">Unpublish At (Optional)</label>
+                <input
+                  type="datetime-local"
+                  value={schedule.unpublishAt}
+                  onInput={(e) => setSchedule({
+                    ...schedule,
+                    unpublishAt: (e.target as HTMLInputElement).value
+                  })}
+                  className="input-flat"
+                />
+              </div>
             </div>
           </div>
 
@@ -215,6 +499,11 @@ export function ItemEdit({ collectionSlug, itemId }: ItemEditProps) {
             >
               Cancel
             </a>
+            {collection?.previewUrl && (
+              <button type="button" onClick={handlePreview} className="btn-secondary">
+                Preview
+              </button>
+            )}
             <button
               type="submit"
               className="btn-primary"
@@ -226,6 +515,196 @@ export function ItemEdit({ collectionSlug, itemId }: ItemEditProps) {
           </div>
         </form>
       </div>
+
+      {locales.length > 1 && fields.some(field => field.localizable) && (
+        <div className="card-flat mt-8">
+          <div className="flex justify-between items-center mb-6">
+            <h3 className="text-xl font-black text-gray-900 uppercase tracking-tight">
+              Translations
+            </h3>
+            <div className="flex space-x-2">
+              {locales.slice(1).map(locale => (
+                <button
+                  key={locale}
+                  type="button"
+                  onClick={() => setActiveLocale(locale)}
+                  className={locale === activeLocale ? 'btn-primary text-sm' : 'btn-secondary text-sm'}
+                >
+                  {locale}
+                </button>
+              ))}
+            </div>
+          </div>
+          <p className="mb-6 text-sm text-gray-600">
+            Empty fields fall back to the locale's fallbacks, then to {locales[0]}.
+          </p>
+          <div className="space-y-6">
+            {fields.filter(field => field.localizable).map(field => (
+              <div key={field.id} className="field-wrapper">
+                <label className="label-flat">{field.label}</label>
+                <div className="field-container">
+                  <FieldComponent
+                    field={{ ...field, placeholder: typeof formData[field.name] === 'string' ? formData[field.name] : field.placeholder }}
+                    value={(translations[activeLocale] || {})[field.name]}
+                    onChange={(value: any) => setTranslations({
+                      ...translations,
+                      [activeLocale]: { ...(translations[activeLocale] || {}), [field.name]: value }
+                    })}
+                  />
+                </div>
+              </div>
+            ))}
+          </div>
+          <div className="mt-8 flex justify-end">
+            <button
+              type="button"
+              onClick={handleSaveTranslation}
+              className="btn-primary"
+              disabled={savingTranslation}
+              style={{ opacity: savingTranslation ? 0.5 : 1 }}
+            >
+              {savingTranslation ? 'Saving...' : `Save ${activeLocale} Translation`}
+            </button>
+          </div>
+        </div>
+      )}
+
+      <div className="card-flat mt-8">
+        <div className="flex justify-between items-center mb-6">
+          <h3 className="text-xl font-black text-gray-900 uppercase tracking-tight">
+            Comments
+          </h3>
+          {comments.some(t => t.resolved) && (
+            <label className="text-sm font-bold uppercase">
+              <input
+                type="checkbox"
+                checked={showResolved}
+                onChange={() => setShowResolved(!showResolved)}
+                className="mr-2"
+              />
+              Show resolved
+            </label>
+          )}
+        </div>
+        <ul className="space-y-6">
+          {comments.filter(t => showResolved || !t.resolved).map(thread => (
+            <li key={thread.id} className={`border-b-2 border-gray-200 pb-4 ${thread.resolved ? 'opacity-60' : ''}`}>
+              <div className="flex justify-between items-start">
+                <div className="text-sm font-medium text-gray-700">
+                  <span className="font-bold">{thread.author || 'unknown'}</span>
+                  {' · '}{new Date(thread.createdAt).toLocaleString()}
+                  {thread.field && <span className="ml-2 font-bold uppercase">on {fieldLabel(thread.field)}</span>}
+                  {thread.resolved && (
+                    <span className="ml-2 font-bold uppercase">(resolved by {thread.resolvedBy || 'unknown'})</span>
+                  )}
+                </div>
+                <button
+                  type="button"
+                  onClick={() => handleResolveComment(thread.id, !thread.resolved)}
+                  className="btn-secondary text-sm"
+                >
+                  {thread.resolved ? 'Reopen' : 'Resolve'}
+                </button>
+              </div>
+              <p className="mt-2 whitespace-pre-wrap">{thread.body}</p>
+              {thread.replies.length > 0 && (
+                <ul className="mt-3 ml-6 space-y-3 border-l-4 border-gray-200 pl-4">
+                  {thread.replies.map(reply => (
+                    <li key={reply.id}>
+                      <div className="text-sm font-medium text-gray-700">
+                        <span className="font-bold">{reply.author || 'unknown'}</span>
+                        {' · '}{new Date(reply.createdAt).toLocaleString()}
+                      </div>
+                      <p className="mt-1 whitespace-pre-wrap">{reply.body}</p>
+                    </li>
+                  ))}
+                </ul>
+              )}
+              {!thread.resolved && (
+                <div className="mt-3 ml-6 flex space-x-2">
+                  <input
+                    type="text"
+                    value={replies[thread.id] || ''}
+                    onInput={(e) => setReplies({ ...replies, [thread.id]: (e.target as HTMLInputElement).value })}
+                    className="input-flat flex-1"
+                    placeholder="Reply"
+                  />
+                  <button type="button" onClick={() => handleAddComment(thread.id)} className="btn-secondary text-sm">
+                    Reply
+                  </button>
+                </div>
+              )}
+            </li>
+          ))}
+        </ul>
+        <div className="mt-6 space-y-3">
+          <textarea
+            value={newComment.body}
+            onInput={(e) => setNewComment({ ...newComment, body: (e.target as HTMLTextAreaElement).value })}
+            className="input-flat"
+            rows={3}
+            placeholder="Add a comment"
+          />
+          <div className="flex justify-end space-x-2">
+            <select
+              value={newComment.field}
+              onChange={(e) => setNewComment({ ...newComment, field: (e.target as HTMLSelectElement).value })}
+              className="input-flat w-auto"
+            >
+              <option value="">Whole item</option>
+              {fields.map(field => (
+                <option key={field.id} value={field.name}>{field.label}</option>
+              ))}
+            </select>
+            <button
+              type="button"
+              onClick={() => handleAddComment()}
+              className="btn-primary"
+              disabled={!newComment.body.trim()}
+            >
+              Comment
+            </button>
+          </div>
+        </div>
+      </div>
+
+      {revisions.length > 0 && (
+        <div className="card-flat mt-8">
+          <h3 className="text-xl font-black text-gray-900 mb-6 uppercase tracking-tight">
+            History
+          </h3>
+          <ul className="space-y-4">
+            {revisions.map((rev, index) => (
+              <li key={rev.revision} className="border-b-2 border-gray-200 pb-4">
+                <div className="flex justify-between items-center">
+                  <div className="text-sm font-medium text-gray-700">
+                    <span className="font-bold">#{rev.revision}</span>
+                    {' · '}{new Date(rev.createdAt).toLocaleString()}
+                    {' · '}{rev.author || 'unknown'}
+                    {' · '}<span className="uppercase">{rev.status}</span>
+                    {index === 0 && <span className="ml-2 font-bold uppercase">(current)</span>}
+                  </div>
+                  <div className="flex space-x-2">
+                    {rev.revision > 1 && !revisionChanges[rev.revision] && (
+                      <button type="button" onClick={() => handleShowChanges(rev.revision)} className="btn-secondary text-sm">
+                        Changes
+                      </button>
+                    )}
+                    {index > 0 && (
+                      <button type="button" onClick={() => handleRestore(rev.revision)} className="btn-secondary text-sm">
+                        Restore
+                      </button>
+                    )}
+                  </div>
+                </div>
+                {revisionChanges[rev.revision] && (
+                  <p className="mt-2 text-sm text-gray-600">{revisionChanges[rev.revision].join(', ')}</p>
+                )}
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
     </div>
   );
 }
@@ -18,11 +18,50 @@ export function Settings() {
   const [showCreateForm, setShowCreateForm] = useState(false);
   const [createdKey, setCreatedKey] = useState<string | null>(null);
   const [copyState, setCopyState] = useState<'idle' | 'copied'>('idle');
+  const [localesInput, setLocalesInput] = useState('');
+  const [fallbacksInput, setFallbacksInput] = useState('');
+  const [localesSaved, setLocalesSaved] = useState(false);
 
   useEffect(() => {
     loadAPIKeys();
+    loadLocales();
   }, []);
 
+  const loadLocales = async () => {
+    try {
+      const settings = await adminAPI.getLocales();
+      setLocalesInput(settings.locales.join(', '));
+      setFallbacksInput(Object.entries(settings.fallbacks)
+        .map(([locale, fallbacks]) => `${locale}: ${fallbacks.join(', ')}`)
+        .join('\n'));
+    } catch (error) {
+      console.error('Failed to load locales:', error);
+    }
+  };
+
+  // Fallbacks are written one locale per line, e.g. "de-AT: de"
+  const handleSaveLocales = async (e: Event) => {
+    e.preventDefault();
+    const split = (value: string) => value.split(',').map(code => code.trim()).filter(code => code);
+    const fallbacks: Record<string, string[]> = {};
+    fallbacksInput.split('\n').forEach(line => {
+      const [locale, rest] = line.split(':');
+      if (locale && locale.trim() && rest) {
+        fallbacks[locale.trim()] = split(rest);
+      }
+    });
+
+    try {
+      await adminAPI.setLocales({ locales: split(localesInput), fallbacks });
+      setLocalesSaved(true);
+      setTimeout(() => setLocalesSaved(false), 3000);
+      await loadLocales();
+    } catch (error) {
+      console.error('Failed to save locales:', error);
+      alert('Failed to save locales: ' + (error as Error).message);
+    }
+  };
+
   const loadAPIKeys = async () => {
     try {
       const keys = await adminAPI.getAPIKeys();
@@ -239,6 +278,52 @@ export function Settings() {
           )}
         </div>
 
+        {/* Locales Section */}
+        <div className="card-flat">
+          <div className="mb-6">
+            <h3 className="text-xl font-black text-gray-900 uppercase">
+              Locales
+            </h3>
+            <p className="mt-1 text-sm text-gray-600 font-medium">
+              Languages your content is published in. Localizable fields have a value per locale
+            </p>
+          </div>
+          <form onSubmit={handleSaveLocales} className="space-y-4">
+            <div>
+              <label className="label-flat">Locales</label>
+              <input
+                type="text"
+                value={localesInput}
+                onInput={(e) => setLocalesInput((e.target as HTMLInputElement).value)}
+                className="input-flat"
+                placeholder="e.g., en, de, ja"
+              />
+              <p className="mt-2 text-sm text-gray-600">
+                Comma-separated locale codes. The first is the default, which items are edited in
+              </p>
+            </div>
+            <div>
+              <label className="label-flat">Fallbacks</label>
+              <textarea
+                value={fallbacksInput}
+                onInput={(e) => setFallbacksInput((e.target as HTMLTextAreaElement).value)}
+                className="input-flat font-mono"
+                rows={3}
+                placeholder="de-AT: de"
+              />
+              <p className="mt-2 text-sm text-gray-600">
+                One locale per line, followed by the locales to try when it has no value. The default locale is always tried last
+              </p>
+            </div>
+            <div className="flex items-center space-x-4">
+              <button type="submit" className="btn-primary">
+                Save Locales
+              </button>
+              {localesSaved && <span className="text-sm font-bold text-green-700 uppercase">Saved</span>}
+            </div>
+          </form>
+        </div>
+
         {/* Usage Instructions */}
         <div className="card-flat">
           <h3 className="text-xl font-black text-gray-900 mb-4 uppercase">API Usage</h3>
@@ -0,0 +1,191 @@
+import { Icon } from '../components/Icon';
+import { adminAPI, TrashedItem, TrashedCollection } from '../api/admin';
+import { useState, useEffect } from 'preact/hooks';
+
+// Trashed items are shown without their collection's fields, so use the
+// first text value as a title
+function itemTitle(item: TrashedItem): string {
+  const text = Object.values(item.data || {}).find(value => typeof value === 'string' && value !== '');
+  return (text as string) || item.slug || `Item #${item.id}`;
+}
+
+export function Trash() {
+  const [items, setItems] = useState<TrashedItem[]>([]);
+  const [collections, setCollections] = useState<TrashedCollection[]>([]);
+  const [retentionDays, setRetentionDays] = useState(0);
+  const [isAdmin, setIsAdmin] = useState(false);
+  const [isLoading, setIsLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  const fetchTrash = async () => {
+    try {
+      setIsLoading(true);
+      const trash = await adminAPI.getTrash();
+      setItems(trash.items);
+      setCollections(trash.collections);
+      setRetentionDays(trash.retentionDays);
+      setError(null);
+    } catch (err) {
+      setError('Failed to fetch trash. Please try again.');
+      console.error(err);
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    fetchTrash();
+    adminAPI.getCurrentUser().then(user => setIsAdmin(user?.role === 'admin')).catch(() => {});
+  }, []);
+
+  const handleRestore = async (kind: 'items' | 'collections', id: number) => {
+    try {
+      await adminAPI.restoreFromTrash(kind, id);
+      fetchTrash();
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to restore');
+    }
+  };
+
+  const handlePurge = async (kind: 'items' | 'collections', id: number) => {
+    const what = kind === 'items' ? 'this item' : 'this collection and all of its items';
+    if (!confirm(`Permanently delete ${what}? This action cannot be undone.`)) return;
+
+    try {
+      await adminAPI.purgeFromTrash(kind, id);
+      fetchTrash();
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to delete permanently');
+    }
+  };
+
+  const handleEmpty = async () => {
+    if (!confirm('Permanently delete everything in the trash? This action cannot be undone.')) return;
+
+    try {
+      await adminAPI.emptyTrash();
+      fetchTrash();
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to empty trash');
+    }
+  };
+
+  const purgeNote = (purgeAt: string | null) =>
+    purgeAt ? ` · Deleted permanently ${new Date(purgeAt).toLocaleDateString()}` : '';
+
+  const isEmpty = items.length === 0 && collections.length === 0;
+
+  return (
+    <div>
+      <div className="mb-8 border-b-4 border-gray-300 pb-6">
+        <h2 className="title-flat">Trash</h2>
+        <p className="mt-2 text-sm font-medium text-gray-600 uppercase tracking-wide">
+          {retentionDays > 0
+            ? `Deleted content is kept for ${retentionDays} days`
+            : 'Deleted content is kept until it is deleted permanently'}
+        </p>
+      </div>
+
+      {isAdmin && !isEmpty && (
+        <div className="mb-6">
+          <button
+            onClick={handleEmpty}
+            className="px-4 py-2 border-4 border-red-600 text-red-600 font-bold hover:bg-red-600 hover:text-white transition-colors uppercase text-sm"
+          >
+            Empty Trash
+          </button>
+        </div>
+      )}
+
+      {error && <div className="text-red-600 mb-4">{error}</div>}
+
+      {isLoading ? (
+        <div className="text-center py-12">Loading...</div>
+      ) : isEmpty ? (
+        <div className="card-flat text-center py-12">
+          <Icon name="trash" className="w-16 h-16 text-gray-400 mx-auto mb-4" />
+          <h3 className="text-xl font-black text-gray-900 mb-2 uppercase">Trash is empty</h3>
+          <p className="text-gray-600 font-medium">
+            Deleted items and collections show up here until they are restored or deleted permanently
+          </p>
+        </div>
+      ) : (
+        <div className="space-y-8">
+          {collections.length > 0 && (
+            <div>
+              <h3 className="text-lg font-black text-gray-900 mb-4 uppercase">Collections</h3>
+              <div className="grid gap-4">
+                {collections.map(collection => (
+                  <div key={`c${collection.id}`} className="card-flat flex justify-between items-start">
+                    <div>
+                      <h4 className="text-xl font-black uppercase">{collection.name}</h4>
+                      <p className="text-sm text-gray-600">
+                        {collection.itemCount} item(s), {collection.fieldCount} field(s)
+                      </p>
+                      <p className="text-xs text-gray-500 mt-2 font-medium uppercase">
+                        Deleted {new Date(collection.deletedAt).toLocaleString()}{purgeNote(collection.purgeAt)}
+                      </p>
+                    </div>
+                    <div className="flex space-x-2 ml-4">
+                      <button onClick={() => handleRestore('collections', collection.id)} className="btn-secondary">
+                        Restore
+                      </button>
+                      {isAdmin && (
+                        <button
+                          onClick={() => handlePurge('collections', collection.id)}
+                          className="px-4 py-2 border-4 border-red-500 text-red-500 font-bold hover:bg-red-500 hover:text-white transition-colors uppercase text-sm"
+                        >
+                          Delete Forever
+                        </button>
+                      )}
+                    </div>
+                  </div>
+                ))}
+              </div>
+            </div>
+          )}
+
+          {items.length > 0 && (
+            <div>
+              <h3 className="text-lg font-black text-gray-900 mb-4 uppercase">Items</h3>
+              <div className="grid gap-4">
+                {items.map(item => (
+                  <div key={`i${item.id}`} className="card-flat flex justify-between items-start">
+                    <div>
+                      <h4 className="text-xl font-black uppercase">{itemTitle(item)}</h4>
+                      <p className="text-sm text-gray-600">
+                        <span className="font-bold uppercase">Collection:</span> {item.collectionName}
+                        {item.collectionDeleted && ' (in the trash)'}
+                      </p>
+                      <p className="text-xs text-gray-500 mt-2 font-medium uppercase">
+                        Deleted {new Date(item.deletedAt).toLocaleString()}{purgeNote(item.purgeAt)}
+                      </p>
+                    </div>
+                    <div className="flex space-x-2 ml-4">
+                      <button
+                        onClick={() => handleRestore('items', item.id)}
+                        disabled={item.collectionDeleted}
+                        title={item.collectionDeleted ? 'Restore the collection first' : undefined}
+                        className="btn-secondary disabled:opacity-50"
+                      >
+                        Restore
+                      </button>
+                      {isAdmin && (
+                        <button
+                          onClick={() => handlePurge('items', item.id)}
+                          className="px-4 py-2 border-4 border-red-500 text-red-500 font-bold hover:bg-red-500 hover:text-white transition-colors uppercase text-sm"
+                        >
+                          Delete Forever
+                        </button>
+                      )}
+                    </div>
+                  </div>
+                ))}
+              </div>
+            </div>
+          )}
+        </div>
+      )}
+    </div>
+  );
+}
@@ -0,0 +1,549 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errStatusBypassesWorkflow is returned when an item's status is written
+// directly in a collection whose status changes go through its workflow
+var errStatusBypassesWorkflow = errors.New("status changes must go through the collection's workflow")
+
+// errTransitionNotAllowed is returned when the workflow has no transition
+// between two states for the user's role
+var errTransitionNotAllowed = errors.New("transition not allowed")
+
+// errItemStatusChanged is returned when an item left the state a transition
+// starts from before the transition was applied
+var errItemStatusChanged = errors.New("item status has changed")
+
+// Workflow defines the states items in a collection move through and which
+// roles may move them between states
+type Workflow struct {
+	States      []string             `json:"states"`
+	Initial     string               `json:"initial,omitempty"` // Defaults to the first state
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// WorkflowTransition allows items to move from one state to another
+type WorkflowTransition struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Roles []string `json:"roles,omitempty"` // Empty allows every role
+}
+
+// validate checks that the workflow is internally consistent
+func (wf *Workflow) validate() error {
+	if len(wf.States) == 0 {
+		return fmt.Errorf("a workflow needs at least one state")
+	}
+
+	seen := make(map[string]bool)
+	for _, state := range wf.States {
+		if state == "" {
+			return fmt.Errorf("state names can't be empty")
+		}
+		if seen[state] {
+			return fmt.Errorf("duplicate state %q", state)
+		}
+		seen[state] = true
+	}
+
+	if wf.Initial != "" && !seen[wf.Initial] {
+		return fmt.Errorf("initial state %q is not one of the states", wf.Initial)
+	}
+
+	pairs := make(map[string]bool)
+	for _, t := range wf.Transitions {
+		if !seen[t.From] {
+			return fmt.Errorf("transition from unknown state %q", t.From)
+		}
+		if !seen[t.To] {
+			return fmt.Errorf("transition to unknown state %q", t.To)
+		}
+		if t.From == t.To {
+			return fmt.Errorf("transition from %q to itself", t.From)
+		}
+		key := t.From + "\x00" + t.To
+		if pairs[key] {
+			return fmt.Errorf("duplicate transition from %q to %q", t.From, t.To)
+		}
+		pairs[key] = true
+	}
+
+	return nil
+}
+
+// hasState reports whether state is one of the workflow's states
+func (wf *Workflow) hasState(state string) bool {
+	for _, s := range wf.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// initialState is the state new items start in
+func (wf *Workflow) initialState() string {
+	if wf.Initial != "" {
+		return wf.Initial
+	}
+	return wf.States[0]
+}
+
+// transition returns the transition between two states, or nil if there is none
+func (wf *Workflow) transition(from, to string) *WorkflowTransition {
+	for i := range wf.Transitions {
+		if wf.Transitions[i].From == from && wf.Transitions[i].To == to {
+			return &wf.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// allows reports whether a user with role may make the transition
+func (t *WorkflowTransition) allows(role string) bool {
+	if len(t.Roles) == 0 {
+		return true
+	}
+	for _, r := range t.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// available returns the states a user with role may move an item to from
+func (wf *Workflow) available(from, role string) []string {
+	states := []string{}
+	for _, t := range wf.Transitions {
+		if t.From == from && t.allows(role) {
+			states = append(states, t.To)
+		}
+	}
+	return states
+}
+
+// ItemTransition records one move of an item between workflow states
+type ItemTransition struct {
+	ID            int
+	ItemID        int
+	FromState     string
+	ToState       string
+	Comment       sql.NullString
+	CreatedBy     sql.NullInt64
+	CreatedByName sql.NullString
+	CreatedAt     time.Time
+}
+
+// getCollectionWorkflow reads a collection's workflow using q, which may be
+// the database or a transaction. It returns nil if the collection has none.
+func getCollectionWorkflow(q interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}, collectionID int) (*Workflow, error) {
+	var definition string
+	err := q.QueryRow(`SELECT definition FROM collection_workflows WHERE collection_id = ?`, collectionID).Scan(&definition)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow: %w", err)
+	}
+
+	var wf Workflow
+	if err := json.Unmarshal([]byte(definition), &wf); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow: %w", err)
+	}
+	return &wf, nil
+}
+
+// GetCollectionWorkflow returns a collection's workflow, or nil if its item
+// statuses are unconstrained
+func (d *Database) GetCollectionWorkflow(collectionID int) (*Workflow, error) {
+	return getCollectionWorkflow(d.db, collectionID)
+}
+
+// SetCollectionWorkflow creates or replaces a collection's workflow
+func (d *Database) SetCollectionWorkflow(collectionID int, wf *Workflow) error {
+	definition, err := json.Marshal(wf)
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow: %w", err)
+	}
+
+	query := `
+		INSERT INTO collection_workflows (collection_id, definition, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (collection_id) DO UPDATE SET definition = excluded.definition, updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := d.db.Exec(query, collectionID, string(definition)); err != nil {
+		return fmt.Errorf("failed to set workflow: %w", err)
+	}
+	return nil
+}
+
+// DeleteCollectionWorkflow removes a collection's workflow, leaving item
+// statuses unconstrained again
+func (d *Database) DeleteCollectionWorkflow(collectionID int) error {
+	if _, err := d.db.Exec(`DELETE FROM collection_workflows WHERE collection_id = ?`, collectionID); err != nil {
+		return fmt.Errorf("failed to delete workflow: %w", err)
+	}
+	return nil
+}
+
+// statusesOutsideWorkflow counts the collection's items whose status is not
+// one of the workflow's states
+func (d *Database) statusesOutsideWorkflow(collectionID int, wf *Workflow) (map[string]int, error) {
+	rows, err := d.db.Query(`SELECT status, COUNT(*) FROM items WHERE collection_id = ? GROUP BY status`, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count item statuses: %w", err)
+	}
+	defer rows.Close()
+
+	outside := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan item status: %w", err)
+		}
+		if !wf.hasState(status) {
+			outside[status] = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating item statuses: %w", err)
+	}
+	return outside, nil
+}
+
+// resolveItemStatus returns the status to store when an item is created
+// (current is nil) or updated. Without a workflow the requested status is
+// used as is. With one, new items start in the initial state and updates keep
+// the current status; asking for anything else returns
+// errStatusBypassesWorkflow.
+func (d *Database) resolveItemStatus(collectionID int, current *Item, requested string) (string, error) {
+	wf, err := d.GetCollectionWorkflow(collectionID)
+	if err != nil {
+		return "", err
+	}
+	if wf == nil {
+		return requested, nil
+	}
+
+	expected := wf.initialState()
+	if current != nil {
+		expected = current.Status
+	}
+	if requested != "" && requested != expected {
+		return "", errStatusBypassesWorkflow
+	}
+	return expected, nil
+}
+
+// recordItemTransition logs a move between states. A userID of 0 means the
+// change was made by the server, e.g. the scheduler.
+func recordItemTransition(tx *sql.Tx, itemID int, from, to, comment string, userID int) error {
+	var commentParam, userParam interface{}
+	if comment != "" {
+		commentParam = comment
+	}
+	if userID > 0 {
+		userParam = userID
+	}
+
+	query := `
+		INSERT INTO item_transitions (item_id, from_state, to_state, comment, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`
+	if _, err := tx.Exec(query, itemID, from, to, commentParam, userParam); err != nil {
+		return fmt.Errorf("failed to record transition: %w", err)
+	}
+	return nil
+}
+
+// TransitionItem moves an item to another workflow state as user, provided
+// the collection's workflow allows it for the user's role
+func (d *Database) TransitionItem(item *Item, to, comment string, user *User) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	wf, err := getCollectionWorkflow(tx, item.CollectionID)
+	if err != nil {
+		return err
+	}
+	if wf == nil {
+		return errTransitionNotAllowed
+	}
+	t := wf.transition(item.Status, to)
+	if t == nil || !t.allows(user.Role) {
+		return errTransitionNotAllowed
+	}
+
+	result, err := tx.Exec(`
+		UPDATE items SET status = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ? AND deleted_at IS NULL
+	`, to, item.ID, item.Status)
+	if err != nil {
+		return fmt.Errorf("failed to update item status: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return errItemStatusChanged
+	}
+
+	if err := recordItemTransition(tx, item.ID, item.Status, to, comment, user.ID); err != nil {
+		return err
+	}
+	if err := recordItemRevision(tx, item.ID, user.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transition: %w", err)
+	}
+	return nil
+}
+
+// GetItemTransitions returns an item's workflow history, newest first
+func (d *Database) GetItemTransitions(itemID int) ([]ItemTransition, error) {
+	query := `
+		SELECT t.id, t.item_id, t.from_state, t.to_state, t.comment, t.created_by, u.username, t.created_at
+		FROM item_transitions t
+		LEFT JOIN users u ON u.id = t.created_by
+		WHERE t.item_id = ?
+		ORDER BY t.id DESC
+	`
+
+	rows, err := d.db.Query(query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transitions: %w", err)
+	}
+	defer rows.Close()
+
+	transitions := []ItemTransition{}
+	for rows.Next() {
+		var t ItemTransition
+		err := rows.Scan(&t.ID, &t.ItemID, &t.FromState, &t.ToState, &t.Comment, &t.CreatedBy, &t.CreatedByName, &t.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transition: %w", err)
+		}
+		transitions = append(transitions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transitions: %w", err)
+	}
+
+	return transitions, nil
+}
+
+// handleAdminCollectionWorkflow manages a collection's workflow:
+//
+//	GET    /admin-api/collections/{id}/workflow
+//	PUT    /admin-api/collections/{id}/workflow  (admins only)
+//	DELETE /admin-api/collections/{id}/workflow  (admins only)
+func (s *Server) handleAdminCollectionWorkflow(w http.ResponseWriter, r *http.Request, user *User, collectionID int) {
+	if r.Method != http.MethodGet && user.Role != "admin" {
+		s.sendJSONError(w, "Only admins can change workflows", http.StatusForbidden)
+		return
+	}
+
+	current, err := s.db.GetCollectionWorkflow(collectionID)
+	if err != nil {
+		log.Printf("Error getting workflow for collection %d: %v", collectionID, err)
+		s.sendJSONError(w, "Failed to get workflow", http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if current == nil {
+			s.sendJSONError(w, "Collection has no workflow", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(current)
+
+	case http.MethodPut:
+		var wf Workflow
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&wf); err != nil {
+			s.sendJSONError(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if err := wf.validate(); err != nil {
+			s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Items must already be in one of the states, or they could never move
+		outside, err := s.db.statusesOutsideWorkflow(collectionID, &wf)
+		if err != nil {
+			log.Printf("Error checking item statuses for collection %d: %v", collectionID, err)
+			s.sendJSONError(w, "Failed to check item statuses", http.StatusInternalServerError)
+			return
+		}
+		if len(outside) > 0 {
+			var statuses []string
+			for status, count := range outside {
+				statuses = append(statuses, fmt.Sprintf("%q (%d items)", status, count))
+			}
+			sort.Strings(statuses)
+			s.sendJSONError(w, "Items have statuses that are not workflow states: "+strings.Join(statuses, ", "), http.StatusConflict)
+			return
+		}
+
+		if err := s.db.SetCollectionWorkflow(collectionID, &wf); err != nil {
+			log.Printf("Error setting workflow for collection %d: %v", collectionID, err)
+			s.sendJSONError(w, "Failed to set workflow", http.StatusInternalServerError)
+			return
+		}
+
+		s.audit(r, user, "workflow.update", "collection", collectionID, current, &wf)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wf)
+
+	case http.MethodDelete:
+		if err := s.db.DeleteCollectionWorkflow(collectionID); err != nil {
+			log.Printf("Error deleting workflow for collection %d: %v", collectionID, err)
+			s.sendJSONError(w, "Failed to delete workflow", http.StatusInternalServerError)
+			return
+		}
+
+		s.audit(r, user, "workflow.delete", "collection", collectionID, current, nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Workflow deleted successfully"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// transitionResponse converts a transition to its JSON form
+func transitionResponse(t ItemTransition) map[string]interface{} {
+	response := map[string]interface{}{
+		"id":        t.ID,
+		"from":      t.FromState,
+		"to":        t.ToState,
+		"comment":   t.Comment.String,
+		"createdBy": nil,
+		"author":    nil,
+		"createdAt": t.CreatedAt.Format(time.RFC3339),
+	}
+	if t.CreatedBy.Valid {
+		response["createdBy"] = t.CreatedBy.Int64
+	}
+	if t.CreatedByName.Valid {
+		response["author"] = t.CreatedByName.String
+	}
+	return response
+}
+
+// handleAdminItemTransitions serves an item's workflow:
+//
+//	GET  /admin-api/items/{id}/transitions  (current state, allowed moves and history)
+//	POST /admin-api/items/{id}/transitions  {"to": "in_review", "comment": "..."}
+func (s *Server) handleAdminItemTransitions(w http.ResponseWriter, r *http.Request, user *User, itemID int) {
+	item, err := s.db.GetItem(itemID)
+	if err != nil {
+		log.Printf("Error getting item %d: %v", itemID, err)
+		s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+		return
+	}
+	if item == nil {
+		s.sendJSONError(w, "Item not found", http.StatusNotFound)
+		return
+	}
+
+	wf, err := s.db.GetCollectionWorkflow(item.CollectionID)
+	if err != nil {
+		log.Printf("Error getting workflow for collection %d: %v", item.CollectionID, err)
+		s.sendJSONError(w, "Failed to get workflow", http.StatusInternalServerError)
+		return
+	}
+	if wf == nil {
+		s.sendJSONError(w, "Collection has no workflow", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		transitions, err := s.db.GetItemTransitions(itemID)
+		if err != nil {
+			log.Printf("Error getting transitions for item %d: %v", itemID, err)
+			s.sendJSONError(w, "Failed to get transitions", http.StatusInternalServerError)
+			return
+		}
+
+		history := []map[string]interface{}{}
+		for _, t := range transitions {
+			history = append(history, transitionResponse(t))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":    item.Status,
+			"available": wf.available(item.Status, user.Role),
+			"history":   history,
+		})
+
+	case http.MethodPost:
+		var request struct {
+			To      string `json:"to"`
+			Comment string `json:"comment"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			s.sendJSONError(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if !wf.hasState(request.To) {
+			s.sendJSONError(w, fmt.Sprintf("Unknown state %q", request.To), http.StatusBadRequest)
+			return
+		}
+
+		err := s.db.TransitionItem(item, request.To, request.Comment, user)
+		if err == errTransitionNotAllowed {
+			s.sendJSONError(w, fmt.Sprintf("Your role can't move items from %q to %q", item.Status, request.To), http.StatusForbidden)
+			return
+		}
+		if err == errItemStatusChanged {
+			s.sendItemConflict(w, itemID)
+			return
+		}
+		if err != nil {
+			log.Printf("Error transitioning item %d: %v", itemID, err)
+			s.sendJSONError(w, "Failed to change item status", http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := s.db.GetItem(itemID)
+		if err != nil || updated == nil {
+			log.Printf("Error getting item %d after transition: %v", itemID, err)
+			s.sendJSONError(w, "Failed to get item", http.StatusInternalServerError)
+			return
+		}
+
+		s.audit(r, user, "item.transition", "item", itemID, auditItem(item), auditItem(updated))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", itemETag(updated))
+		json.NewEncoder(w).Encode(convertItemToResponse(updated))
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWorkflowTransition(t *testing.T) {
+	wf := &Workflow{
+		States: []string{"draft", "review", "published"},
+		Transitions: []WorkflowTransition{
+			{From: "draft", To: "review"},
+			{From: "review", To: "draft", Roles: []string{"editor", "admin"}},
+			{From: "review", To: "published", Roles: []string{"admin"}},
+			{From: "published", To: "draft", Roles: []string{"admin"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		from, to  string
+		role      string
+		exists    bool
+		allowed   bool
+		available []string
+	}{
+		{"open to every role", "draft", "review", "writer", true, true, []string{"review"}},
+		{"listed role", "review", "draft", "editor", true, true, []string{"draft"}},
+		{"unlisted role", "review", "published", "editor", true, false, []string{"draft"}},
+		{"admin", "review", "published", "admin", true, true, []string{"draft", "published"}},
+		{"no such transition", "draft", "published", "admin", false, false, []string{"review"}},
+		{"reverse of a transition", "review", "draft", "writer", true, false, []string{}},
+		{"unknown state", "archived", "draft", "admin", false, false, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transition := wf.transition(tt.from, tt.to)
+			if (transition != nil) != tt.exists {
+				t.Fatalf("transition(%q, %q) = %v, want exists %t", tt.from, tt.to, transition, tt.exists)
+			}
+			if transition != nil {
+				if transition.From != tt.from || transition.To != tt.to {
+					t.Errorf("transition(%q, %q) returned %s -> %s", tt.from, tt.to, transition.From, transition.To)
+				}
+				if got := transition.allows(tt.role); got != tt.allowed {
+					t.Errorf("allows(%q) = %t, want %t", tt.role, got, tt.allowed)
+				}
+			}
+			if got := wf.available(tt.from, tt.role); !reflect.DeepEqual(got, tt.available) {
+				t.Errorf("available(%q, %q) = %v, want %v", tt.from, tt.role, got, tt.available)
+			}
+		})
+	}
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wf      Workflow
+		wantErr bool
+	}{
+		{"valid", Workflow{States: []string{"draft", "published"}, Transitions: []WorkflowTransition{{From: "draft", To: "published"}}}, false},
+		{"no states", Workflow{}, true},
+		{"empty state", Workflow{States: []string{"draft", ""}}, true},
+		{"duplicate state", Workflow{States: []string{"draft", "draft"}}, true},
+		{"unknown initial state", Workflow{States: []string{"draft"}, Initial: "review"}, true},
+		{"unknown from state", Workflow{States: []string{"draft"}, Transitions: []WorkflowTransition{{From: "review", To: "draft"}}}, true},
+		{"unknown to state", Workflow{States: []string{"draft"}, Transitions: []WorkflowTransition{{From: "draft", To: "review"}}}, true},
+		{"transition to itself", Workflow{States: []string{"draft"}, Transitions: []WorkflowTransition{{From: "draft", To: "draft"}}}, true},
+		{"duplicate transition", Workflow{States: []string{"draft", "review"}, Transitions: []WorkflowTransition{{From: "draft", To: "review"}, {From: "draft", To: "review", Roles: []string{"admin"}}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.wf.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}